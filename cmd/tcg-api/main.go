@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log"
 	"log/slog"
 	"net/http"
@@ -13,6 +15,9 @@ import (
 
 	"github.com/jwebster45206/tcg-api/internal/config"
 	"github.com/jwebster45206/tcg-api/internal/handlers"
+	"github.com/jwebster45206/tcg-api/internal/jobs"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/search"
 	"github.com/jwebster45206/tcg-api/internal/storage"
 )
 
@@ -48,14 +53,56 @@ func main() {
 	logger := config.NewLogger(cfg.Logger)
 	config.SetDefaultLogger(logger)
 
+	// tcg-api migrate up|down|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(context.Background(), *cfg, os.Args[2:], os.Stdout); err != nil {
+			logger.Error("Migration failed", slog.Any("error", err))
+			os.Exit(1)
+		}
+		return
+	}
+
 	logger.Info("Starting TCG API",
 		slog.String("env", cfg.Env),
 		slog.String("port", cfg.Port))
 
+	// Apply pending migrations if configured
+	if cfg.Storage.MigrateOnStart {
+		if err := migrateOnStart(context.Background(), *cfg, logger); err != nil {
+			logger.Error("Failed to apply migrations",
+				slog.String("driver", cfg.Storage.Driver),
+				slog.Any("error", err))
+			os.Exit(1)
+		}
+	}
+
+	// Initialize storage
+	sto, err := newStorage(context.Background(), *cfg)
+	if err != nil {
+		logger.Error("Failed to initialize storage",
+			slog.String("driver", cfg.Storage.Driver),
+			slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	// Index existing cards for full-text search; writes through sto keep the
+	// index current from here on
+	index := search.NewIndex()
+	if err := index.Load(context.Background(), sto); err != nil {
+		logger.Error("Failed to build search index", slog.Any("error", err))
+		_ = closeStorage(sto)
+		os.Exit(1)
+	}
+	sto = search.WithIndex(sto, index)
+	logger.Info("Search index built", slog.Int("cards", index.Len()))
+
+	// Background jobs run in this process and end with it
+	jobManager := jobs.NewManager(cfg.Jobs.Workers, cfg.Jobs.Retain)
+
 	// Create a new HTTP server
 	server := &http.Server{
 		Addr:         ":" + cfg.Port,
-		Handler:      setupRoutes(*cfg, logger),
+		Handler:      setupRoutes(sto, index, jobManager, logger),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -86,27 +133,112 @@ func main() {
 		logger.Error("Server forced to shutdown", slog.Any("error", err))
 		os.Exit(1)
 	}
+	jobManager.Close()
+
+	if err := closeStorage(sto); err != nil {
+		logger.Error("Failed to close storage", slog.Any("error", err))
+	}
 
 	logger.Info("Server exited")
 }
 
-func setupRoutes(cfg config.Config, logger *slog.Logger) *http.ServeMux {
+// newStorage creates the storage backend selected by cfg.Storage.Driver,
+// moving deck states to Redis if cfg.Storage.DeckStates asks for it
+func newStorage(ctx context.Context, cfg config.Config) (storage.Storage, error) {
+	var base storage.Storage
+	switch cfg.Storage.Driver {
+	case "", config.StorageDriverMemory:
+		base = storage.NewMockStorage()
+	case config.StorageDriverMySQL:
+		var err error
+		if base, err = storage.NewMySQLStorage(ctx, cfg.DB); err != nil {
+			return nil, err
+		}
+	case config.StorageDriverSQLite:
+		var err error
+		if base, err = storage.NewSQLiteStorage(ctx, cfg.SQLite); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+
+	switch cfg.Storage.DeckStates {
+	case "":
+		return base, nil
+	case config.DeckStateStoreRedis:
+		states, err := storage.NewRedisDeckStateStore(ctx, cfg.Redis)
+		if err != nil {
+			_ = closeStorage(base)
+			return nil, fmt.Errorf("redis deck states: %w", err)
+		}
+		return storage.WithDeckStateStore(base, states), nil
+	default:
+		_ = closeStorage(base)
+		return nil, fmt.Errorf("unknown deck state store %q", cfg.Storage.DeckStates)
+	}
+}
+
+// closeStorage releases a storage backend's resources, if it holds any
+func closeStorage(sto storage.Storage) error {
+	if closer, ok := sto.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func setupRoutes(sto storage.Storage, index *search.Index, jobManager *jobs.Manager, logger *slog.Logger) *http.ServeMux {
 	mux := http.NewServeMux()
 
-	// TODO: Initialize storage
-	sto := storage.NewMockStorage()
+	cardsHandler := handlers.NewCardsHandler(sto, logger)
+	cardSearchHandler := handlers.NewCardSearchHandler(sto, index, logger)
 	gameCardsHandler := handlers.NewGameCardsHandler(sto, logger)
 	imageCardsHandler := handlers.NewImageCardsHandler(sto, logger)
+	playingCardsHandler := handlers.NewPlayingCardsHandler(sto, logger)
+	cardSetsHandler := handlers.NewCardSetsHandler(sto, logger)
+	gameDefinitionsHandler := handlers.NewGameDefinitionsHandler(sto, logger)
+	keywordsHandler := handlers.NewRegistryHandler(sto, models.RegistryKeywords, logger)
+	colorsHandler := handlers.NewRegistryHandler(sto, models.RegistryColors, logger)
+	goldfishHandler := handlers.NewGoldfishHandler(sto, jobManager, logger)
+	decksHandler := handlers.NewDecksHandler(sto, goldfishHandler, logger)
+	deckStatesHandler := handlers.NewDeckStatesHandler(sto, logger)
+	jobsHandler := handlers.NewJobsHandler(jobManager, logger)
 
 	// Health endpoint
 	mux.HandleFunc("/health", handlers.HealthHandler)
 
 	// Cards endpoints
+	mux.Handle("/cards", cardsHandler)
+	mux.Handle("/cards/", cardsHandler)
+	mux.Handle("/cards/search", cardSearchHandler)
+
 	mux.Handle("/game-cards", gameCardsHandler)
 	mux.Handle("/game-cards/", gameCardsHandler)
 
 	mux.Handle("/image-cards", imageCardsHandler)
 	mux.Handle("/image-cards/", imageCardsHandler)
 
+	mux.Handle("/playing-cards", playingCardsHandler)
+	mux.Handle("/playing-cards/", playingCardsHandler)
+
+	mux.Handle("/sets", cardSetsHandler)
+	mux.Handle("/sets/", cardSetsHandler)
+	mux.Handle("/game-definitions", gameDefinitionsHandler)
+	mux.Handle("/game-definitions/", gameDefinitionsHandler)
+	mux.Handle("/keywords", keywordsHandler)
+	mux.Handle("/keywords/", keywordsHandler)
+	mux.Handle("/colors", colorsHandler)
+	mux.Handle("/colors/", colorsHandler)
+
+	// Deck endpoints
+	mux.Handle("/decks", decksHandler)
+	mux.Handle("/decks/", decksHandler)
+
+	// Deck state endpoints
+	mux.Handle("/states/", deckStatesHandler)
+
+	// Background job endpoints
+	mux.Handle("/jobs/", jobsHandler)
+
 	return mux
 }
@@ -1,12 +1,19 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jwebster45206/tcg-api/internal/config"
 	"github.com/jwebster45206/tcg-api/internal/handlers"
+	"github.com/jwebster45206/tcg-api/internal/models"
 )
 
 func TestMainRoutes(t *testing.T) {
@@ -71,3 +78,78 @@ func TestServerStartup(t *testing.T) {
 		t.Error("server address should be :0")
 	}
 }
+
+func TestNewStorage(t *testing.T) {
+	ctx := context.Background()
+
+	for _, driver := range []string{"", config.StorageDriverMemory} {
+		sto, err := newStorage(ctx, config.Config{Storage: config.StorageConfig{Driver: driver}})
+		if err != nil || sto == nil {
+			t.Errorf("driver %q: expected in-memory storage, got %v", driver, err)
+		}
+	}
+
+	sqliteCfg := config.Config{
+		Storage: config.StorageConfig{Driver: config.StorageDriverSQLite},
+		SQLite:  config.SQLiteConfig{Path: filepath.Join(t.TempDir(), "tcg.db")},
+	}
+	sto, err := newStorage(ctx, sqliteCfg)
+	if err != nil {
+		t.Fatalf("driver sqlite: %v", err)
+	}
+	if err := closeStorage(sto); err != nil {
+		t.Errorf("driver sqlite: failed to close: %v", err)
+	}
+	var out bytes.Buffer
+	if err := runMigrate(ctx, sqliteCfg, []string{"status"}, &out); err != nil || !strings.Contains(out.String(), "create_tables") {
+		t.Errorf("migrate status: got %q, err %v", out.String(), err)
+	}
+
+	if _, err := newStorage(ctx, config.Config{Storage: config.StorageConfig{Driver: "oracle"}}); err == nil {
+		t.Error("expected an error for an unknown storage driver")
+	}
+	if _, err := newStorage(ctx, config.Config{Storage: config.StorageConfig{DeckStates: "memcached"}}); err == nil {
+		t.Error("expected an error for an unknown deck state store")
+	}
+}
+
+func TestNewStorage_RedisDeckStates(t *testing.T) {
+	server := miniredis.RunT(t)
+	cfg := config.Config{
+		Storage: config.StorageConfig{DeckStates: config.DeckStateStoreRedis},
+		Redis:   config.RedisConfig{Addr: server.Addr(), KeyPrefix: "tcg:"},
+	}
+
+	sto, err := newStorage(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("newStorage: %v", err)
+	}
+	defer func() { _ = closeStorage(sto) }()
+
+	state, err := sto.CreateDeckState(context.Background(), models.DeckState{})
+	if err != nil {
+		t.Fatalf("CreateDeckState: %v", err)
+	}
+	if !server.Exists("tcg:deck-state:" + state.ID.String()) {
+		t.Error("expected deck states to be stored in Redis")
+	}
+}
+
+func TestRunMigrate_InvalidUsage(t *testing.T) {
+	ctx := context.Background()
+	mysqlCfg := config.Config{Storage: config.StorageConfig{Driver: config.StorageDriverMySQL}}
+
+	for _, args := range [][]string{nil, {"sideways"}, {"up", "extra"}} {
+		var out bytes.Buffer
+		if err := runMigrate(ctx, mysqlCfg, args, &out); err == nil || err.Error() != migrateUsage {
+			t.Errorf("args %q: expected usage error, got %v", args, err)
+		}
+	}
+
+	// The in-memory store has no schema to migrate
+	var out bytes.Buffer
+	memoryCfg := config.Config{Storage: config.StorageConfig{Driver: config.StorageDriverMemory}}
+	if err := runMigrate(ctx, memoryCfg, []string{"status"}, &out); err == nil {
+		t.Error("expected an error migrating in-memory storage")
+	}
+}
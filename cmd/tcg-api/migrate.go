@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jwebster45206/tcg-api/internal/config"
+	"github.com/jwebster45206/tcg-api/internal/migrations"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+const migrateUsage = "usage: tcg-api migrate up|down|status"
+
+// runMigrate handles the migrate subcommand, writing results to out
+func runMigrate(ctx context.Context, cfg config.Config, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+
+	var run func(m *migrations.Migrator) error
+	switch args[0] {
+	case "up":
+		run = func(m *migrations.Migrator) error {
+			applied, err := m.Up(ctx)
+			for _, migration := range applied {
+				_, _ = fmt.Fprintf(out, "applied %d_%s\n", migration.Version, migration.Name)
+			}
+			if err == nil && len(applied) == 0 {
+				_, _ = fmt.Fprintln(out, "no pending migrations")
+			}
+			return err
+		}
+	case "down":
+		run = func(m *migrations.Migrator) error {
+			rolledBack, err := m.Down(ctx)
+			if err != nil {
+				return err
+			}
+			if rolledBack == nil {
+				_, _ = fmt.Fprintln(out, "no migrations to roll back")
+				return nil
+			}
+			_, _ = fmt.Fprintf(out, "rolled back %d_%s\n", rolledBack.Version, rolledBack.Name)
+			return nil
+		}
+	case "status":
+		run = func(m *migrations.Migrator) error {
+			statuses, err := m.Status(ctx)
+			if err != nil {
+				return err
+			}
+			tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+			for _, status := range statuses {
+				appliedAt := "pending"
+				if status.Applied {
+					appliedAt = status.AppliedAt.Format(time.RFC3339)
+				}
+				_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+			}
+			return tw.Flush()
+		}
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	return withMigrator(ctx, cfg, run)
+}
+
+// migrateOnStart applies pending migrations before the server starts
+func migrateOnStart(ctx context.Context, cfg config.Config, logger *slog.Logger) error {
+	return withMigrator(ctx, cfg, func(m *migrations.Migrator) error {
+		applied, err := m.Up(ctx)
+		for _, migration := range applied {
+			logger.Info("Applied migration",
+				slog.Int("version", migration.Version),
+				slog.String("name", migration.Name))
+		}
+		return err
+	})
+}
+
+// withMigrator opens the configured SQL database and runs fn with a Migrator
+// for it
+func withMigrator(ctx context.Context, cfg config.Config, fn func(m *migrations.Migrator) error) error {
+	var dsn string
+	switch cfg.Storage.Driver {
+	case config.StorageDriverMySQL:
+		dsn = storage.MySQLDSN(cfg.DB)
+	case config.StorageDriverSQLite:
+		dsn = storage.SQLiteDSN(cfg.SQLite)
+	default:
+		return fmt.Errorf("storage driver %q does not use migrations", cfg.Storage.Driver)
+	}
+
+	db, err := sql.Open(cfg.Storage.Driver, dsn)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := db.PingContext(ctx); err != nil {
+		return err
+	}
+	m, err := migrations.NewForDriver(db, cfg.Storage.Driver)
+	if err != nil {
+		return err
+	}
+	return fn(m)
+}
@@ -8,9 +8,50 @@ type MySQLConfig struct {
 	DBName   string
 }
 
+// SQLiteConfig describes the database file used by the sqlite driver
+type SQLiteConfig struct {
+	Path string `json:"path"` // e.g. "tcg.db"; ":memory:" keeps data in process
+}
+
+// RedisConfig describes the Redis server used for live deck states
+type RedisConfig struct {
+	Addr      string `json:"addr"`
+	Password  string `json:"password"`
+	DB        int    `json:"db"`
+	KeyPrefix string `json:"key_prefix"`
+	TTL       string `json:"ttl"` // Go duration, e.g. "24h"; empty or "0" never expires
+}
+
+// Storage drivers selectable with StorageConfig.Driver
+const (
+	StorageDriverMemory = "memory"
+	StorageDriverMySQL  = "mysql"
+	StorageDriverSQLite = "sqlite"
+)
+
+// DeckStateStoreRedis selects Redis with StorageConfig.DeckStates
+const DeckStateStoreRedis = "redis"
+
+// StorageConfig selects the storage backend
+type StorageConfig struct {
+	Driver         string `json:"driver"`           // "memory" (default), "mysql" or "sqlite"
+	MigrateOnStart bool   `json:"migrate_on_start"` // apply pending migrations before serving
+	DeckStates     string `json:"deck_states"`      // "" keeps deck states with Driver, "redis" uses Redis
+}
+
+// JobsConfig sizes the background jobs, such as goldfish simulations
+type JobsConfig struct {
+	Workers int `json:"workers"` // jobs run at once; 0 uses jobs.DefaultWorkers
+	Retain  int `json:"retain"`  // jobs kept for polling; 0 uses jobs.DefaultRetain
+}
+
 type Config struct {
-	Env    string       `json:"env"`
-	Port   string       `json:"port"`
-	DB     MySQLConfig  `json:"db"`
-	Logger LoggerConfig `json:"logger"`
+	Env     string        `json:"env"`
+	Port    string        `json:"port"`
+	Storage StorageConfig `json:"storage"`
+	DB      MySQLConfig   `json:"db"`
+	SQLite  SQLiteConfig  `json:"sqlite"`
+	Redis   RedisConfig   `json:"redis"`
+	Jobs    JobsConfig    `json:"jobs"`
+	Logger  LoggerConfig  `json:"logger"`
 }
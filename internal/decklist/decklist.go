@@ -0,0 +1,376 @@
+// Package decklist reads and writes the deck lists players paste between
+// deck building tools: plain text such as "4 Fire Bolt", CSV and JSON. A
+// list names its cards rather than identifying them; resolving the names to
+// cards is up to the caller.
+package decklist
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jwebster45206/tcg-api/internal/models"
+)
+
+// Formats of a deck list
+const (
+	FormatText = "text"
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Formats lists the supported formats
+var Formats = []string{FormatText, FormatCSV, FormatJSON}
+
+// ContentTypes maps each format to its media type
+var ContentTypes = map[string]string{
+	FormatText: "text/plain",
+	FormatCSV:  "text/csv",
+	FormatJSON: "application/json",
+}
+
+// List is a deck list
+type List struct {
+	// Name is the name of the deck. Only the JSON format carries it.
+	Name  string `json:"name,omitempty"`
+	Cards []Line `json:"cards"`
+}
+
+// Line is a number of copies of a named card in one section of a deck
+type Line struct {
+	Quantity int    `json:"quantity"`
+	Name     string `json:"name"`
+	Section  string `json:"section"`
+}
+
+// ParseError describes a deck list that could not be read. Line is the
+// 1-based line of the input it was found on, or 0 if it is not about one
+// line.
+type ParseError struct {
+	Line    int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// sectionNames maps the section names and headers other tools use, in lower
+// case, to deck sections
+var sectionNames = map[string]string{
+	"main":        models.SectionMain,
+	"mainboard":   models.SectionMain,
+	"main deck":   models.SectionMain,
+	"deck":        models.SectionMain,
+	"sideboard":   models.SectionSideboard,
+	"side":        models.SectionSideboard,
+	"sb":          models.SectionSideboard,
+	"maybeboard":  models.SectionMaybeboard,
+	"maybe":       models.SectionMaybeboard,
+	"considering": models.SectionMaybeboard,
+	"commander":   models.SectionCommander,
+	"commanders":  models.SectionCommander,
+}
+
+// section returns the deck section a name refers to. An empty name is the
+// main section.
+func section(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return models.SectionMain, true
+	}
+	s, ok := sectionNames[name]
+	return s, ok
+}
+
+// Parse reads a deck list in the given format
+func Parse(format string, data []byte) (*List, error) {
+	// Spreadsheets often start text files with a byte order mark
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	var list *List
+	var err error
+	switch format {
+	case FormatText:
+		list, err = parseText(data)
+	case FormatCSV:
+		list, err = parseCSV(data)
+	case FormatJSON:
+		list, err = parseJSON(data)
+	default:
+		return nil, fmt.Errorf("unknown deck list format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Cards) == 0 {
+		return nil, &ParseError{Message: "deck list has no cards"}
+	}
+	return list, nil
+}
+
+var (
+	// header matches a section header such as "Sideboard", "// Sideboard"
+	// or "Sideboard (15):"
+	header = regexp.MustCompile(`^(?://\s*)?([A-Za-z][A-Za-z ]*?)\s*(?:\(\d+\))?\s*:?$`)
+	// cardLine matches "4 Fire Bolt", "4x Fire Bolt" or just "Fire Bolt",
+	// optionally followed by a set code and collector number as in
+	// "4 Fire Bolt (CORE) 12"
+	cardLine = regexp.MustCompile(`^(?:(\d+)[xX]?\s+)?(.+?)(?:\s+\([A-Za-z0-9]+\)(?:\s+\S+)?)?$`)
+)
+
+// parseText reads the plain text format: one card per line, with section
+// headers such as "Sideboard" between sections and "SB:" marking single
+// sideboard lines. Blank lines and comments starting with # or // are
+// skipped.
+func parseText(data []byte) (*List, error) {
+	list := &List{Cards: []Line{}}
+	current := models.SectionMain
+	for i, text := range strings.Split(string(data), "\n") {
+		n := i + 1
+		text = strings.TrimSpace(text)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if m := header.FindStringSubmatch(text); m != nil {
+			if s, ok := section(m[1]); ok {
+				current = s
+				continue
+			}
+		}
+		if strings.HasPrefix(text, "//") {
+			continue
+		}
+
+		lineSection := current
+		if rest, ok := cutPrefixFold(text, "SB:"); ok {
+			lineSection = models.SectionSideboard
+			text = strings.TrimSpace(rest)
+		}
+		m := cardLine.FindStringSubmatch(text)
+		if m == nil {
+			return nil, &ParseError{Line: n, Message: fmt.Sprintf("cannot read %q", text)}
+		}
+		quantity := 1
+		if m[1] != "" {
+			q, err := parseQuantity(m[1])
+			if err != nil {
+				return nil, &ParseError{Line: n, Message: err.Error()}
+			}
+			quantity = q
+		}
+		list.Cards = append(list.Cards, Line{Quantity: quantity, Name: m[2], Section: lineSection})
+	}
+	return list, nil
+}
+
+// cutPrefixFold is strings.CutPrefix ignoring case
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		return s[len(prefix):], true
+	}
+	return s, false
+}
+
+// parseQuantity reads a number of copies
+func parseQuantity(raw string) (int, error) {
+	q, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || q < 1 || q > models.MaxQuantity {
+		return 0, fmt.Errorf("quantity must be a number between 1 and %d", models.MaxQuantity)
+	}
+	return q, nil
+}
+
+// csvColumns maps the accepted CSV headers, in lower case, to the field
+// they hold
+var csvColumns = map[string]string{
+	"quantity": "quantity",
+	"count":    "quantity",
+	"qty":      "quantity",
+	"name":     "name",
+	"card":     "name",
+	"section":  "section",
+	"board":    "section",
+}
+
+// parseCSV reads the CSV format: a header row naming a name column and
+// optionally quantity and section columns, then one card per row. Other
+// columns are ignored.
+func parseCSV(data []byte) (*List, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	headers, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return &List{Cards: []Line{}}, nil
+	}
+	if err != nil {
+		return nil, csvError(err)
+	}
+	columns := make(map[string]int)
+	for i, h := range headers {
+		field, ok := csvColumns[strings.ToLower(strings.TrimSpace(h))]
+		if _, dup := columns[field]; ok && !dup {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, &ParseError{Line: 1, Message: "the header row has no name column"}
+	}
+
+	list := &List{Cards: []Line{}}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return list, nil
+		}
+		if err != nil {
+			return nil, csvError(err)
+		}
+		n, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		line := Line{Quantity: 1, Name: field("name")}
+		if line.Name == "" {
+			if slices.ContainsFunc(record, func(v string) bool { return strings.TrimSpace(v) != "" }) {
+				return nil, &ParseError{Line: n, Message: "name is required"}
+			}
+			continue
+		}
+		if raw := field("quantity"); raw != "" {
+			if line.Quantity, err = parseQuantity(raw); err != nil {
+				return nil, &ParseError{Line: n, Message: err.Error()}
+			}
+		}
+		var ok bool
+		if line.Section, ok = section(field("section")); !ok {
+			return nil, &ParseError{Line: n, Message: sectionMessage(field("section"))}
+		}
+		list.Cards = append(list.Cards, line)
+	}
+}
+
+// csvError converts an error of encoding/csv
+func csvError(err error) error {
+	var csvErr *csv.ParseError
+	if errors.As(err, &csvErr) {
+		return &ParseError{Line: csvErr.Line, Message: csvErr.Err.Error()}
+	}
+	return err
+}
+
+// parseJSON reads the JSON format, a List. Quantity defaults to 1 and
+// section to main.
+func parseJSON(data []byte) (*List, error) {
+	var list List
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&list); err != nil {
+		return nil, &ParseError{Message: "invalid JSON: " + strings.TrimPrefix(err.Error(), "json: ")}
+	}
+	if decoder.More() {
+		return nil, &ParseError{Message: "invalid JSON: unexpected data after the deck list"}
+	}
+
+	if list.Cards == nil {
+		list.Cards = []Line{}
+	}
+	for i := range list.Cards {
+		line := &list.Cards[i]
+		line.Name = strings.TrimSpace(line.Name)
+		if line.Name == "" {
+			return nil, &ParseError{Message: fmt.Sprintf("cards[%d]: name is required", i)}
+		}
+		if line.Quantity == 0 {
+			line.Quantity = 1
+		}
+		if line.Quantity < 1 || line.Quantity > models.MaxQuantity {
+			return nil, &ParseError{Message: fmt.Sprintf("cards[%d]: quantity must be between 1 and %d", i, models.MaxQuantity)}
+		}
+		s, ok := section(line.Section)
+		if !ok {
+			return nil, &ParseError{Message: fmt.Sprintf("cards[%d]: %s", i, sectionMessage(line.Section))}
+		}
+		line.Section = s
+	}
+	return &list, nil
+}
+
+// sectionMessage describes an unknown section
+func sectionMessage(name string) string {
+	return fmt.Sprintf("unknown section %q; expected one of: %s", name, strings.Join(models.DeckSections, ", "))
+}
+
+// Write writes a deck list in the given format. The text and CSV formats
+// list the sections in the order of models.DeckSections; the text format
+// puts a header before each section but the main one, so that Parse reads
+// it back.
+func Write(w io.Writer, format string, list *List) error {
+	switch format {
+	case FormatText:
+		return writeText(w, list)
+	case FormatCSV:
+		return writeCSV(w, list)
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(list)
+	default:
+		return fmt.Errorf("unknown deck list format %q", format)
+	}
+}
+
+// sorted returns the lines of a list grouped by section, in the order of
+// models.DeckSections
+func sorted(list *List) []Line {
+	lines := slices.Clone(list.Cards)
+	slices.SortStableFunc(lines, func(a, b Line) int {
+		return slices.Index(models.DeckSections, a.Section) - slices.Index(models.DeckSections, b.Section)
+	})
+	return lines
+}
+
+func writeText(w io.Writer, list *List) error {
+	var buf bytes.Buffer
+	current := models.SectionMain
+	for _, line := range sorted(list) {
+		if line.Section != current {
+			current = line.Section
+			if buf.Len() > 0 {
+				buf.WriteString("\n")
+			}
+			buf.WriteString(strings.ToUpper(current[:1]) + current[1:] + "\n")
+		}
+		fmt.Fprintf(&buf, "%d %s\n", line.Quantity, line.Name)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeCSV(w io.Writer, list *List) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"quantity", "name", "section"}); err != nil {
+		return err
+	}
+	for _, line := range sorted(list) {
+		if err := writer.Write([]string{strconv.Itoa(line.Quantity), line.Name, line.Section}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,147 @@
+package decklist
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jwebster45206/tcg-api/internal/models"
+)
+
+func TestParse_Text(t *testing.T) {
+	input := "\ufeff# Burn\r\n" +
+		"4 Fire Bolt\r\n" +
+		"2x Ancient Wyrm (CORE) 12\n" +
+		"Mountain\n" +
+		"SB: 1 Frost Ward\n" +
+		"\n" +
+		"// Sideboard (3)\n" +
+		"2 Frost Ward\n" +
+		"Commander:\n" +
+		"1 Queen of Embers\n"
+	list, err := Parse(FormatText, []byte(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []Line{
+		{4, "Fire Bolt", models.SectionMain},
+		{2, "Ancient Wyrm", models.SectionMain},
+		{1, "Mountain", models.SectionMain},
+		{1, "Frost Ward", models.SectionSideboard},
+		{2, "Frost Ward", models.SectionSideboard},
+		{1, "Queen of Embers", models.SectionCommander},
+	}
+	if !reflect.DeepEqual(list.Cards, want) {
+		t.Errorf("Expected %+v, got %+v", want, list.Cards)
+	}
+}
+
+func TestParse_CSV(t *testing.T) {
+	input := "Count,Name,Set,Board\n" +
+		"4,Fire Bolt,CORE,\n" +
+		",\"Wyrm, Ancient\",CORE,main\n" +
+		",,,\n" +
+		"2,Frost Ward,CORE,Sideboard\n"
+	list, err := Parse(FormatCSV, []byte(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []Line{
+		{4, "Fire Bolt", models.SectionMain},
+		{1, "Wyrm, Ancient", models.SectionMain},
+		{2, "Frost Ward", models.SectionSideboard},
+	}
+	if !reflect.DeepEqual(list.Cards, want) {
+		t.Errorf("Expected %+v, got %+v", want, list.Cards)
+	}
+}
+
+func TestParse_JSON(t *testing.T) {
+	input := `{"name": "Burn", "cards": [{"quantity": 4, "name": " Fire Bolt "}, {"name": "Frost Ward", "section": "SB"}]}`
+	list, err := Parse(FormatJSON, []byte(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := &List{Name: "Burn", Cards: []Line{
+		{4, "Fire Bolt", models.SectionMain},
+		{1, "Frost Ward", models.SectionSideboard},
+	}}
+	if !reflect.DeepEqual(list, want) {
+		t.Errorf("Expected %+v, got %+v", want, list)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name, format, input string
+		wantLine            int
+		wantMessage         string
+	}{
+		{"empty text", FormatText, "# nothing\n\n", 0, "no cards"},
+		{"zero copies", FormatText, "4 Fire Bolt\n0 Frost Ward", 2, "quantity"},
+		{"too many copies", FormatText, "1000 Fire Bolt", 1, "quantity"},
+		{"no name column", FormatCSV, "quantity,card name\n4,Fire Bolt", 1, "no name column"},
+		{"bad CSV quantity", FormatCSV, "quantity,name\n4,Fire Bolt\nfour,Frost Ward", 3, "quantity"},
+		{"bad CSV section", FormatCSV, "name,section\nFire Bolt,graveyard", 2, "unknown section"},
+		{"missing CSV name", FormatCSV, "quantity,name\n4,", 2, "name is required"},
+		{"bad CSV quoting", FormatCSV, "name\n\"Fire Bolt", 2, "quote"},
+		{"unknown JSON field", FormatJSON, `{"cards": [], "owner": "me"}`, 0, "unknown field"},
+		{"trailing JSON", FormatJSON, `{"cards": [{"name": "Fire Bolt"}]} {}`, 0, "unexpected data"},
+		{"negative JSON quantity", FormatJSON, `{"cards": [{"name": "Fire Bolt", "quantity": -1}]}`, 0, "cards[0]: quantity"},
+		{"empty JSON name", FormatJSON, `{"cards": [{"quantity": 2}]}`, 0, "cards[0]: name"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.format, []byte(tt.input))
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: expected a ParseError, got %v", tt.name, err)
+			continue
+		}
+		if parseErr.Line != tt.wantLine || !strings.Contains(parseErr.Message, tt.wantMessage) {
+			t.Errorf("%s: expected line %d and %q, got %v", tt.name, tt.wantLine, tt.wantMessage, parseErr)
+		}
+	}
+
+	if _, err := Parse("xml", []byte("<deck/>")); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestWrite_RoundTrip(t *testing.T) {
+	list := &List{Name: "Burn", Cards: []Line{
+		{2, "Frost Ward", models.SectionSideboard},
+		{4, "Fire Bolt", models.SectionMain},
+		{1, "Queen of Embers", models.SectionCommander},
+		{2, "Wyrm, Ancient", models.SectionMain},
+	}}
+	grouped := []Line{list.Cards[1], list.Cards[3], list.Cards[0], list.Cards[2]}
+
+	var text bytes.Buffer
+	if err := Write(&text, FormatText, list); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	wantText := "4 Fire Bolt\n2 Wyrm, Ancient\n\nSideboard\n2 Frost Ward\n\nCommander\n1 Queen of Embers\n"
+	if text.String() != wantText {
+		t.Errorf("Expected text %q, got %q", wantText, text.String())
+	}
+
+	for _, format := range Formats {
+		var buf bytes.Buffer
+		if err := Write(&buf, format, list); err != nil {
+			t.Fatalf("Write %s: %v", format, err)
+		}
+		parsed, err := Parse(format, buf.Bytes())
+		if err != nil {
+			t.Fatalf("Parse %s: %v\n%s", format, err, buf.String())
+		}
+		want := grouped
+		if format == FormatJSON {
+			want = list.Cards
+		}
+		if !reflect.DeepEqual(parsed.Cards, want) {
+			t.Errorf("%s: expected %+v, got %+v", format, want, parsed.Cards)
+		}
+	}
+}
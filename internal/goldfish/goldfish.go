@@ -0,0 +1,269 @@
+// Package goldfish plays a deck against an opponent who does nothing, many
+// times over, to measure how it opens: how often it mulligans, how quickly it
+// finds its resources and on which turn it can first cast cards of each cost.
+//
+// Every game is shuffled with shuffle.Shuffle from a seed derived from the
+// run's seed, so a run with the same deck, settings and seed always gives the
+// same result.
+package goldfish
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/shuffle"
+)
+
+// Limits and defaults of Settings
+const (
+	MaxGames        = 100000
+	MaxTurns        = 30
+	DefaultGames    = 1000
+	DefaultTurns    = 10
+	DefaultHandSize = 7
+)
+
+// Card is one copy of a card in the simulated deck
+type Card struct {
+	ID       uuid.UUID
+	Cost     int
+	Resource bool
+	// Castable is false for cards that cannot be cast, such as cards that
+	// are not game cards
+	Castable bool
+}
+
+// Settings configure a run
+type Settings struct {
+	// Games is the number of games to play
+	Games int `json:"games"`
+	// Seed derives the shuffle of every game; empty picks a random one
+	Seed string `json:"seed"`
+	// HandSize is the size of the opening hand before mulligans
+	HandSize int `json:"hand_size"`
+	// Turns is the number of turns played in each game
+	Turns int `json:"turns"`
+	// OnTheDraw draws a card on the first turn too
+	OnTheDraw bool           `json:"on_the_draw"`
+	Mulligan  MulliganPolicy `json:"mulligan"`
+}
+
+// MulliganPolicy decides which opening hands to keep. A hand with fewer than
+// MinResources or more than MaxResources resource cards is shuffled back and
+// a new hand of one card fewer drawn, up to MaxMulligans times. A zero
+// MaxResources means no upper bound.
+type MulliganPolicy struct {
+	MinResources int `json:"min_resources"`
+	MaxResources int `json:"max_resources"`
+	MaxMulligans int `json:"max_mulligans"`
+}
+
+// Defaults fills in the zero fields of the settings
+func (s *Settings) Defaults() {
+	if s.Games == 0 {
+		s.Games = DefaultGames
+	}
+	if s.Seed == "" {
+		s.Seed = shuffle.NewSeed()
+	}
+	if s.HandSize == 0 {
+		s.HandSize = DefaultHandSize
+	}
+	if s.Turns == 0 {
+		s.Turns = DefaultTurns
+	}
+}
+
+// Validate checks the settings for a deck of deckSize cards, returning a
+// FieldError for each problem keyed by its JSON field
+func (s *Settings) Validate(deckSize int) []models.FieldError {
+	var errs []models.FieldError
+	add := func(field, format string, args ...any) {
+		errs = append(errs, models.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Games < 1 || s.Games > MaxGames {
+		add("games", "must be between 1 and %d", MaxGames)
+	}
+	if s.Turns < 1 || s.Turns > MaxTurns {
+		add("turns", "must be between 1 and %d", MaxTurns)
+	}
+	if s.HandSize < 1 || s.HandSize > deckSize {
+		add("hand_size", "must be between 1 and the deck's %d cards", deckSize)
+	}
+
+	policy := s.Mulligan
+	if policy.MinResources < 0 {
+		add("mulligan.min_resources", "cannot be negative")
+	}
+	if policy.MaxResources < 0 {
+		add("mulligan.max_resources", "cannot be negative")
+	}
+	if policy.MaxMulligans < 0 {
+		add("mulligan.max_mulligans", "cannot be negative")
+	}
+	if policy.MaxResources > 0 && policy.MinResources > policy.MaxResources {
+		add("mulligan.min_resources", "is above max_resources")
+	}
+	if policy.MaxMulligans >= s.HandSize {
+		add("mulligan.max_mulligans", "must be less than hand_size")
+	}
+	return errs
+}
+
+// Result summarizes a run
+type Result struct {
+	Games int    `json:"games"`
+	Seed  string `json:"seed"`
+	// Mulligans[k] counts the games that took k mulligans
+	Mulligans []int `json:"mulligans"`
+	// FirstCastable holds, for each cost of the deck's castable cards, the
+	// turn on which a card of that cost could first be cast
+	FirstCastable []CostTurns `json:"first_castable"`
+	// Resources[t] is the mean number of resources in play after turn t+1,
+	// and Spent[t] the mean cost of the cards cast on it
+	Resources []float64 `json:"resources"`
+	Spent     []float64 `json:"spent"`
+}
+
+// CostTurns is the distribution of the turn on which a card of one cost was
+// first castable: in hand with enough resources in play to pay for it
+type CostTurns struct {
+	Cost int `json:"cost"`
+	// Turns[t] counts the games in which it was first castable on turn t+1
+	Turns []int `json:"turns"`
+	// Never counts the games in which it never was
+	Never int `json:"never"`
+	// MeanTurn is the mean first turn over the games in which it was
+	// castable, or zero if it never was
+	MeanTurn float64 `json:"mean_turn"`
+}
+
+// Run plays the games of a run, calling progress with the number of games
+// played so far. It stops early with ctx.Err() if ctx is done. The settings
+// must have their defaults filled in and be valid for the deck.
+func Run(ctx context.Context, deck []Card, settings Settings, progress func(done int)) (*Result, error) {
+	var costs []int
+	for _, card := range deck {
+		if card.Castable && !card.Resource && !slices.Contains(costs, card.Cost) {
+			costs = append(costs, card.Cost)
+		}
+	}
+	slices.Sort(costs)
+
+	result := &Result{
+		Games:         settings.Games,
+		Seed:          settings.Seed,
+		Mulligans:     make([]int, settings.Mulligan.MaxMulligans+1),
+		FirstCastable: make([]CostTurns, len(costs)),
+		Resources:     make([]float64, settings.Turns),
+		Spent:         make([]float64, settings.Turns),
+	}
+	for i, cost := range costs {
+		result.FirstCastable[i] = CostTurns{Cost: cost, Turns: make([]int, settings.Turns)}
+	}
+
+	library := make([]Card, len(deck))
+	for game := range settings.Games {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		play(deck, library, settings, settings.Seed+":"+strconv.Itoa(game), costs, result)
+		if progress != nil {
+			progress(game + 1)
+		}
+	}
+
+	for i := range result.FirstCastable {
+		turns := &result.FirstCastable[i]
+		castable, sum := 0, 0
+		for t, n := range turns.Turns {
+			castable += n
+			sum += (t + 1) * n
+		}
+		turns.Never = settings.Games - castable
+		if castable > 0 {
+			turns.MeanTurn = float64(sum) / float64(castable)
+		}
+	}
+	for t := range settings.Turns {
+		result.Resources[t] /= float64(settings.Games)
+		result.Spent[t] /= float64(settings.Games)
+	}
+	return result, nil
+}
+
+// play plays one game, adding its outcome to result. library is scratch
+// space the size of deck.
+func play(deck, library []Card, settings Settings, seed string, costs []int, result *Result) {
+	// Draw opening hands until one is kept
+	policy := settings.Mulligan
+	var hand []Card
+	mulligans := 0
+	for {
+		copy(library, deck)
+		shuffle.Shuffle(seed+":"+strconv.Itoa(mulligans), library)
+		size := settings.HandSize - mulligans
+		hand = append(hand[:0], library[:size]...)
+		if mulligans == policy.MaxMulligans || keep(hand, policy) {
+			library = library[size:]
+			break
+		}
+		mulligans++
+	}
+	result.Mulligans[mulligans]++
+
+	found := make([]bool, len(costs))
+	resources := 0
+	for turn := range settings.Turns {
+		if (turn > 0 || settings.OnTheDraw) && len(library) > 0 {
+			hand = append(hand, library[0])
+			library = library[1:]
+		}
+
+		// Play one resource card a turn
+		if i := slices.IndexFunc(hand, func(c Card) bool { return c.Resource }); i >= 0 {
+			hand = slices.Delete(hand, i, i+1)
+			resources++
+		}
+		result.Resources[turn] += float64(resources)
+
+		for i, cost := range costs {
+			if !found[i] && cost <= resources && slices.ContainsFunc(hand, func(c Card) bool {
+				return c.Castable && !c.Resource && c.Cost == cost
+			}) {
+				found[i] = true
+				result.FirstCastable[i].Turns[turn]++
+			}
+		}
+
+		// Cast the most expensive cards that can be paid for
+		slices.SortStableFunc(hand, func(a, b Card) int { return b.Cost - a.Cost })
+		available := resources
+		kept := hand[:0]
+		for _, card := range hand {
+			if card.Castable && !card.Resource && card.Cost <= available {
+				available -= card.Cost
+				result.Spent[turn] += float64(card.Cost)
+				continue
+			}
+			kept = append(kept, card)
+		}
+		hand = kept
+	}
+}
+
+// keep reports whether the policy keeps a hand
+func keep(hand []Card, policy MulliganPolicy) bool {
+	resources := 0
+	for _, card := range hand {
+		if card.Resource {
+			resources++
+		}
+	}
+	return resources >= policy.MinResources && (policy.MaxResources == 0 || resources <= policy.MaxResources)
+}
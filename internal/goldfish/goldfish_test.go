@@ -0,0 +1,152 @@
+package goldfish
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// testDeck returns a deck of resources and castable cards of the given costs
+func testDeck(resources int, costs ...int) []Card {
+	var deck []Card
+	for range resources {
+		deck = append(deck, Card{ID: uuid.New(), Resource: true})
+	}
+	for _, cost := range costs {
+		deck = append(deck, Card{ID: uuid.New(), Cost: cost, Castable: true})
+	}
+	return deck
+}
+
+func TestRun_Reproducible(t *testing.T) {
+	deck := testDeck(17, 1, 1, 1, 1, 2, 2, 2, 2, 3, 3, 3, 3, 4, 4, 5, 5, 6, 6, 6, 6, 6, 6, 6)
+	settings := Settings{Games: 200, Seed: "fixed", Mulligan: MulliganPolicy{MinResources: 2, MaxResources: 5, MaxMulligans: 2}}
+	settings.Defaults()
+
+	first, err := Run(context.Background(), deck, settings, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	second, err := Run(context.Background(), deck, settings, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected the same seed to give the same result:\n%+v\n%+v", first, second)
+	}
+
+	settings.Seed = "other"
+	third, err := Run(context.Background(), deck, settings, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if reflect.DeepEqual(first.FirstCastable, third.FirstCastable) {
+		t.Error("Expected another seed to give another result")
+	}
+
+	// Every game is counted once in each distribution
+	mulligans := 0
+	for _, n := range first.Mulligans {
+		mulligans += n
+	}
+	if mulligans != settings.Games || len(first.Mulligans) != 3 {
+		t.Errorf("Expected %d games across 3 mulligan counts, got %v", settings.Games, first.Mulligans)
+	}
+	if len(first.FirstCastable) != 6 || first.FirstCastable[0].Cost != 1 || first.FirstCastable[5].Cost != 6 {
+		t.Fatalf("Expected costs 1 to 6, got %+v", first.FirstCastable)
+	}
+	for _, turns := range first.FirstCastable {
+		games := turns.Never
+		for _, n := range turns.Turns {
+			games += n
+		}
+		if games != settings.Games {
+			t.Errorf("cost %d: expected %d games, got %d", turns.Cost, settings.Games, games)
+		}
+	}
+	// A 6-cost card needs six resources, one played per turn
+	for turn, n := range first.FirstCastable[5].Turns[:5] {
+		if n != 0 {
+			t.Errorf("Expected no 6-cost card castable on turn %d, got %d games", turn+1, n)
+		}
+	}
+}
+
+func TestRun_Turns(t *testing.T) {
+	// With only resources one is played every turn and nothing is cast
+	settings := Settings{Games: 10, Seed: "lands", Turns: 5}
+	settings.Defaults()
+	result, err := Run(context.Background(), testDeck(20), settings, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if want := []float64{1, 2, 3, 4, 5}; !reflect.DeepEqual(result.Resources, want) {
+		t.Errorf("Expected resources %v, got %v", want, result.Resources)
+	}
+	if want := []float64{0, 0, 0, 0, 0}; !reflect.DeepEqual(result.Spent, want) || len(result.FirstCastable) != 0 {
+		t.Errorf("Expected nothing cast, got %v and %+v", result.Spent, result.FirstCastable)
+	}
+
+	// Free cards are cast on the first turn, and a hand without resources
+	// is always sent back
+	settings.Mulligan = MulliganPolicy{MinResources: 1, MaxMulligans: 2}
+	result, err = Run(context.Background(), testDeck(0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0), settings, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !reflect.DeepEqual(result.Mulligans, []int{0, 0, 10}) {
+		t.Errorf("Expected every game to take 2 mulligans, got %v", result.Mulligans)
+	}
+	if turns := result.FirstCastable[0]; turns.Turns[0] != 10 || turns.MeanTurn != 1 || turns.Never != 0 {
+		t.Errorf("Expected free cards castable on turn 1, got %+v", turns)
+	}
+}
+
+func TestRun_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	settings := Settings{Games: 100, Seed: "cancel"}
+	settings.Defaults()
+
+	played := 0
+	_, err := Run(ctx, testDeck(10, 1, 2, 3), settings, func(done int) {
+		played = done
+		if done == 5 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) || played != 5 {
+		t.Errorf("Expected to stop after 5 games with context.Canceled, got %d games and %v", played, err)
+	}
+}
+
+func TestSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		fields   []string
+	}{
+		{"defaults", Settings{}, nil},
+		{"too many games", Settings{Games: MaxGames + 1}, []string{"games"}},
+		{"too many turns", Settings{Turns: MaxTurns + 1}, []string{"turns"}},
+		{"hand larger than the deck", Settings{HandSize: 41}, []string{"hand_size"}},
+		{"negative policy", Settings{Mulligan: MulliganPolicy{MinResources: -1}}, []string{"mulligan.min_resources"}},
+		{"inverted policy", Settings{Mulligan: MulliganPolicy{MinResources: 4, MaxResources: 2}}, []string{"mulligan.min_resources"}},
+		{"mulligan to nothing", Settings{Mulligan: MulliganPolicy{MaxMulligans: 7}}, []string{"mulligan.max_mulligans"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.settings.Defaults()
+			var fields []string
+			for _, field := range tt.settings.Validate(40) {
+				fields = append(fields, field.Field)
+			}
+			if !slices.Equal(fields, tt.fields) {
+				t.Errorf("Expected problems with %v, got %v", tt.fields, fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jwebster45206/tcg-api/internal/search"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// Result limits for GET /cards/search. Results are ranked rather than paged.
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// CardSearchResult is one card found by a full-text search, with fragments
+// of the fields that matched
+type CardSearchResult struct {
+	Card       TypedCard          `json:"card"`
+	Score      float64            `json:"score"`
+	Highlights []search.Highlight `json:"highlights"`
+}
+
+// CardSearchHandler serves /cards/search, a full-text search over game and
+// image card names, subtitles and descriptions
+type CardSearchHandler struct {
+	storage storage.Storage
+	index   *search.Index
+	logger  *slog.Logger
+}
+
+// NewCardSearchHandler creates a new CardSearchHandler with the given dependencies
+func NewCardSearchHandler(storage storage.Storage, index *search.Index, logger *slog.Logger) *CardSearchHandler {
+	return &CardSearchHandler{
+		storage: storage,
+		index:   index,
+		logger:  logger,
+	}
+}
+
+func (h *CardSearchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		// GET /cards/search?text= - Search cards by text
+		h.searchCards(w, r)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// searchCards handles GET /cards/search?text=, optionally narrowed by
+// ?card_type= and capped by ?limit=
+func (h *CardSearchHandler) searchCards(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	text := strings.TrimSpace(params.Get("text"))
+	if text == "" {
+		writeInvalidQuery(w, errors.New("text is required"))
+		return
+	}
+
+	opts := search.Options{
+		CardType: params.Get("card_type"),
+		Limit:    defaultSearchLimit,
+	}
+	if opts.CardType != "" && !slices.Contains(search.CardTypes(), opts.CardType) {
+		response := ErrorResponse{
+			Error:   "invalid_card_type",
+			Message: "Unsupported card type; expected one of: " + strings.Join(search.CardTypes(), ", "),
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+	if raw := params.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			writeInvalidQuery(w, fmt.Errorf("limit must be between 1 and %d", maxSearchLimit))
+			return
+		}
+		opts.Limit = limit
+	}
+
+	ctx := r.Context()
+	results := []CardSearchResult{}
+	for _, result := range h.index.Search(text, opts) {
+		card, err := storage.GetCard(ctx, h.storage, result.ID)
+		if errors.Is(err, storage.ErrNotFound) {
+			// Deleted since the index was searched
+			continue
+		}
+		if err != nil {
+			h.logger.Error("Failed to load search result",
+				slog.String("operation", "search_cards"),
+				slog.String("card_id", result.ID.String()),
+				slog.Any("error", err))
+			writeStorageError(w, err, "Card not found", "Failed to search cards")
+			return
+		}
+		results = append(results, CardSearchResult{
+			Card:       TypedCard{card},
+			Score:      result.Score,
+			Highlights: result.Highlights,
+		})
+	}
+
+	writeJSONResponse(w, http.StatusOK, ListResponse[CardSearchResult]{Data: results})
+}
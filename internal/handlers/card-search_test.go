@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/search"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// newTestSearchHandler returns a CardSearchHandler over indexed storage
+// holding a few game and image cards, and the unindexed storage beneath it
+func newTestSearchHandler(t *testing.T) (*CardSearchHandler, storage.Storage) {
+	t.Helper()
+
+	index := search.NewIndex()
+	base := storage.NewMockStorage()
+	sto := search.WithIndex(base, index)
+	ctx := context.Background()
+	for _, card := range []models.GameCard{
+		{Name: "Goblin King", Subtitle: "Lord of the Hill"},
+		{Name: "Goblin Raider"},
+		{Name: "Sky Angel"},
+	} {
+		if _, err := sto.CreateGameCard(ctx, card); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := sto.CreateImageCard(ctx, models.ImageCard{Name: "Mountain Pass", Description: "Goblin country"}); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewCardSearchHandler(sto, index, testLogger()), base
+}
+
+// searchResultNames runs a search and returns the names of the cards found
+func searchResultNames(t *testing.T, handler http.Handler, query string) []string {
+	t.Helper()
+
+	req, err := http.NewRequest("GET", "/cards/search?"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("%s: handler returned wrong status code: got %v want %v",
+			query, status, http.StatusOK)
+	}
+	var body ListResponse[struct {
+		Card map[string]interface{} `json:"card"`
+	}]
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	names := []string{}
+	for _, result := range body.Data {
+		names = append(names, result.Card["name"].(string))
+	}
+	return names
+}
+
+func TestCardSearchHandler_SearchCards(t *testing.T) {
+	handler, _ := newTestSearchHandler(t)
+
+	req, err := http.NewRequest("GET", "/cards/search?text=gob+lord", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	var body struct {
+		Data []struct {
+			Card       map[string]interface{} `json:"card"`
+			Score      float64                `json:"score"`
+			Highlights []search.Highlight     `json:"highlights"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if len(body.Data) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(body.Data))
+	}
+	result := body.Data[0]
+	if result.Card["name"] != "Goblin King" || result.Card["card_type"] != models.CardTypeGameCard {
+		t.Errorf("Expected the Goblin King game card, got %v", result.Card)
+	}
+	if result.Score <= 0 {
+		t.Errorf("Expected a positive score, got %v", result.Score)
+	}
+	if len(result.Highlights) != 2 || result.Highlights[0].Fragment != "<mark>Gob</mark>lin King" {
+		t.Errorf("Expected name and subtitle highlights, got %v", result.Highlights)
+	}
+}
+
+func TestCardSearchHandler_SearchCards_TypeAndLimit(t *testing.T) {
+	handler, base := newTestSearchHandler(t)
+
+	tests := []struct {
+		query     string
+		wantNames []string
+	}{
+		{"text=goblin", []string{"Goblin King", "Goblin Raider", "Mountain Pass"}},
+		{"text=goblin&limit=1", []string{"Goblin King"}},
+		{"text=goblin&card_type=image-card", []string{"Mountain Pass"}},
+		{"text=dragon", []string{}},
+	}
+
+	for _, tt := range tests {
+		if names := searchResultNames(t, handler, tt.query); !slices.Equal(names, tt.wantNames) {
+			t.Errorf("%s: expected %v, got %v", tt.query, tt.wantNames, names)
+		}
+	}
+
+	// Cards deleted behind the index's back are skipped
+	cards, err := base.ListGameCards(context.Background(), "gamecard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, card := range cards {
+		if err := base.DeleteGameCard(context.Background(), card.ID, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if names := searchResultNames(t, handler, "text=goblin"); !slices.Equal(names, []string{"Mountain Pass"}) {
+		t.Errorf("Expected only the image card after deletes, got %v", names)
+	}
+}
+
+func TestCardSearchHandler_InvalidQuery(t *testing.T) {
+	handler, _ := newTestSearchHandler(t)
+
+	tests := []struct {
+		query     string
+		wantError string
+	}{
+		{"", "invalid_query"},
+		{"text=+++", "invalid_query"},
+		{"text=goblin&limit=0", "invalid_query"},
+		{"text=goblin&limit=101", "invalid_query"},
+		{"text=goblin&card_type=playing-card", "invalid_card_type"},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest("GET", "/cards/search?"+tt.query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("%q: handler returned wrong status code: got %v want %v",
+				tt.query, status, http.StatusBadRequest)
+			continue
+		}
+		var response ErrorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+		if response.Error != tt.wantError {
+			t.Errorf("%q: expected error %q, got %q", tt.query, tt.wantError, response.Error)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// CardSetsHandler serves /sets, the sets and expansions game cards are
+// printed in
+type CardSetsHandler struct {
+	storage storage.Storage
+	logger  *slog.Logger
+}
+
+// NewCardSetsHandler creates a new CardSetsHandler with the given dependencies
+func NewCardSetsHandler(storage storage.Storage, logger *slog.Logger) *CardSetsHandler {
+	return &CardSetsHandler{
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+func (h *CardSetsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/sets"), "/")
+	code, sub, _ := strings.Cut(path, "/")
+
+	switch r.Method {
+	case http.MethodGet:
+		if code == "" {
+			// GET /sets - List all sets
+			h.listSets(w, r)
+		} else if sub == "cards" {
+			// GET /sets/{code}/cards - List the cards printed in a set
+			h.listSetCards(w, r, code)
+		} else if sub == "" {
+			// GET /sets/{code} - Get specific set
+			h.getSet(w, r, code)
+		} else {
+			http.NotFound(w, r)
+		}
+
+	case http.MethodPost:
+		if code == "" {
+			// POST /sets - Create new set
+			h.createSet(w, r)
+		} else {
+			http.Error(w, "Method not allowed for this path", http.StatusMethodNotAllowed)
+		}
+
+	case http.MethodPut:
+		if code != "" && sub == "" {
+			// PUT /sets/{code} - Update set
+			h.updateSet(w, r, code)
+		} else {
+			http.Error(w, "Set code required for update", http.StatusBadRequest)
+		}
+
+	case http.MethodDelete:
+		if code != "" && sub == "" {
+			// DELETE /sets/{code} - Delete set
+			h.deleteSet(w, r, code)
+		} else {
+			http.Error(w, "Set code required for deletion", http.StatusBadRequest)
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listSets handles GET /sets
+func (h *CardSetsHandler) listSets(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sets, err := h.storage.ListCardSets(ctx)
+	if err != nil {
+		h.logger.Error("Failed to list card sets",
+			slog.String("operation", "list_card_sets"),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card set not found", "Failed to retrieve card sets")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, ListResponse[*models.CardSet]{Data: sets})
+}
+
+// listSetCards handles GET /sets/{code}/cards, taking the same paging and
+// filter parameters as GET /game-cards
+func (h *CardSetsHandler) listSetCards(w http.ResponseWriter, r *http.Request, code string) {
+	params := r.URL.Query()
+	pageReq, err := parsePageRequest(params)
+	if err != nil {
+		writeInvalidQuery(w, err)
+		return
+	}
+	filter, err := parseGameCardFilter(params)
+	if err != nil {
+		writeInvalidQuery(w, err)
+		return
+	}
+	filter.Set = code
+
+	ctx := r.Context()
+	if _, err := h.storage.GetCardSet(ctx, code); err != nil {
+		h.logger.Error("Failed to get card set",
+			slog.String("operation", "list_card_set_cards"),
+			slog.String("set_code", code),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card set not found", "Failed to retrieve cards")
+		return
+	}
+
+	page, err := h.storage.ListGameCardsPage(ctx, filter, pageReq)
+	if err != nil {
+		h.logger.Error("Failed to list card set cards",
+			slog.String("operation", "list_card_set_cards"),
+			slog.String("set_code", code),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card set not found", "Failed to retrieve cards")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, newListResponse(page))
+}
+
+// getSet handles GET /sets/{code}
+func (h *CardSetsHandler) getSet(w http.ResponseWriter, r *http.Request, code string) {
+	ctx := r.Context()
+	set, err := h.storage.GetCardSet(ctx, code)
+	if err != nil {
+		h.logger.Error("Failed to get card set",
+			slog.String("operation", "get_card_set"),
+			slog.String("set_code", code),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card set not found", "Failed to retrieve card set")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, set)
+}
+
+// createSet handles POST /sets
+func (h *CardSetsHandler) createSet(w http.ResponseWriter, r *http.Request) {
+	var set models.CardSet
+	if !decodeJSON(w, r, &set) {
+		return
+	}
+	if !validateModel(w, &set, "card set") {
+		return
+	}
+
+	ctx := r.Context()
+	createdSet, err := h.storage.CreateCardSet(ctx, set)
+	if err != nil {
+		h.logger.Error("Failed to create card set",
+			slog.String("operation", "create_card_set"),
+			slog.String("set_code", set.Code),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card set not found", "Failed to create card set")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, createdSet)
+}
+
+// updateSet handles PUT /sets/{code}
+func (h *CardSetsHandler) updateSet(w http.ResponseWriter, r *http.Request, code string) {
+	var set models.CardSet
+	if !decodeJSON(w, r, &set) {
+		return
+	}
+
+	// Set the code from the URL path
+	set.Code = code
+	if !validateModel(w, &set, "card set") {
+		return
+	}
+
+	ctx := r.Context()
+	updatedSet, err := h.storage.UpdateCardSet(ctx, set)
+	if err != nil {
+		h.logger.Error("Failed to update card set",
+			slog.String("operation", "update_card_set"),
+			slog.String("set_code", code),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card set not found", "Failed to update card set")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, updatedSet)
+}
+
+// deleteSet handles DELETE /sets/{code}. Sets that cards are still printed in
+// cannot be deleted.
+func (h *CardSetsHandler) deleteSet(w http.ResponseWriter, r *http.Request, code string) {
+	ctx := r.Context()
+	if err := h.storage.DeleteCardSet(ctx, code); err != nil {
+		h.logger.Error("Failed to delete card set",
+			slog.String("operation", "delete_card_set"),
+			slog.String("set_code", code),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card set not found", "Failed to delete card set")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+func TestCardSetsHandler_CRUD(t *testing.T) {
+	handler := NewCardSetsHandler(storage.NewMockStorage(), testLogger())
+
+	rr := serve(t, handler, "POST", "/sets",
+		`{"code": "CORE", "name": "Core Set", "release_date": "2024-09-15", "size": 250}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create: got %v want %v: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+
+	rr = serve(t, handler, "POST", "/sets", `{"code": "CORE", "name": "Core Set Again"}`)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("create duplicate: got %v want %v", rr.Code, http.StatusConflict)
+	}
+
+	rr = serve(t, handler, "PUT", "/sets/CORE", `{"name": "Core Set 2025", "size": 260}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("update: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	rr = serve(t, handler, "GET", "/sets/CORE", "")
+	var set models.CardSet
+	if err := json.Unmarshal(rr.Body.Bytes(), &set); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if rr.Code != http.StatusOK || set.Code != "CORE" || set.Name != "Core Set 2025" || set.Size != 260 {
+		t.Errorf("get: expected the updated set, got %v %+v", rr.Code, set)
+	}
+
+	rr = serve(t, handler, "GET", "/sets", "")
+	var list ListResponse[models.CardSet]
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].Code != "CORE" {
+		t.Errorf("list: expected the one set, got %+v", list.Data)
+	}
+
+	rr = serve(t, handler, "DELETE", "/sets/CORE", "")
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("delete: got %v want %v", rr.Code, http.StatusNoContent)
+	}
+	for _, method := range []string{"GET", "DELETE"} {
+		if rr := serve(t, handler, method, "/sets/CORE", ""); rr.Code != http.StatusNotFound {
+			t.Errorf("%s deleted set: got %v want %v", method, rr.Code, http.StatusNotFound)
+		}
+	}
+	if rr := serve(t, handler, "PUT", "/sets/CORE", `{"name": "Core"}`); rr.Code != http.StatusNotFound {
+		t.Errorf("update deleted set: got %v want %v", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestCardSetsHandler_InvalidSet(t *testing.T) {
+	handler := NewCardSetsHandler(storage.NewMockStorage(), testLogger())
+
+	tests := []struct {
+		body      string
+		wantField string
+	}{
+		{`{"name": "No Code"}`, "code"},
+		{`{"code": "BAD CODE", "name": "Spaces"}`, "code"},
+		{`{"code": "CORE"}`, "name"},
+		{`{"code": "CORE", "name": "` + strings.Repeat("C", models.MaxNameLength+1) + `"}`, "name"},
+		{`{"code": "CORE", "name": "Core", "size": -1}`, "size"},
+		{`{"code": "CORE", "name": "Core", "release_date": "15/09/2024"}`, "release_date"},
+	}
+	for _, tt := range tests {
+		rr := serve(t, handler, "POST", "/sets", tt.body)
+		var response ValidationErrorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+		if rr.Code != http.StatusUnprocessableEntity || response.Error != "validation_failed" ||
+			len(response.Fields) != 1 || response.Fields[0].Field != tt.wantField {
+			t.Errorf("%.40s: expected 422 on %s, got %v %s", tt.body, tt.wantField, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestCardSetsHandler_SetCards(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	ctx := context.Background()
+	for _, set := range []models.CardSet{{Code: "CORE", Name: "Core Set"}, {Code: "EXP1", Name: "Expansion"}} {
+		if _, err := mockStorage.CreateCardSet(ctx, set); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, card := range []models.GameCard{
+		{Name: "Wyrm", Printings: []models.Printing{
+			{SetCode: "CORE", CollectorNumber: "1", Rarity: models.RarityRare},
+			{SetCode: "EXP1", CollectorNumber: "9", Rarity: models.RarityMythic},
+		}},
+		{Name: "Goblin", Printings: []models.Printing{{SetCode: "CORE", CollectorNumber: "2", Rarity: models.RarityCommon}}},
+		{Name: "Angel", Printings: []models.Printing{{SetCode: "EXP1", CollectorNumber: "3", Rarity: models.RarityRare}}},
+	} {
+		if _, err := mockStorage.CreateGameCard(ctx, card); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := NewCardSetsHandler(mockStorage, testLogger())
+
+	tests := []struct {
+		path      string
+		wantNames []string
+	}{
+		{"/sets/CORE/cards", []string{"Goblin", "Wyrm"}},
+		{"/sets/EXP1/cards?rarity=rare", []string{"Angel"}},
+		{"/sets/CORE/cards?rarity=mythic", []string{}},
+	}
+	for _, tt := range tests {
+		rr := serve(t, handler, "GET", tt.path, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: got %v want %v", tt.path, rr.Code, http.StatusOK)
+		}
+		var response ListResponse[models.GameCard]
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+		names := []string{}
+		for _, card := range response.Data {
+			names = append(names, card.Name)
+		}
+		if len(names) != len(tt.wantNames) || (len(names) > 0 && names[0] != tt.wantNames[0]) {
+			t.Errorf("%s: expected %v, got %v", tt.path, tt.wantNames, names)
+		}
+	}
+
+	if rr := serve(t, handler, "GET", "/sets/NOPE/cards", ""); rr.Code != http.StatusNotFound {
+		t.Errorf("cards of unknown set: got %v want %v", rr.Code, http.StatusNotFound)
+	}
+	if rr := serve(t, handler, "GET", "/sets/CORE/cards?rarity=legendary", ""); rr.Code != http.StatusBadRequest {
+		t.Errorf("unknown rarity: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+
+	// Sets with printings cannot be deleted
+	rr := serve(t, handler, "DELETE", "/sets/CORE", "")
+	var response ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if rr.Code != http.StatusConflict || response.Message != "Card set still has printings" {
+		t.Errorf("delete set in use: expected 409, got %v %+v", rr.Code, response)
+	}
+}
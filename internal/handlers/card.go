@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// TypedCard wraps a card of any type so that its JSON form carries a
+// card_type discriminator alongside the card's own fields
+type TypedCard struct {
+	models.CardInterface
+}
+
+// MarshalJSON encodes the wrapped card with a leading card_type field
+func (c TypedCard) MarshalJSON() ([]byte, error) {
+	cardJSON, err := json.Marshal(c.CardInterface)
+	if err != nil {
+		return nil, err
+	}
+	typeJSON, err := json.Marshal(c.GetCardType())
+	if err != nil {
+		return nil, err
+	}
+
+	// Splice the discriminator into the card's JSON object
+	out := make([]byte, 0, len(cardJSON)+len(typeJSON)+16)
+	out = append(out, `{"card_type":`...)
+	out = append(out, typeJSON...)
+	if body := cardJSON[1:]; len(body) > 1 {
+		out = append(out, ',')
+		out = append(out, body...)
+	} else {
+		out = append(out, '}')
+	}
+	return out, nil
+}
+
+// CardsHandler serves /cards, a read-only view over every registered card type
+type CardsHandler struct {
+	storage storage.Storage
+	logger  *slog.Logger
+}
+
+// NewCardsHandler creates a new CardsHandler with the given dependencies
+func NewCardsHandler(storage storage.Storage, logger *slog.Logger) *CardsHandler {
+	return &CardsHandler{
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+func (h *CardsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/cards")
+
+	switch r.Method {
+	case http.MethodGet:
+		if path == "" || path == "/" {
+			// GET /cards - List cards of every type
+			h.listCards(w, r)
+		} else {
+			// GET /cards/{id} - Get a card of any type
+			cardID := strings.Trim(path, "/")
+			h.getCard(w, r, cardID)
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listCards handles GET /cards, optionally filtered by ?card_type=
+func (h *CardsHandler) listCards(w http.ResponseWriter, r *http.Request) {
+	cardType := r.URL.Query().Get("card_type")
+
+	ctx := r.Context()
+	cards, err := storage.ListCards(ctx, h.storage, cardType)
+	if err != nil {
+		if errors.Is(err, storage.ErrUnsupportedCardType) {
+			response := ErrorResponse{
+				Error:   "invalid_card_type",
+				Message: "Unsupported card type; expected one of: " + strings.Join(storage.CardTypes(), ", "),
+			}
+			writeJSONResponse(w, http.StatusBadRequest, response)
+			return
+		}
+		h.logger.Error("Failed to list cards",
+			slog.String("operation", "list_cards"),
+			slog.String("card_type", cardType),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card not found", "Failed to retrieve cards")
+		return
+	}
+
+	typedCards := make([]TypedCard, len(cards))
+	for i, card := range cards {
+		typedCards[i] = TypedCard{card}
+	}
+	writeJSONResponse(w, http.StatusOK, typedCards)
+}
+
+// getCard handles GET /cards/{id}
+func (h *CardsHandler) getCard(w http.ResponseWriter, r *http.Request, cardID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(cardID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid card ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	ctx := r.Context()
+	card, err := storage.GetCard(ctx, h.storage, id)
+	if err != nil {
+		h.logger.Error("Failed to get card",
+			slog.String("operation", "get_card"),
+			slog.String("card_id", cardID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card not found", "Failed to retrieve card")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, TypedCard{card})
+}
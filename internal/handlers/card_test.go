@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// newTestCardStorage returns storage holding one card of each built-in type
+func newTestCardStorage(t *testing.T) (storage.Storage, []models.CardInterface) {
+	t.Helper()
+
+	mockStorage := storage.NewMockStorage()
+	ctx := context.Background()
+
+	gameCard, err := mockStorage.CreateGameCard(ctx, models.GameCard{Name: "Ancient Wyrm", Cost: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	imageCard, err := mockStorage.CreateImageCard(ctx, models.ImageCard{Name: "Sunset"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	playingCard, err := mockStorage.CreatePlayingCard(ctx, models.PlayingCard{Suite: models.SuiteClubs, Value: 13})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return mockStorage, []models.CardInterface{gameCard, imageCard, playingCard}
+}
+
+func TestCardsHandler_GetCard(t *testing.T) {
+	mockStorage, cards := newTestCardStorage(t)
+	handler := NewCardsHandler(mockStorage, testLogger())
+
+	for _, card := range cards {
+		t.Run(card.GetCardType(), func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/cards/"+card.GetID().String(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != http.StatusOK {
+				t.Fatalf("handler returned wrong status code: got %v want %v",
+					status, http.StatusOK)
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Could not parse response body: %v", err)
+			}
+			if body["card_type"] != card.GetCardType() {
+				t.Errorf("Expected card_type '%s', got '%v'", card.GetCardType(), body["card_type"])
+			}
+			if body["id"] != card.GetID().String() {
+				t.Errorf("Expected id '%s', got '%v'", card.GetID(), body["id"])
+			}
+		})
+	}
+
+	req, err := http.NewRequest("GET", "/cards/"+uuid.New().String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusNotFound)
+	}
+}
+
+func TestCardsHandler_ListCards(t *testing.T) {
+	mockStorage, _ := newTestCardStorage(t)
+	handler := NewCardsHandler(mockStorage, testLogger())
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedCards  int
+	}{
+		{"all types", "", http.StatusOK, 3},
+		{"game cards only", "?card_type=game-card", http.StatusOK, 1},
+		{"unknown type", "?card_type=tarot-card", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/cards"+tt.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v",
+					status, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body []map[string]interface{}
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Could not parse response body: %v", err)
+			}
+			if len(body) != tt.expectedCards {
+				t.Errorf("Expected %d cards, got %d", tt.expectedCards, len(body))
+			}
+			for _, card := range body {
+				if card["card_type"] == nil {
+					t.Errorf("Expected card_type discriminator on %v", card)
+				}
+			}
+		})
+	}
+}
+
+func TestCardsHandler_MethodNotAllowed(t *testing.T) {
+	handler := NewCardsHandler(storage.NewMockStorage(), testLogger())
+
+	req, err := http.NewRequest("POST", "/cards", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusMethodNotAllowed {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusMethodNotAllowed)
+	}
+}
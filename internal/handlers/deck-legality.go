@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+)
+
+// LegalityResponse is returned by GET /decks/{id}/legality
+type LegalityResponse struct {
+	DeckID uuid.UUID `json:"deck_id"`
+	GameID uuid.UUID `json:"game_id"`
+	// Format is empty when the deck was checked against the game's deck rules
+	Format     string             `json:"format"`
+	Legal      bool               `json:"legal"`
+	Violations []models.Violation `json:"violations"`
+}
+
+// deckLegality handles GET /decks/{id}/legality?format=&game_id=. The game
+// defaults to the one the deck's game cards belong to, and the format to the
+// game's deck rules.
+func (h *DecksHandler) deckLegality(w http.ResponseWriter, r *http.Request, deckID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(deckID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid deck ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	var gameID uuid.UUID
+	if raw := r.URL.Query().Get("game_id"); raw != "" {
+		if gameID, err = uuid.Parse(raw); err != nil {
+			response := ErrorResponse{
+				Error:   "invalid_id",
+				Message: "Invalid game ID format",
+			}
+			writeJSONResponse(w, http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	deck, err := h.storage.GetDeck(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to get deck",
+			slog.String("operation", "check_deck_legality"),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to retrieve deck")
+		return
+	}
+
+	cards, err := gameCards(ctx, h.storage, deck)
+	if err != nil {
+		h.logger.Error("Failed to look up deck cards",
+			slog.String("operation", "check_deck_legality"),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to retrieve deck cards")
+		return
+	}
+
+	if gameID == uuid.Nil {
+		games := map[uuid.UUID]bool{}
+		for _, card := range cards {
+			if card.GameID != nil {
+				games[*card.GameID] = true
+				gameID = *card.GameID
+			}
+		}
+		if len(games) != 1 {
+			response := ErrorResponse{
+				Error:   "game_required",
+				Message: "The deck's cards do not belong to exactly one game; pass game_id",
+			}
+			writeJSONResponse(w, http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	game, err := h.storage.GetGameDefinition(ctx, gameID)
+	if err != nil {
+		h.logger.Error("Failed to get game definition",
+			slog.String("operation", "check_deck_legality"),
+			slog.String("game_id", gameID.String()),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Game definition not found", "Failed to retrieve game definition")
+		return
+	}
+
+	formatName := r.URL.Query().Get("format")
+	format, ok := game.Format(formatName)
+	if !ok {
+		message := fmt.Sprintf("Unknown format %q", formatName)
+		if names := game.FormatNames(); len(names) > 0 {
+			message += "; " + game.Name + " has " + strings.Join(names, ", ")
+		}
+		response := ErrorResponse{
+			Error:   "unknown_format",
+			Message: message,
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	violations := game.CheckDeck(deck, format, cards)
+	writeJSONResponse(w, http.StatusOK, LegalityResponse{
+		DeckID:     deck.ID,
+		GameID:     game.ID,
+		Format:     format.Name,
+		Legal:      len(violations) == 0,
+		Violations: violations,
+	})
+}
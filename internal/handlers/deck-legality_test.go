@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+func TestDecksHandler_DeckLegality(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewDecksHandler(mockStorage, nil, testLogger())
+	ctx := context.Background()
+
+	game, err := mockStorage.CreateGameDefinition(ctx, models.GameDefinition{
+		Name:      "Skirmish",
+		DeckRules: models.DeckRules{MinCards: 6, MaxCopies: 3},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test game: %v", err)
+	}
+	for _, set := range []models.CardSet{{Code: "CORE", Name: "Core Set"}, {Code: "EXP1", Name: "First Expansion"}} {
+		if _, err := mockStorage.CreateCardSet(ctx, set); err != nil {
+			t.Fatalf("Failed to create test set: %v", err)
+		}
+	}
+	newCard := func(card models.GameCard) uuid.UUID {
+		card.GameID = &game.ID
+		created, err := mockStorage.CreateGameCard(ctx, card)
+		if err != nil {
+			t.Fatalf("Failed to create test card: %v", err)
+		}
+		return created.ID
+	}
+	land := newCard(models.GameCard{Name: "Mountain", IsResource: true, Colors: []string{"Red"}})
+	bolt := newCard(models.GameCard{Name: "Fire Bolt", Colors: []string{"Red"},
+		Printings: []models.Printing{{SetCode: "CORE"}}})
+	wyrm := newCard(models.GameCard{Name: "Ancient Wyrm", Colors: []string{"Red", "Green"},
+		Printings: []models.Printing{{SetCode: "EXP1"}}})
+
+	game.Formats = []models.Format{
+		{Name: "Core", Sets: []string{"CORE"}, MaxSideboard: 1},
+		{Name: "Highlander", MaxCopies: 1, MaxCards: 8, Banned: []uuid.UUID{wyrm}, Colors: []string{"Red"}},
+		{Name: "Vintage", Restricted: []uuid.UUID{bolt}, MinResourceRatio: 0.75},
+	}
+	if game, err = mockStorage.UpdateGameDefinition(ctx, *game); err != nil {
+		t.Fatalf("Failed to update test game: %v", err)
+	}
+
+	deck := models.Deck{Name: "Burn"}
+	deck.AddCard(land, models.CardTypeGameCard, models.SectionMain, 4)
+	deck.AddCard(bolt, models.CardTypeGameCard, models.SectionMain, 2)
+	deck.AddCard(bolt, models.CardTypeGameCard, models.SectionSideboard, 1)
+	deck.AddCard(wyrm, models.CardTypeGameCard, models.SectionSideboard, 1)
+	deck.AddCard(uuid.New(), models.CardTypeGameCard, models.SectionMaybeboard, 9)
+	created, err := mockStorage.CreateDeck(ctx, deck)
+	if err != nil {
+		t.Fatalf("Failed to create test deck: %v", err)
+	}
+	path := "/decks/" + created.ID.String() + "/legality"
+
+	tests := []struct {
+		name  string
+		query string
+		want  []models.Violation
+	}{
+		// The maybeboard is not checked, and resource cards are not
+		// limited by max_copies
+		{"deck rules", "", []models.Violation{}},
+		{"explicit game", "?game_id=" + game.ID.String(), []models.Violation{}},
+		{"format", "?format=Core", []models.Violation{
+			{Rule: models.RuleMaxSideboard},
+			{Rule: models.RuleSets, CardIDs: []uuid.UUID{land, wyrm}},
+		}},
+		{"limits fall back to deck rules", "?format=Highlander", []models.Violation{
+			{Rule: models.RuleMaxCopies, CardIDs: []uuid.UUID{bolt}},
+			{Rule: models.RuleBanned, CardIDs: []uuid.UUID{wyrm}},
+			{Rule: models.RuleColors, CardIDs: []uuid.UUID{wyrm}},
+		}},
+		{"restricted and ratios", "?format=Vintage", []models.Violation{
+			{Rule: models.RuleRestricted, CardIDs: []uuid.UUID{bolt}},
+			{Rule: models.RuleMinResourceRatio},
+		}},
+	}
+	for _, tt := range tests {
+		rr := serve(t, handler, "GET", path+tt.query, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: got %v want %v: %s", tt.name, rr.Code, http.StatusOK, rr.Body.String())
+		}
+		var response LegalityResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: could not parse response body: %v", tt.name, err)
+		}
+		if response.Legal != (len(tt.want) == 0) || response.GameID != game.ID || len(response.Violations) != len(tt.want) {
+			t.Errorf("%s: expected violations %+v, got %+v", tt.name, tt.want, response)
+			continue
+		}
+		for i, violation := range response.Violations {
+			if violation.Rule != tt.want[i].Rule || !slices.Equal(violation.CardIDs, tt.want[i].CardIDs) || violation.Message == "" {
+				t.Errorf("%s: violation %d: expected %+v, got %+v", tt.name, i, tt.want[i], violation)
+			}
+		}
+	}
+
+	// Cards outside the game break the rules of every format
+	other, err := mockStorage.CreateGameCard(ctx, models.GameCard{Name: "Stranger"})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+	created.AddCard(other.ID, models.CardTypeGameCard, models.SectionMain, 1)
+	if _, err := mockStorage.UpdateDeck(ctx, *created); err != nil {
+		t.Fatalf("Failed to update test deck: %v", err)
+	}
+	rr := serve(t, handler, "GET", path, "")
+	var response LegalityResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if response.Legal || len(response.Violations) != 1 || response.Violations[0].Rule != models.RuleGame ||
+		!slices.Equal(response.Violations[0].CardIDs, []uuid.UUID{other.ID}) {
+		t.Errorf("Expected a game violation for the stranger, got %+v", response)
+	}
+
+	for _, tt := range []struct {
+		name, path string
+		wantStatus int
+		wantError  string
+	}{
+		{"unknown format", path + "?format=Pauper", http.StatusBadRequest, "unknown_format"},
+		{"invalid game", path + "?game_id=nope", http.StatusBadRequest, "invalid_id"},
+		{"missing game", path + "?game_id=" + uuid.New().String(), http.StatusNotFound, "not_found"},
+		{"missing deck", "/decks/" + uuid.New().String() + "/legality", http.StatusNotFound, "not_found"},
+	} {
+		rr := serve(t, handler, "GET", tt.path, "")
+		var response ErrorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: could not parse response body: %v", tt.name, err)
+		}
+		if rr.Code != tt.wantStatus || response.Error != tt.wantError {
+			t.Errorf("%s: expected %v %s, got %v %+v", tt.name, tt.wantStatus, tt.wantError, rr.Code, response)
+		}
+	}
+
+	// Without game cards the game must be named
+	empty, err := mockStorage.CreateDeck(ctx, models.Deck{Name: "Empty"})
+	if err != nil {
+		t.Fatalf("Failed to create test deck: %v", err)
+	}
+	rr = serve(t, handler, "GET", "/decks/"+empty.ID.String()+"/legality", "")
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected %v without a game, got %v: %s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+}
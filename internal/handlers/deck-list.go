@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/decklist"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/search"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// Reasons a deck list name is unresolved
+const (
+	UnresolvedUnknown   = "unknown"
+	UnresolvedAmbiguous = "ambiguous"
+)
+
+// maxSuggestions caps the suggestions for an unknown card name
+const maxSuggestions = 3
+
+// UnresolvedCard is a name in an imported deck list that does not name
+// exactly one card
+type UnresolvedCard struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	// Suggestions are the names of cards like an unknown name
+	Suggestions []string `json:"suggestions,omitempty"`
+	// CardIDs are the cards an ambiguous name names
+	CardIDs []uuid.UUID `json:"card_ids,omitempty"`
+}
+
+// DeckImportErrorResponse is the 422 response for a deck list with names
+// that cannot be resolved to cards
+type DeckImportErrorResponse struct {
+	ErrorResponse
+	Cards []UnresolvedCard `json:"cards"`
+}
+
+// namedCard is a card a deck list can name
+type namedCard struct {
+	id       uuid.UUID
+	cardType string
+	name     string
+}
+
+// importDeck handles POST /decks/import. The body is a deck list in the
+// format named by its Content-Type, and the cards are resolved by name, case
+// insensitively, against the game and image cards. ?name= and ?owner_id= set
+// the deck's name and owner, and ?game_id= only resolves game cards of that
+// game.
+func (h *DecksHandler) importDeck(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	format := ""
+	for _, f := range decklist.Formats {
+		if decklist.ContentTypes[f] == mediaType {
+			format = f
+		}
+	}
+	if format == "" {
+		var types []string
+		for _, f := range decklist.Formats {
+			types = append(types, decklist.ContentTypes[f])
+		}
+		response := ErrorResponse{
+			Error:   "unsupported_media_type",
+			Message: "Deck lists must have Content-Type " + strings.Join(types, ", "),
+		}
+		w.Header().Set("Accept-Post", strings.Join(types, ", "))
+		writeJSONResponse(w, http.StatusUnsupportedMediaType, response)
+		return
+	}
+
+	params := r.URL.Query()
+	deck := models.Deck{Name: params.Get("name"), Entries: []models.DeckEntry{}}
+	if raw := params.Get("owner_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			response := ErrorResponse{
+				Error:   "invalid_id",
+				Message: "Invalid owner ID format",
+			}
+			writeJSONResponse(w, http.StatusBadRequest, response)
+			return
+		}
+		deck.OwnerID = &id
+	}
+	var gameID *uuid.UUID
+	if raw := params.Get("game_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			response := ErrorResponse{
+				Error:   "invalid_id",
+				Message: "Invalid game ID format",
+			}
+			writeJSONResponse(w, http.StatusBadRequest, response)
+			return
+		}
+		gameID = &id
+	}
+
+	if r.Body == nil {
+		writeDecodeError(w, io.EOF)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		writeDecodeError(w, io.EOF)
+		return
+	}
+
+	list, err := decklist.Parse(format, body)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_deck_list",
+			Message: "Invalid deck list: " + err.Error(),
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+	if deck.Name == "" {
+		deck.Name = list.Name
+	}
+	if deck.Name == "" {
+		deck.Name = "Imported Deck"
+	}
+
+	ctx := r.Context()
+	cards, err := namedCards(ctx, h.storage, gameID)
+	if err != nil {
+		h.logger.Error("Failed to list cards",
+			slog.String("operation", "import_deck"),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card not found", "Failed to resolve deck list")
+		return
+	}
+	byName := make(map[string][]namedCard, len(cards))
+	names := make([]string, 0, len(cards))
+	for _, card := range cards {
+		key := nameKey(card.name)
+		byName[key] = append(byName[key], card)
+		names = append(names, card.name)
+	}
+
+	var unresolved []UnresolvedCard
+	for _, line := range list.Cards {
+		matches := byName[nameKey(line.Name)]
+		if len(matches) == 0 {
+			// Exported decks name cards that no longer exist by ID
+			if id, err := uuid.Parse(line.Name); err == nil {
+				cardType, err := h.cardType(ctx, id)
+				if err != nil {
+					h.logger.Error("Failed to look up deck card",
+						slog.String("operation", "import_deck"),
+						slog.String("card_id", id.String()),
+						slog.Any("error", err))
+					writeStorageError(w, err, "Card not found", "Failed to resolve deck list")
+					return
+				}
+				if cardType != "" {
+					matches = []namedCard{{id: id, cardType: cardType}}
+				}
+			}
+		}
+
+		switch {
+		case len(matches) == 1:
+			deck.AddCard(matches[0].id, matches[0].cardType, line.Section, line.Quantity)
+		case slices.ContainsFunc(unresolved, func(u UnresolvedCard) bool { return nameKey(u.Name) == nameKey(line.Name) }):
+			// Reported already
+		case len(matches) == 0:
+			unresolved = append(unresolved, UnresolvedCard{
+				Name:        line.Name,
+				Reason:      UnresolvedUnknown,
+				Suggestions: search.Suggest(line.Name, names, maxSuggestions),
+			})
+		default:
+			ids := make([]uuid.UUID, len(matches))
+			for i, match := range matches {
+				ids[i] = match.id
+			}
+			unresolved = append(unresolved, UnresolvedCard{
+				Name:    line.Name,
+				Reason:  UnresolvedAmbiguous,
+				CardIDs: ids,
+			})
+		}
+	}
+	if len(unresolved) > 0 {
+		response := DeckImportErrorResponse{
+			ErrorResponse: ErrorResponse{
+				Error:   "unresolved_cards",
+				Message: "Some card names do not name exactly one card; fix them, pass game_id or use card IDs",
+			},
+			Cards: unresolved,
+		}
+		writeJSONResponse(w, http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	deck.Normalize()
+	if !validateModel(w, &deck, "deck") {
+		return
+	}
+
+	createdDeck, err := h.storage.CreateDeck(ctx, deck)
+	if err != nil {
+		h.logger.Error("Failed to create deck",
+			slog.String("operation", "import_deck"),
+			slog.String("deck_name", deck.Name),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to import deck")
+		return
+	}
+
+	setETag(w, createdDeck.Version)
+	writeJSONResponse(w, http.StatusCreated, createdDeck)
+}
+
+// exportDeck handles GET /decks/{id}/export?format=text|csv|json. Cards
+// that no longer exist, and cards of types import does not resolve by name,
+// are listed by ID.
+func (h *DecksHandler) exportDeck(w http.ResponseWriter, r *http.Request, deckID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(deckID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid deck ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = decklist.FormatText
+	}
+	if !slices.Contains(decklist.Formats, format) {
+		response := ErrorResponse{
+			Error:   "invalid_format",
+			Message: "format must be one of: " + strings.Join(decklist.Formats, ", "),
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	ctx := r.Context()
+	deck, err := h.storage.GetDeck(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to get deck",
+			slog.String("operation", "export_deck"),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to retrieve deck")
+		return
+	}
+
+	list := &decklist.List{Name: deck.Name, Cards: make([]decklist.Line, 0, len(deck.Entries))}
+	names := make(map[uuid.UUID]string)
+	for _, entry := range deck.Entries {
+		name, ok := names[entry.CardID]
+		if !ok {
+			card, err := storage.GetCard(ctx, h.storage, entry.CardID)
+			switch {
+			case errors.Is(err, storage.ErrNotFound):
+				name = entry.CardID.String()
+			case err != nil:
+				h.logger.Error("Failed to look up deck card",
+					slog.String("operation", "export_deck"),
+					slog.String("card_id", entry.CardID.String()),
+					slog.Any("error", err))
+				writeStorageError(w, err, "Card not found", "Failed to retrieve deck cards")
+				return
+			case !slices.Contains(namedCardTypes, card.GetCardType()):
+				name = entry.CardID.String()
+			default:
+				name = card.GetName()
+			}
+			names[entry.CardID] = name
+		}
+		list.Cards = append(list.Cards, decklist.Line{Quantity: entry.Quantity, Name: name, Section: entry.Section})
+	}
+
+	var buf bytes.Buffer
+	if err := decklist.Write(&buf, format, list); err != nil {
+		h.logger.Error("Failed to write deck list",
+			slog.String("operation", "export_deck"),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		response := ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to export deck",
+		}
+		writeJSONResponse(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	w.Header().Set("Content-Type", decklist.ContentTypes[format]+"; charset=utf-8")
+	setETag(w, deck.Version)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		h.logger.Error("Failed to write deck list",
+			slog.String("operation", "export_deck"),
+			slog.Any("error", err))
+	}
+}
+
+// namedCardTypes are the types of the cards namedCards returns. Playing
+// cards are left out: every standard deck has its own copies, and two jokers,
+// so their names never name exactly one card.
+var namedCardTypes = []string{models.CardTypeGameCard, models.CardTypeImageCard}
+
+// namedCards returns the game and image cards, the game cards only of the
+// given game if gameID is not nil
+func namedCards(ctx context.Context, s storage.Storage, gameID *uuid.UUID) ([]namedCard, error) {
+	gameCards, err := s.ListGameCards(ctx, "gamecard")
+	if err != nil {
+		return nil, err
+	}
+	imageCards, err := s.ListImageCards(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make([]namedCard, 0, len(gameCards)+len(imageCards))
+	for _, card := range gameCards {
+		if gameID == nil || (card.GameID != nil && *card.GameID == *gameID) {
+			cards = append(cards, namedCard{card.ID, models.CardTypeGameCard, card.Name})
+		}
+	}
+	for _, card := range imageCards {
+		cards = append(cards, namedCard{card.ID, models.CardTypeImageCard, card.Name})
+	}
+	return cards, nil
+}
+
+// nameKey is the form card names are matched in: lower case, with runs of
+// spaces collapsed
+func nameKey(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
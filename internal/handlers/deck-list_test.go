@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+func TestDecksHandler_ImportDeck(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewDecksHandler(mockStorage, nil, testLogger())
+	ctx := context.Background()
+
+	gameA, gameB := uuid.New(), uuid.New()
+	newCard := func(card models.GameCard) uuid.UUID {
+		created, err := mockStorage.CreateGameCard(ctx, card)
+		if err != nil {
+			t.Fatalf("Failed to create test card: %v", err)
+		}
+		return created.ID
+	}
+	land := newCard(models.GameCard{Name: "Mountain", IsResource: true, GameID: &gameA})
+	bolt := newCard(models.GameCard{Name: "Fire Bolt", Cost: 1, GameID: &gameA})
+	ward := newCard(models.GameCard{Name: "Frost Ward", Cost: 2, GameID: &gameA})
+	otherWard := newCard(models.GameCard{Name: "Frost Ward", Cost: 3, GameID: &gameB})
+	image, err := mockStorage.CreateImageCard(ctx, models.ImageCard{Name: "Sunset"})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+
+	wantEntries := []models.DeckEntry{
+		{CardID: bolt, CardType: models.CardTypeGameCard, Quantity: 4, Section: models.SectionMain},
+		{CardID: land, CardType: models.CardTypeGameCard, Quantity: 2, Section: models.SectionMain},
+		{CardID: image.ID, CardType: models.CardTypeImageCard, Quantity: 1, Section: models.SectionMain},
+		{CardID: ward, CardType: models.CardTypeGameCard, Quantity: 2, Section: models.SectionSideboard},
+	}
+	imports := []struct {
+		name, query, contentType, body, wantName string
+	}{
+		{"text", "?game_id=" + gameA.String() + "&name=Burn", "text/plain; charset=utf-8",
+			"4 Fire Bolt\n2 mountain\nSunset\n\nSideboard\n2 Frost  Ward\n", "Burn"},
+		{"CSV", "?game_id=" + gameA.String(), "text/csv",
+			"quantity,name,section\n4,Fire Bolt,main\n1,Mountain,\n1,Mountain,\n1,Sunset,\n2," + ward.String() + ",sideboard\n", "Imported Deck"},
+		{"JSON", "", "application/json",
+			`{"name": "Burn", "cards": [{"quantity": 4, "name": "Fire Bolt"}, {"quantity": 2, "name": "Mountain"},
+			{"name": "Sunset"}, {"quantity": 2, "name": "` + ward.String() + `", "section": "sideboard"}]}`, "Burn"},
+	}
+	for _, tt := range imports {
+		rr := serve(t, handler, "POST", "/decks/import"+tt.query, tt.body, "Content-Type", tt.contentType)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("%s: handler returned wrong status code: got %v want %v: %s", tt.name, rr.Code, http.StatusCreated, rr.Body.String())
+		}
+		var deck models.Deck
+		if err := json.Unmarshal(rr.Body.Bytes(), &deck); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+		if deck.Name != tt.wantName || !reflect.DeepEqual(deck.Entries, wantEntries) || rr.Header().Get("ETag") == "" {
+			t.Errorf("%s: unexpected deck %q with entries %+v", tt.name, deck.Name, deck.Entries)
+		}
+		if _, err := mockStorage.GetDeck(ctx, deck.ID); err != nil {
+			t.Errorf("%s: expected the deck to be saved: %v", tt.name, err)
+		}
+	}
+
+	// Unknown names get suggestions and ambiguous names their candidates
+	rr := serve(t, handler, "POST", "/decks/import",
+		"4 Fire Blot\n2 Frost Ward\nSB: 1 fire blot\n1 Dragon\n", "Content-Type", "text/plain")
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", rr.Code, http.StatusUnprocessableEntity, rr.Body.String())
+	}
+	var response DeckImportErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	wantUnresolved := []UnresolvedCard{
+		{Name: "Fire Blot", Reason: UnresolvedUnknown, Suggestions: []string{"Fire Bolt"}},
+		{Name: "Frost Ward", Reason: UnresolvedAmbiguous, CardIDs: []uuid.UUID{ward, otherWard}},
+		{Name: "Dragon", Reason: UnresolvedUnknown},
+	}
+	if response.Error != "unresolved_cards" || len(response.Cards) != len(wantUnresolved) {
+		t.Fatalf("Unexpected response %+v", response)
+	}
+	for i, want := range wantUnresolved {
+		got := response.Cards[i]
+		if got.Name != want.Name || got.Reason != want.Reason || !reflect.DeepEqual(got.Suggestions, want.Suggestions) ||
+			len(got.CardIDs) != len(want.CardIDs) {
+			t.Errorf("Expected %+v, got %+v", want, got)
+		}
+	}
+	for _, id := range wantUnresolved[1].CardIDs {
+		if !slices.Contains(response.Cards[1].CardIDs, id) {
+			t.Errorf("Expected %s among the ambiguous cards %v", id, response.Cards[1].CardIDs)
+		}
+	}
+
+	for _, tt := range []struct {
+		name, query, contentType, body string
+		wantStatus                     int
+		wantError                      string
+	}{
+		{"unsupported type", "", "application/xml", "<deck/>", http.StatusUnsupportedMediaType, "unsupported_media_type"},
+		{"no type", "", "", "4 Fire Bolt", http.StatusUnsupportedMediaType, "unsupported_media_type"},
+		{"empty body", "", "text/plain", "\n", http.StatusBadRequest, "invalid_json"},
+		{"bad line", "", "text/plain", "4 Fire Bolt\n0 Mountain", http.StatusBadRequest, "invalid_deck_list"},
+		{"no cards", "", "text/csv", "quantity,name\n", http.StatusBadRequest, "invalid_deck_list"},
+		{"invalid owner", "?owner_id=nope", "text/plain", "4 Fire Bolt", http.StatusBadRequest, "invalid_id"},
+		{"invalid game", "?game_id=nope", "text/plain", "4 Fire Bolt", http.StatusBadRequest, "invalid_id"},
+		{"too many copies", "", "text/plain", "999 Fire Bolt\n1 Fire Bolt", http.StatusUnprocessableEntity, "validation_failed"},
+	} {
+		rr := serve(t, handler, "POST", "/decks/import"+tt.query, tt.body, "Content-Type", tt.contentType)
+		var response ErrorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: could not parse response body: %v", tt.name, err)
+		}
+		if rr.Code != tt.wantStatus || response.Error != tt.wantError {
+			t.Errorf("%s: expected %v %s, got %v %+v", tt.name, tt.wantStatus, tt.wantError, rr.Code, response)
+		}
+	}
+}
+
+func TestDecksHandler_ExportImportStandardDeck(t *testing.T) {
+	handler := NewDecksHandler(storage.NewMockStorage(), nil, testLogger())
+
+	// A second deck gives every playing card name a second card
+	var decks [2]models.Deck
+	for i := range decks {
+		rr := serve(t, handler, "POST", "/decks/standard", `{"name": "Poker", "jokers": 2}`, "Content-Type", "application/json")
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("create: got %v want %v: %s", rr.Code, http.StatusCreated, rr.Body.String())
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &decks[i]); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+	}
+
+	for _, format := range []string{"text", "csv", "json"} {
+		exported := serve(t, handler, "GET", "/decks/"+decks[0].ID.String()+"/export?format="+format, "")
+		if exported.Code != http.StatusOK {
+			t.Fatalf("%s export: got %v want %v: %s", format, exported.Code, http.StatusOK, exported.Body.String())
+		}
+		rr := serve(t, handler, "POST", "/decks/import?name=Poker", exported.Body.String(),
+			"Content-Type", exported.Header().Get("Content-Type"))
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("%s import: got %v want %v: %s", format, rr.Code, http.StatusCreated, rr.Body.String())
+		}
+		var imported models.Deck
+		if err := json.Unmarshal(rr.Body.Bytes(), &imported); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+		if imported.Name != "Poker" || !reflect.DeepEqual(imported.Entries, decks[0].Entries) {
+			t.Errorf("%s: expected the exported deck back, got %q with %d entries", format, imported.Name, len(imported.Entries))
+		}
+	}
+}
+
+func TestDecksHandler_ExportDeck(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewDecksHandler(mockStorage, nil, testLogger())
+	ctx := context.Background()
+
+	bolt, err := mockStorage.CreateGameCard(ctx, models.GameCard{Name: "Fire Bolt", Cost: 1})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+	image, err := mockStorage.CreateImageCard(ctx, models.ImageCard{Name: "Sunset, Again"})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+	gone := uuid.New()
+	deck := models.Deck{Name: "Burn"}
+	deck.AddCard(bolt.ID, models.CardTypeGameCard, models.SectionSideboard, 2)
+	deck.AddCard(bolt.ID, models.CardTypeGameCard, models.SectionMain, 4)
+	deck.AddCard(image.ID, models.CardTypeImageCard, models.SectionMain, 1)
+	deck.AddCard(gone, models.CardTypeGameCard, models.SectionMain, 1)
+	created, err := mockStorage.CreateDeck(ctx, deck)
+	if err != nil {
+		t.Fatalf("Failed to create test deck: %v", err)
+	}
+	path := "/decks/" + created.ID.String() + "/export"
+
+	tests := []struct {
+		query, wantType, wantBody string
+	}{
+		{"", "text/plain; charset=utf-8",
+			"4 Fire Bolt\n1 Sunset, Again\n1 " + gone.String() + "\n\nSideboard\n2 Fire Bolt\n"},
+		{"?format=csv", "text/csv; charset=utf-8",
+			"quantity,name,section\n4,Fire Bolt,main\n1,\"Sunset, Again\",main\n1," + gone.String() + ",main\n2,Fire Bolt,sideboard\n"},
+		{"?format=json", "application/json; charset=utf-8",
+			`{"name":"Burn","cards":[{"quantity":2,"name":"Fire Bolt","section":"sideboard"},` +
+				`{"quantity":4,"name":"Fire Bolt","section":"main"},{"quantity":1,"name":"Sunset, Again","section":"main"},` +
+				`{"quantity":1,"name":"` + gone.String() + `","section":"main"}]}` + "\n"},
+	}
+	for _, tt := range tests {
+		rr := serve(t, handler, "GET", path+tt.query, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%q: handler returned wrong status code: got %v want %v: %s", tt.query, rr.Code, http.StatusOK, rr.Body.String())
+		}
+		if got := rr.Header().Get("Content-Type"); got != tt.wantType {
+			t.Errorf("%q: expected Content-Type %q, got %q", tt.query, tt.wantType, got)
+		}
+		if rr.Body.String() != tt.wantBody {
+			t.Errorf("%q: expected body %q, got %q", tt.query, tt.wantBody, rr.Body.String())
+		}
+		if rr.Header().Get("ETag") != `"1"` {
+			t.Errorf("%q: expected the deck's ETag, got %q", tt.query, rr.Header().Get("ETag"))
+		}
+	}
+
+	for _, tt := range []struct {
+		name, method, path string
+		wantStatus         int
+	}{
+		{"unknown format", "GET", path + "?format=xml", http.StatusBadRequest},
+		{"missing deck", "GET", "/decks/" + uuid.New().String() + "/export", http.StatusNotFound},
+		{"invalid deck", "GET", "/decks/nope/export", http.StatusBadRequest},
+		{"wrong method", "POST", path, http.StatusMethodNotAllowed},
+	} {
+		if rr := serve(t, handler, tt.method, tt.path, ""); rr.Code != tt.wantStatus {
+			t.Errorf("%s: got %v want %v", tt.name, rr.Code, tt.wantStatus)
+		}
+	}
+}
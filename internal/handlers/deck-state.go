@@ -0,0 +1,433 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/shuffle"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// DrawResponse is returned by the draw and peek endpoints
+type DrawResponse struct {
+	Cards     []uuid.UUID `json:"cards"`
+	Remaining int         `json:"remaining"`
+}
+
+// ShuffleRequest optionally supplies the seed for a shuffle. Seed picks the
+// order directly; ClientSeed is mixed into a pending committed seed.
+type ShuffleRequest struct {
+	Seed       string `json:"seed,omitempty"`
+	ClientSeed string `json:"client_seed,omitempty"`
+}
+
+// DiscardRequest lists drawn cards to move to the discard pile
+type DiscardRequest struct {
+	Cards []uuid.UUID `json:"cards"`
+}
+
+// DeckStatesHandler serves the /states resource
+type DeckStatesHandler struct {
+	storage storage.Storage
+	logger  *slog.Logger
+}
+
+// NewDeckStatesHandler creates a new DeckStatesHandler with the given dependencies
+func NewDeckStatesHandler(storage storage.Storage, logger *slog.Logger) *DeckStatesHandler {
+	return &DeckStatesHandler{
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+func (h *DeckStatesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/states"), "/")
+	if path == "" {
+		http.Error(w, "Deck state ID required", http.StatusBadRequest)
+		return
+	}
+	stateID, action, _ := strings.Cut(path, "/")
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		// GET /states/{id} - Get deck state
+		h.getState(w, r, stateID)
+	case action == "" && r.Method == http.MethodDelete:
+		// DELETE /states/{id} - Delete deck state
+		h.deleteState(w, r, stateID)
+	case action == "shuffle" && r.Method == http.MethodPost:
+		// POST /states/{id}/shuffle - Shuffle the draw pile
+		h.shuffle(w, r, stateID)
+	case action == "commit" && r.Method == http.MethodPost:
+		// POST /states/{id}/commit - Commit to a seed for the next shuffle
+		h.commit(w, r, stateID)
+	case action == "reveal" && r.Method == http.MethodPost:
+		// POST /states/{id}/reveal - Reveal the seed of a committed shuffle
+		h.reveal(w, r, stateID)
+	case action == "draw" && r.Method == http.MethodPost:
+		// POST /states/{id}/draw?count=N - Draw from the top of the draw pile
+		h.draw(w, r, stateID)
+	case action == "peek" && r.Method == http.MethodGet:
+		// GET /states/{id}/peek?count=N - Look at the top of the draw pile
+		h.peek(w, r, stateID)
+	case action == "discard" && r.Method == http.MethodPost:
+		// POST /states/{id}/discard - Move drawn cards to the discard pile
+		h.discard(w, r, stateID)
+	case action == "reset" && r.Method == http.MethodPost:
+		// POST /states/{id}/reset - Return every card to the draw pile
+		h.reset(w, r, stateID)
+	case action == "" || action == "shuffle" || action == "commit" || action == "reveal" ||
+		action == "draw" || action == "peek" || action == "discard" || action == "reset":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// getState handles GET /states/{id}
+func (h *DeckStatesHandler) getState(w http.ResponseWriter, r *http.Request, stateID string) {
+	state, ok := h.loadState(w, r, stateID, "get_deck_state")
+	if !ok {
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, state.Redacted())
+}
+
+// deleteState handles DELETE /states/{id}
+func (h *DeckStatesHandler) deleteState(w http.ResponseWriter, r *http.Request, stateID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(stateID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid deck state ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.storage.DeleteDeckState(ctx, id); err != nil {
+		h.logger.Error("Failed to delete deck state",
+			slog.String("operation", "delete_deck_state"),
+			slog.String("state_id", stateID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck state not found", "Failed to delete deck state")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// shuffle handles POST /states/{id}/shuffle. The body is optional; without a
+// seed a random one is generated and recorded on the state.
+func (h *DeckStatesHandler) shuffle(w http.ResponseWriter, r *http.Request, stateID string) {
+	var shuffleReq ShuffleRequest
+	if !decodeOptionalJSON(w, r, &shuffleReq) {
+		return
+	}
+
+	updatedState, ok := h.modifyState(w, r, stateID, "shuffle_deck_state", func(state *models.DeckState) error {
+		switch {
+		case state.Commitment != nil && shuffleReq.Seed != "":
+			return &stateRejection{
+				status: http.StatusConflict,
+				response: ErrorResponse{
+					Error:   "commitment_pending",
+					Message: "A seed has been committed for this shuffle; supply client_seed instead of seed",
+				},
+			}
+		case state.Commitment != nil:
+			if err := state.ShuffleCommitted(shuffleReq.ClientSeed); err != nil {
+				return revealPending()
+			}
+		case shuffleReq.ClientSeed != "":
+			return &stateRejection{
+				status: http.StatusConflict,
+				response: ErrorResponse{
+					Error:   "no_commitment",
+					Message: "client_seed requires a pending commitment",
+				},
+			}
+		default:
+			seed := shuffleReq.Seed
+			if seed == "" {
+				seed = shuffle.NewSeed()
+			}
+			err := state.Shuffle(seed)
+			if errors.Is(err, models.ErrRevealPending) {
+				return revealPending()
+			}
+			if err != nil {
+				return &stateRejection{
+					status: http.StatusConflict,
+					response: ErrorResponse{
+						Error:   "commitment_pending",
+						Message: err.Error(),
+					},
+				}
+			}
+		}
+		return nil
+	})
+	if !ok {
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, updatedState.Redacted())
+}
+
+// commit handles POST /states/{id}/commit. It generates a secret server seed
+// for the next shuffle and publishes only its hash.
+func (h *DeckStatesHandler) commit(w http.ResponseWriter, r *http.Request, stateID string) {
+	updatedState, ok := h.modifyState(w, r, stateID, "commit_deck_state", func(state *models.DeckState) error {
+		if err := state.Commit(shuffle.NewSeed()); err != nil {
+			return revealPending()
+		}
+		return nil
+	})
+	if !ok {
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, updatedState.Redacted())
+}
+
+// reveal handles POST /states/{id}/reveal. It publishes the seeds of the last
+// committed shuffle so players can check them against the commitment.
+func (h *DeckStatesHandler) reveal(w http.ResponseWriter, r *http.Request, stateID string) {
+	updatedState, ok := h.modifyState(w, r, stateID, "reveal_deck_state", func(state *models.DeckState) error {
+		if err := state.Reveal(); err != nil {
+			return &stateRejection{
+				status: http.StatusConflict,
+				response: ErrorResponse{
+					Error:   "nothing_to_reveal",
+					Message: "No committed shuffle to reveal",
+				},
+			}
+		}
+		return nil
+	})
+	if !ok {
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, updatedState.Redacted())
+}
+
+// draw handles POST /states/{id}/draw?count=N
+func (h *DeckStatesHandler) draw(w http.ResponseWriter, r *http.Request, stateID string) {
+	count, ok := parseCount(w, r)
+	if !ok {
+		return
+	}
+
+	// The draw happens atomically, so concurrent draws never share a card
+	var cards []uuid.UUID
+	updatedState, ok := h.modifyState(w, r, stateID, "draw_deck_state", func(state *models.DeckState) error {
+		cards = state.Draw(count)
+		return nil
+	})
+	if !ok {
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, DrawResponse{
+		Cards:     cards,
+		Remaining: len(updatedState.DrawPile),
+	})
+}
+
+// peek handles GET /states/{id}/peek?count=N
+func (h *DeckStatesHandler) peek(w http.ResponseWriter, r *http.Request, stateID string) {
+	count, ok := parseCount(w, r)
+	if !ok {
+		return
+	}
+
+	state, ok := h.loadState(w, r, stateID, "peek_deck_state")
+	if !ok {
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, DrawResponse{
+		Cards:     state.Peek(count),
+		Remaining: len(state.DrawPile),
+	})
+}
+
+// discard handles POST /states/{id}/discard
+func (h *DeckStatesHandler) discard(w http.ResponseWriter, r *http.Request, stateID string) {
+	var discardReq DiscardRequest
+	if !decodeJSON(w, r, &discardReq) {
+		return
+	}
+
+	updatedState, ok := h.modifyState(w, r, stateID, "discard_deck_state", func(state *models.DeckState) error {
+		for _, cardID := range discardReq.Cards {
+			if !state.DiscardCard(cardID) {
+				return &stateRejection{
+					status: http.StatusBadRequest,
+					response: ErrorResponse{
+						Error:   "invalid_cards",
+						Message: "Card has not been drawn: " + cardID.String(),
+					},
+				}
+			}
+		}
+		return nil
+	})
+	if !ok {
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, updatedState.Redacted())
+}
+
+// reset handles POST /states/{id}/reset
+func (h *DeckStatesHandler) reset(w http.ResponseWriter, r *http.Request, stateID string) {
+	state, ok := h.loadState(w, r, stateID, "reset_deck_state")
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	deck, err := h.storage.GetDeck(ctx, state.DeckID)
+	if err != nil {
+		h.logger.Error("Failed to get deck for deck state",
+			slog.String("operation", "reset_deck_state"),
+			slog.String("state_id", stateID),
+			slog.String("deck_id", state.DeckID.String()),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to retrieve deck")
+		return
+	}
+
+	updatedState, ok := h.modifyState(w, r, stateID, "reset_deck_state", func(state *models.DeckState) error {
+		state.Reset(*deck)
+		return nil
+	})
+	if !ok {
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, updatedState.Redacted())
+}
+
+// loadState fetches the deck state named in the URL, writing an error
+// response and returning false if it cannot be loaded
+func (h *DeckStatesHandler) loadState(w http.ResponseWriter, r *http.Request, stateID, operation string) (*models.DeckState, bool) {
+	// Validate UUID format
+	id, err := uuid.Parse(stateID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid deck state ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return nil, false
+	}
+
+	ctx := r.Context()
+	state, err := h.storage.GetDeckState(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to get deck state",
+			slog.String("operation", operation),
+			slog.String("state_id", stateID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck state not found", "Failed to retrieve deck state")
+		return nil, false
+	}
+
+	return state, true
+}
+
+// stateRejection is returned from a modifyState callback to abandon the
+// change and send the client an error response
+type stateRejection struct {
+	status   int
+	response ErrorResponse
+}
+
+func (e *stateRejection) Error() string {
+	return e.response.Message
+}
+
+// revealPending rejects a shuffle or commit that would replace the record of
+// an unrevealed committed shuffle, losing its server seed
+func revealPending() *stateRejection {
+	return &stateRejection{
+		status: http.StatusConflict,
+		response: ErrorResponse{
+			Error:   "reveal_pending",
+			Message: "The last committed shuffle must be revealed first",
+		},
+	}
+}
+
+// modifyState atomically applies fn to the deck state named in the URL and
+// saves it, writing an error response and returning false if that fails
+func (h *DeckStatesHandler) modifyState(w http.ResponseWriter, r *http.Request, stateID, operation string, fn func(state *models.DeckState) error) (*models.DeckState, bool) {
+	// Validate UUID format
+	id, err := uuid.Parse(stateID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid deck state ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return nil, false
+	}
+
+	ctx := r.Context()
+	updatedState, err := h.storage.ModifyDeckState(ctx, id, func(state *models.DeckState) error {
+		if err := fn(state); err != nil {
+			return err
+		}
+		state.UpdatedAt = time.Now().UTC()
+		return nil
+	})
+	if err != nil {
+		var rejection *stateRejection
+		if errors.As(err, &rejection) {
+			writeJSONResponse(w, rejection.status, rejection.response)
+			return nil, false
+		}
+
+		h.logger.Error("Failed to update deck state",
+			slog.String("operation", operation),
+			slog.String("state_id", stateID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck state not found", "Failed to update deck state")
+		return nil, false
+	}
+
+	return updatedState, true
+}
+
+// parseCount reads the ?count= query parameter, defaulting to 1
+func parseCount(w http.ResponseWriter, r *http.Request) (int, bool) {
+	raw := r.URL.Query().Get("count")
+	if raw == "" {
+		return 1, true
+	}
+
+	count, err := strconv.Atoi(raw)
+	if err != nil || count < 1 {
+		response := ErrorResponse{
+			Error:   "invalid_count",
+			Message: "count must be a positive integer",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return 0, false
+	}
+
+	return count, true
+}
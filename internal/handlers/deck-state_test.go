@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/shuffle"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// newTestDeckState creates a deck of the given size and a deck state for it
+func newTestDeckState(t *testing.T, sto storage.Storage, size int) (*models.Deck, *models.DeckState) {
+	t.Helper()
+
+	ctx := context.Background()
+	deck := models.Deck{Name: "State Deck"}
+	for i := 0; i < size; i++ {
+		card, err := sto.CreateGameCard(ctx, models.GameCard{Name: "Card"})
+		if err != nil {
+			t.Fatalf("Failed to create test card: %v", err)
+		}
+		deck.Cards = append(deck.Cards, card.ID)
+	}
+	createdDeck, err := sto.CreateDeck(ctx, deck)
+	if err != nil {
+		t.Fatalf("Failed to create test deck: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", "/decks/"+createdDeck.ID.String()+"/states", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	NewDecksHandler(sto, nil, testLogger()).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v",
+			status, http.StatusCreated)
+	}
+
+	var state models.DeckState
+	if err := json.Unmarshal(rr.Body.Bytes(), &state); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	return createdDeck, &state
+}
+
+func TestDecksHandler_CreateDeckState(t *testing.T) {
+	deck, state := newTestDeckState(t, storage.NewMockStorage(), 5)
+
+	if state.ID == uuid.Nil {
+		t.Error("Expected deck state to have a generated ID")
+	}
+	if state.DeckID != deck.ID {
+		t.Errorf("Expected deck ID %s, got %s", deck.ID, state.DeckID)
+	}
+	for i, cardID := range deck.Cards {
+		if state.DrawPile[i] != cardID {
+			t.Fatalf("Expected draw pile in deck order, got %v", state.DrawPile)
+		}
+	}
+
+	// Unknown deck
+	req, err := http.NewRequest("POST", "/decks/"+uuid.New().String()+"/states", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	NewDecksHandler(storage.NewMockStorage(), nil, testLogger()).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusNotFound)
+	}
+}
+
+func TestDeckStatesHandler_DrawPeekDiscardReset(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	deck, state := newTestDeckState(t, mockStorage, 5)
+	handler := NewDeckStatesHandler(mockStorage, testLogger())
+	base := "/states/" + state.ID.String()
+
+	serve := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, path, bytes.NewBuffer(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Peek does not remove cards
+	rr := serve("GET", base+"/peek?count=2", nil)
+	var peeked DrawResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &peeked); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if len(peeked.Cards) != 2 || peeked.Remaining != 5 {
+		t.Errorf("Expected to peek 2 of 5 cards, got %+v", peeked)
+	}
+
+	// Draw removes cards from the top
+	rr = serve("POST", base+"/draw?count=3", nil)
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+	var drawn DrawResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &drawn); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if len(drawn.Cards) != 3 || drawn.Remaining != 2 {
+		t.Errorf("Expected to draw 3 cards leaving 2, got %+v", drawn)
+	}
+	if drawn.Cards[0] != deck.Cards[0] || drawn.Cards[1] != peeked.Cards[1] {
+		t.Errorf("Expected to draw from the top of the pile, got %v", drawn.Cards)
+	}
+
+	// Drawing more than remain returns what is left
+	rr = serve("POST", base+"/draw?count=10", nil)
+	if err := json.Unmarshal(rr.Body.Bytes(), &drawn); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if len(drawn.Cards) != 2 || drawn.Remaining != 0 {
+		t.Errorf("Expected to draw the last 2 cards, got %+v", drawn)
+	}
+
+	// Discard a drawn card
+	body, _ := json.Marshal(DiscardRequest{Cards: []uuid.UUID{deck.Cards[0]}})
+	rr = serve("POST", base+"/discard", body)
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+	var discarded models.DeckState
+	if err := json.Unmarshal(rr.Body.Bytes(), &discarded); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if len(discarded.Discard) != 1 || len(discarded.Drawn) != 4 {
+		t.Errorf("Expected 1 discarded and 4 drawn cards, got %+v", discarded)
+	}
+
+	// Discarding a card that is not in hand fails
+	rr = serve("POST", base+"/discard", body)
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusBadRequest)
+	}
+
+	// Reset returns everything to the draw pile
+	rr = serve("POST", base+"/reset", nil)
+	var reset models.DeckState
+	if err := json.Unmarshal(rr.Body.Bytes(), &reset); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if len(reset.DrawPile) != 5 || len(reset.Drawn) != 0 || len(reset.Discard) != 0 {
+		t.Errorf("Expected a full draw pile after reset, got %+v", reset)
+	}
+}
+
+func TestDeckStatesHandler_ConcurrentDraws(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	deck, state := newTestDeckState(t, mockStorage, 30)
+	handler := NewDeckStatesHandler(mockStorage, testLogger())
+
+	var mu sync.Mutex
+	seen := make(map[uuid.UUID]int)
+	var wg sync.WaitGroup
+	for i := 0; i < len(deck.Cards); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest("POST", "/states/"+state.ID.String()+"/draw", nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			var drawn DrawResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &drawn); err != nil {
+				t.Errorf("Could not parse response body: %v", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range drawn.Cards {
+				seen[id]++
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every card is drawn exactly once
+	if len(seen) != len(deck.Cards) {
+		t.Errorf("Expected %d distinct cards drawn, got %d", len(deck.Cards), len(seen))
+	}
+	for id, count := range seen {
+		if count != 1 {
+			t.Errorf("Card %s drawn %d times", id, count)
+		}
+	}
+}
+
+func TestDeckStatesHandler_Shuffle(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	_, state := newTestDeckState(t, mockStorage, 20)
+	handler := NewDeckStatesHandler(mockStorage, testLogger())
+
+	req, err := http.NewRequest("POST", "/states/"+state.ID.String()+"/shuffle", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	var shuffled models.DeckState
+	if err := json.Unmarshal(rr.Body.Bytes(), &shuffled); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+
+	seen := make(map[uuid.UUID]int)
+	for _, id := range state.DrawPile {
+		seen[id]++
+	}
+	for _, id := range shuffled.DrawPile {
+		seen[id]--
+	}
+	for id, n := range seen {
+		if n != 0 {
+			t.Errorf("Shuffle changed the cards in the draw pile: %s off by %d", id, n)
+		}
+	}
+}
+
+func TestDeckStatesHandler_Errors(t *testing.T) {
+	handler := NewDeckStatesHandler(storage.NewMockStorage(), testLogger())
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{"missing state", "GET", "/states/" + uuid.New().String(), http.StatusNotFound},
+		{"invalid id", "GET", "/states/invalid-id", http.StatusBadRequest},
+		{"invalid count", "POST", "/states/" + uuid.New().String() + "/draw?count=0", http.StatusBadRequest},
+		{"wrong method", "GET", "/states/" + uuid.New().String() + "/draw", http.StatusMethodNotAllowed},
+		{"unknown action", "POST", "/states/" + uuid.New().String() + "/juggle", http.StatusNotFound},
+		{"delete missing", "DELETE", "/states/" + uuid.New().String(), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					status, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestDeckStatesHandler_SeededShuffle(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	_, state := newTestDeckState(t, mockStorage, 20)
+	_, otherState := newTestDeckState(t, mockStorage, 0)
+	handler := NewDeckStatesHandler(mockStorage, testLogger())
+
+	shuffleWithSeed := func(stateID uuid.UUID) models.DeckState {
+		body, _ := json.Marshal(ShuffleRequest{Seed: "replay-42"})
+		req, err := http.NewRequest("POST", "/states/"+stateID.String()+"/shuffle", bytes.NewBuffer(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v",
+				status, http.StatusOK)
+		}
+		var shuffled models.DeckState
+		if err := json.Unmarshal(rr.Body.Bytes(), &shuffled); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+		return shuffled
+	}
+
+	first := shuffleWithSeed(state.ID)
+	if first.LastShuffle == nil || first.LastShuffle.Seed != "replay-42" || first.LastShuffle.Algorithm != shuffle.Algorithm {
+		t.Fatalf("Expected shuffle record with seed and algorithm, got %+v", first.LastShuffle)
+	}
+
+	// Replaying the recorded shuffle reproduces the order
+	replayed := append([]uuid.UUID{}, first.LastShuffle.Input...)
+	shuffle.Shuffle(first.LastShuffle.Seed, replayed)
+	for i := range replayed {
+		if replayed[i] != first.DrawPile[i] {
+			t.Fatalf("Replayed shuffle differs at %d: got %v want %v", i, replayed, first.DrawPile)
+		}
+	}
+
+	// An empty draw pile shuffles without error
+	shuffleWithSeed(otherState.ID)
+}
+
+func TestDeckStatesHandler_CommitReveal(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	_, state := newTestDeckState(t, mockStorage, 10)
+	handler := NewDeckStatesHandler(mockStorage, testLogger())
+	base := "/states/" + state.ID.String()
+
+	serve := func(method, path string, body []byte, expectedStatus int) models.DeckState {
+		req, err := http.NewRequest(method, path, bytes.NewBuffer(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != expectedStatus {
+			t.Fatalf("%s %s returned wrong status code: got %v want %v",
+				method, path, status, expectedStatus)
+		}
+		var result models.DeckState
+		_ = json.Unmarshal(rr.Body.Bytes(), &result)
+		return result
+	}
+
+	// Nothing to reveal yet
+	serve("POST", base+"/reveal", nil, http.StatusConflict)
+
+	committed := serve("POST", base+"/commit", nil, http.StatusOK)
+	if committed.Commitment == nil || committed.Commitment.Commitment == "" {
+		t.Fatalf("Expected a published commitment, got %+v", committed.Commitment)
+	}
+	if committed.Commitment.ServerSeed != "" {
+		t.Error("Expected the server seed to be withheld")
+	}
+
+	// A chosen seed cannot override the commitment
+	seedBody, _ := json.Marshal(ShuffleRequest{Seed: "cheat"})
+	serve("POST", base+"/shuffle", seedBody, http.StatusConflict)
+
+	clientBody, _ := json.Marshal(ShuffleRequest{ClientSeed: "table-7"})
+	shuffled := serve("POST", base+"/shuffle", clientBody, http.StatusOK)
+	if shuffled.LastShuffle.ServerSeed != "" || shuffled.LastShuffle.Seed != "" {
+		t.Error("Expected seeds to stay hidden until revealed")
+	}
+	if shuffled.LastShuffle.Commitment != committed.Commitment.Commitment {
+		t.Error("Expected the shuffle to record the published commitment")
+	}
+
+	revealed := serve("POST", base+"/reveal", nil, http.StatusOK)
+	record := revealed.LastShuffle
+	if !shuffle.Verify(record.ServerSeed, committed.Commitment.Commitment) {
+		t.Fatal("Expected the revealed server seed to match the commitment")
+	}
+
+	replayed := append([]uuid.UUID{}, record.Input...)
+	shuffle.Shuffle(shuffle.Combine(record.ServerSeed, record.ClientSeed), replayed)
+	for i := range replayed {
+		if replayed[i] != shuffled.DrawPile[i] {
+			t.Fatalf("Replayed shuffle differs at %d", i)
+		}
+	}
+}
+
+func TestDeckStatesHandler_RevealPending(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	_, state := newTestDeckState(t, mockStorage, 10)
+	handler := NewDeckStatesHandler(mockStorage, testLogger())
+	base := "/states/" + state.ID.String()
+
+	rr := serve(t, handler, "POST", base+"/commit", "")
+	var committed models.DeckState
+	if err := json.Unmarshal(rr.Body.Bytes(), &committed); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	rr = serve(t, handler, "POST", base+"/shuffle", `{"client_seed": "table-7"}`)
+	var shuffled models.DeckState
+	if err := json.Unmarshal(rr.Body.Bytes(), &shuffled); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+
+	// Neither a second shuffle nor a new commitment may replace the
+	// unrevealed record
+	for _, tt := range []struct{ action, body string }{
+		{"shuffle", ""},
+		{"shuffle", `{"seed": "replay-42"}`},
+		{"commit", ""},
+	} {
+		rr := serve(t, handler, "POST", base+"/"+tt.action, tt.body)
+		var response ErrorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+		if rr.Code != http.StatusConflict || response.Error != "reveal_pending" {
+			t.Errorf("%s %s: expected 409 reveal_pending, got %v %q", tt.action, tt.body, rr.Code, response.Error)
+		}
+	}
+
+	// The dealt shuffle can still be verified
+	rr = serve(t, handler, "POST", base+"/reveal", "")
+	var revealed models.DeckState
+	if err := json.Unmarshal(rr.Body.Bytes(), &revealed); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if !shuffle.Verify(revealed.LastShuffle.ServerSeed, committed.Commitment.Commitment) {
+		t.Fatal("Expected the revealed server seed to match the first commitment")
+	}
+	if !slices.Equal(revealed.DrawPile, shuffled.DrawPile) {
+		t.Error("Expected the rejected requests to leave the draw pile alone")
+	}
+
+	// Once revealed, shuffling works again
+	if rr := serve(t, handler, "POST", base+"/shuffle", ""); rr.Code != http.StatusOK {
+		t.Errorf("shuffle after reveal: got %v want %v", rr.Code, http.StatusOK)
+	}
+}
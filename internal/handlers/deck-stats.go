@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+)
+
+// deckStats handles GET /decks/{id}/stats?hand_size=N, the makeup of a deck
+// and the odds of its opening hand
+func (h *DecksHandler) deckStats(w http.ResponseWriter, r *http.Request, deckID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(deckID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid deck ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	handSize := models.DefaultHandSize
+	if raw := r.URL.Query().Get("hand_size"); raw != "" {
+		handSize, err = strconv.Atoi(raw)
+		if err != nil || handSize < 1 {
+			response := ErrorResponse{
+				Error:   "invalid_hand_size",
+				Message: "hand_size must be a positive integer",
+			}
+			writeJSONResponse(w, http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	deck, err := h.storage.GetDeck(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to get deck",
+			slog.String("operation", "get_deck_stats"),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to retrieve deck")
+		return
+	}
+
+	cards, err := gameCards(ctx, h.storage, deck)
+	if err != nil {
+		h.logger.Error("Failed to look up deck cards",
+			slog.String("operation", "get_deck_stats"),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to retrieve deck cards")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, models.NewDeckStats(deck, cards, handSize))
+}
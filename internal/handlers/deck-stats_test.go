@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+func TestDecksHandler_DeckStats(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewDecksHandler(mockStorage, nil, testLogger())
+	ctx := context.Background()
+
+	newCard := func(card models.GameCard) uuid.UUID {
+		created, err := mockStorage.CreateGameCard(ctx, card)
+		if err != nil {
+			t.Fatalf("Failed to create test card: %v", err)
+		}
+		return created.ID
+	}
+	land := newCard(models.GameCard{Name: "Mountain", IsResource: true})
+	bolt := newCard(models.GameCard{Name: "Fire Bolt", Cost: 1, Offense: 6, Colors: []string{"Red"}, Keywords: []string{"Haste"}})
+	wyrm := newCard(models.GameCard{Name: "Ancient Wyrm", Cost: 5, Offense: 6, Defense: 6,
+		Colors: []string{"Red", "Green"}, Keywords: []string{"Flying"}})
+	image, err := mockStorage.CreateImageCard(ctx, models.ImageCard{Name: "Sunset"})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+
+	deck := models.Deck{Name: "Burn"}
+	deck.AddCard(land, models.CardTypeGameCard, models.SectionMain, 4)
+	deck.AddCard(bolt, models.CardTypeGameCard, models.SectionMain, 2)
+	deck.AddCard(wyrm, models.CardTypeGameCard, models.SectionCommander, 1)
+	deck.AddCard(image.ID, models.CardTypeImageCard, models.SectionMain, 1)
+	deck.AddCard(bolt, models.CardTypeGameCard, models.SectionSideboard, 3)
+	created, err := mockStorage.CreateDeck(ctx, deck)
+	if err != nil {
+		t.Fatalf("Failed to create test deck: %v", err)
+	}
+	path := "/decks/" + created.ID.String() + "/stats"
+
+	rr := serve(t, handler, "GET", path+"?hand_size=2", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var stats models.DeckStats
+	if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+
+	// The sideboard is not counted, and the image card only in the totals
+	if stats.Cards != 8 || stats.GameCards != 7 || stats.Resources != 4 || stats.NonResources != 3 || stats.ResourceRatio != 0.5 {
+		t.Errorf("Unexpected counts: %+v", stats)
+	}
+	if len(stats.CostCurve) != 2 || stats.CostCurve[1] != 2 || stats.CostCurve[5] != 1 {
+		t.Errorf("Expected a curve of two 1s and a 5, got %v", stats.CostCurve)
+	}
+	if len(stats.Colors) != 2 || stats.Colors["Red"] != 3 || stats.Colors["Green"] != 1 || stats.Colorless != 4 {
+		t.Errorf("Unexpected colors %v with %d colorless", stats.Colors, stats.Colorless)
+	}
+	if len(stats.Keywords) != 2 || stats.Keywords["Haste"] != 2 || stats.Keywords["Flying"] != 1 {
+		t.Errorf("Unexpected keywords %v", stats.Keywords)
+	}
+	for _, tt := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"average cost", stats.AverageCost, 7.0 / 3},
+		{"average offense", stats.AverageOffense, 6},
+		{"average defense", stats.AverageDefense, 2},
+		// 2 cards from 8 with 4 resources: C(4,k) C(4,2-k) / C(8,2)
+		{"no resources", stats.OpeningHand.Resources[0], 6.0 / 28},
+		{"one resource", stats.OpeningHand.Resources[1], 16.0 / 28},
+		{"two resources", stats.OpeningHand.Resources[2], 6.0 / 28},
+		{"at least one resource", stats.OpeningHand.AtLeastOneResource, 22.0 / 28},
+		{"lands first", stats.OpeningHand.Cards[0].AtLeastOne, 22.0 / 28},
+		{"then bolts", stats.OpeningHand.Cards[1].AtLeastOne, 1 - 15.0/28},
+		{"then a single card", stats.OpeningHand.Cards[2].AtLeastOne, 1 - 21.0/28},
+	} {
+		if math.Abs(tt.got-tt.want) > 1e-9 {
+			t.Errorf("%s: got %v want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if stats.OpeningHand.Size != 2 || len(stats.OpeningHand.Resources) != 3 || len(stats.OpeningHand.Cards) != 4 ||
+		stats.OpeningHand.Cards[0].CardID != land || stats.OpeningHand.Cards[1].CardID != bolt {
+		t.Errorf("Unexpected opening hand %+v", stats.OpeningHand)
+	}
+
+	// The hand defaults to 7, which must hold a resource with 4 of 8 cards
+	rr = serve(t, handler, "GET", path, "")
+	if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if stats.OpeningHand.Size != 7 || stats.OpeningHand.AtLeastOneResource != 1 {
+		t.Errorf("Expected a certain resource in 7 cards, got %+v", stats.OpeningHand)
+	}
+
+	for _, tt := range []struct {
+		name, method, path string
+		wantStatus         int
+	}{
+		{"invalid hand size", "GET", path + "?hand_size=0", http.StatusBadRequest},
+		{"missing deck", "GET", "/decks/" + uuid.New().String() + "/stats", http.StatusNotFound},
+		{"wrong method", "POST", path, http.StatusMethodNotAllowed},
+	} {
+		if rr := serve(t, handler, tt.method, tt.path, ""); rr.Code != tt.wantStatus {
+			t.Errorf("%s: got %v want %v", tt.name, rr.Code, tt.wantStatus)
+		}
+	}
+}
@@ -0,0 +1,752 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// StandardDeckRequest configures POST /decks/standard
+type StandardDeckRequest struct {
+	Name          string     `json:"name"`
+	OwnerID       *uuid.UUID `json:"owner_id,omitempty"`
+	Jokers        int        `json:"jokers"` // jokers per deck, 0-2
+	Decks         int        `json:"decks"`  // number of decks in the shoe, defaults to 1
+	FrontImageURL string     `json:"front_image_url"`
+	BackImageURL  string     `json:"back_image_url"`
+}
+
+// Validate checks the deck name, as a deck would, and the image URLs copied
+// onto every card of the deck
+func (req *StandardDeckRequest) Validate() []models.FieldError {
+	errs := (&models.Deck{Name: req.Name}).Validate()
+	errs = append(errs, models.ValidateURL("front_image_url", req.FrontImageURL)...)
+	return append(errs, models.ValidateURL("back_image_url", req.BackImageURL)...)
+}
+
+const maxShoeDecks = 8
+
+// DeckCardRequest adds or removes copies of a card in one section of a deck.
+// Quantity defaults to 1 and Section to main.
+type DeckCardRequest struct {
+	CardID   uuid.UUID `json:"card_id"`
+	Quantity int       `json:"quantity"`
+	Section  string    `json:"section"`
+}
+
+// Validate checks the request once its defaults are set
+func (req *DeckCardRequest) Validate() []models.FieldError {
+	return validateDeckCard(req.CardID, req.Quantity, map[string]string{"section": req.Section})
+}
+
+// MoveDeckCardRequest moves copies of a card from one section of a deck to
+// another. Quantity defaults to 1.
+type MoveDeckCardRequest struct {
+	CardID   uuid.UUID `json:"card_id"`
+	Quantity int       `json:"quantity"`
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+}
+
+// Validate checks the request once its defaults are set
+func (req *MoveDeckCardRequest) Validate() []models.FieldError {
+	return validateDeckCard(req.CardID, req.Quantity, map[string]string{"from": req.From, "to": req.To})
+}
+
+// validateDeckCard checks the fields shared by the deck card requests
+func validateDeckCard(cardID uuid.UUID, quantity int, sections map[string]string) []models.FieldError {
+	var errs []models.FieldError
+	if cardID == uuid.Nil {
+		errs = append(errs, models.FieldError{Field: "card_id", Message: "is required"})
+	}
+	if quantity < 1 || quantity > models.MaxQuantity {
+		errs = append(errs, models.FieldError{
+			Field:   "quantity",
+			Message: fmt.Sprintf("must be between 1 and %d", models.MaxQuantity),
+		})
+	}
+	for _, field := range slices.Sorted(maps.Keys(sections)) {
+		if !slices.Contains(models.DeckSections, sections[field]) {
+			errs = append(errs, models.FieldError{
+				Field:   field,
+				Message: "must be one of: " + strings.Join(models.DeckSections, ", "),
+			})
+		}
+	}
+	return errs
+}
+
+// DecksHandler serves the /decks resource
+type DecksHandler struct {
+	storage  storage.Storage
+	goldfish *GoldfishHandler
+	logger   *slog.Logger
+}
+
+// NewDecksHandler creates a new DecksHandler with the given dependencies.
+// goldfish serves POST /decks/{id}/goldfish.
+func NewDecksHandler(storage storage.Storage, goldfish *GoldfishHandler, logger *slog.Logger) *DecksHandler {
+	return &DecksHandler{
+		storage:  storage,
+		goldfish: goldfish,
+		logger:   logger,
+	}
+}
+
+func (h *DecksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/decks")
+
+	// Sub-resources of a single deck, e.g. /decks/{id}/states
+	if deckID, subresource, found := strings.Cut(strings.Trim(path, "/"), "/"); found {
+		switch {
+		case subresource == "states" && r.Method == http.MethodPost:
+			// POST /decks/{id}/states - Start a new deck state
+			h.createDeckState(w, r, deckID)
+		case subresource == "cards" && r.Method == http.MethodPost:
+			// POST /decks/{id}/cards - Add copies of a card to a section
+			h.addDeckCard(w, r, deckID)
+		case subresource == "cards/remove" && r.Method == http.MethodPost:
+			// POST /decks/{id}/cards/remove - Remove copies of a card from a section
+			h.removeDeckCard(w, r, deckID)
+		case subresource == "cards/move" && r.Method == http.MethodPost:
+			// POST /decks/{id}/cards/move - Move copies of a card between sections
+			h.moveDeckCard(w, r, deckID)
+		case subresource == "legality" && r.Method == http.MethodGet:
+			// GET /decks/{id}/legality?format= - Check the deck against a format
+			h.deckLegality(w, r, deckID)
+		case subresource == "stats" && r.Method == http.MethodGet:
+			// GET /decks/{id}/stats?hand_size=N - Summarize the deck
+			h.deckStats(w, r, deckID)
+		case subresource == "export" && r.Method == http.MethodGet:
+			// GET /decks/{id}/export?format= - Write the deck as a deck list
+			h.exportDeck(w, r, deckID)
+		case subresource == "goldfish" && r.Method == http.MethodPost:
+			// POST /decks/{id}/goldfish - Start a goldfish simulation
+			h.goldfish.startGoldfish(w, r, deckID)
+		case subresource == "states" || subresource == "cards" || subresource == "cards/remove" ||
+			subresource == "cards/move" || subresource == "legality" || subresource == "stats" ||
+			subresource == "export" || subresource == "goldfish":
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if path == "" || path == "/" {
+			// GET /decks - List all decks
+			h.listDecks(w, r)
+		} else {
+			// GET /decks/{id} - Get specific deck
+			deckID := strings.Trim(path, "/")
+			h.getDeck(w, r, deckID)
+		}
+
+	case http.MethodPost:
+		if path == "" || path == "/" {
+			// POST /decks - Create new deck
+			h.createDeck(w, r)
+		} else if strings.Trim(path, "/") == "standard" {
+			// POST /decks/standard - Create a standard 52-card deck
+			h.createStandardDeck(w, r)
+		} else if strings.Trim(path, "/") == "import" {
+			// POST /decks/import - Create a deck from a deck list
+			h.importDeck(w, r)
+		} else {
+			http.Error(w, "Method not allowed for this path", http.StatusMethodNotAllowed)
+		}
+
+	case http.MethodPut:
+		if path != "" && path != "/" {
+			// PUT /decks/{id} - Update deck
+			deckID := strings.Trim(path, "/")
+			h.updateDeck(w, r, deckID)
+		} else {
+			http.Error(w, "Deck ID required for update", http.StatusBadRequest)
+		}
+
+	case http.MethodPatch:
+		if path != "" && path != "/" {
+			// PATCH /decks/{id} - Partially update deck
+			deckID := strings.Trim(path, "/")
+			h.patchDeck(w, r, deckID)
+		} else {
+			http.Error(w, "Method not allowed for this path", http.StatusMethodNotAllowed)
+		}
+
+	case http.MethodDelete:
+		if path != "" && path != "/" {
+			// DELETE /decks/{id} - Delete deck
+			deckID := strings.Trim(path, "/")
+			h.deleteDeck(w, r, deckID)
+		} else {
+			http.Error(w, "Deck ID required for deletion", http.StatusBadRequest)
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listDecks handles GET /decks, optionally filtered by ?owner_id=
+func (h *DecksHandler) listDecks(w http.ResponseWriter, r *http.Request) {
+	var ownerID *uuid.UUID
+	if raw := r.URL.Query().Get("owner_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			response := ErrorResponse{
+				Error:   "invalid_id",
+				Message: "Invalid owner ID format",
+			}
+			writeJSONResponse(w, http.StatusBadRequest, response)
+			return
+		}
+		ownerID = &id
+	}
+
+	ctx := r.Context()
+	decks, err := h.storage.ListDecks(ctx, ownerID)
+	if err != nil {
+		h.logger.Error("Failed to list decks",
+			slog.String("operation", "list_decks"),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to retrieve decks")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, decks)
+}
+
+// getDeck handles GET /decks/{id}
+func (h *DecksHandler) getDeck(w http.ResponseWriter, r *http.Request, deckID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(deckID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid deck ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	ctx := r.Context()
+	deck, err := h.storage.GetDeck(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to get deck",
+			slog.String("operation", "get_deck"),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to retrieve deck")
+		return
+	}
+
+	setETag(w, deck.Version)
+	writeJSONResponse(w, http.StatusOK, deck)
+}
+
+// createDeck handles POST /decks
+func (h *DecksHandler) createDeck(w http.ResponseWriter, r *http.Request) {
+	var deck models.Deck
+	if !decodeJSON(w, r, &deck) || !validateModel(w, &deck, "deck") {
+		return
+	}
+
+	ctx := r.Context()
+	if !h.validateCards(ctx, w, &deck) {
+		return
+	}
+
+	createdDeck, err := h.storage.CreateDeck(ctx, deck)
+	if err != nil {
+		h.logger.Error("Failed to create deck",
+			slog.String("operation", "create_deck"),
+			slog.String("deck_name", deck.Name),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to create deck")
+		return
+	}
+
+	setETag(w, createdDeck.Version)
+	writeJSONResponse(w, http.StatusCreated, createdDeck)
+}
+
+// createStandardDeck handles POST /decks/standard. It creates the 52
+// playing cards (plus any jokers) once and a deck that references them once
+// per deck in the shoe.
+func (h *DecksHandler) createStandardDeck(w http.ResponseWriter, r *http.Request) {
+	var deckReq StandardDeckRequest
+	if !decodeJSON(w, r, &deckReq) || !validateModel(w, &deckReq, "standard deck request") {
+		return
+	}
+
+	if deckReq.Decks == 0 {
+		deckReq.Decks = 1
+	}
+	if deckReq.Decks < 1 || deckReq.Decks > maxShoeDecks || deckReq.Jokers < 0 || deckReq.Jokers > 2 {
+		response := ErrorResponse{
+			Error:   "invalid_request",
+			Message: fmt.Sprintf("decks must be between 1 and %d and jokers between 0 and 2", maxShoeDecks),
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+	if deckReq.Name == "" {
+		deckReq.Name = "Standard Deck"
+	}
+
+	ctx := r.Context()
+	cardIDs := make([]uuid.UUID, 0, 52+deckReq.Jokers)
+	for _, card := range models.NewStandardPlayingCards(deckReq.Jokers) {
+		card.FrontImageURL = deckReq.FrontImageURL
+		card.BackImageURL = deckReq.BackImageURL
+
+		createdCard, err := h.storage.CreatePlayingCard(ctx, card)
+		if err != nil {
+			h.logger.Error("Failed to create playing card",
+				slog.String("operation", "create_standard_deck"),
+				slog.String("card_name", card.GetName()),
+				slog.Any("error", err))
+			h.deletePlayingCards(ctx, cardIDs)
+			writeStandardDeckError(w, err)
+			return
+		}
+		cardIDs = append(cardIDs, createdCard.ID)
+	}
+
+	deck := models.Deck{
+		Name:    deckReq.Name,
+		OwnerID: deckReq.OwnerID,
+		Entries: make([]models.DeckEntry, 0, len(cardIDs)),
+	}
+	if deckReq.BackImageURL != "" {
+		deck.BackImageURL = &deckReq.BackImageURL
+	}
+	for _, cardID := range cardIDs {
+		deck.AddCard(cardID, models.CardTypePlayingCard, models.SectionMain, deckReq.Decks)
+	}
+
+	createdDeck, err := h.storage.CreateDeck(ctx, deck)
+	if err != nil {
+		h.logger.Error("Failed to create deck",
+			slog.String("operation", "create_standard_deck"),
+			slog.String("deck_name", deck.Name),
+			slog.Any("error", err))
+		h.deletePlayingCards(ctx, cardIDs)
+		writeStandardDeckError(w, err)
+		return
+	}
+
+	setETag(w, createdDeck.Version)
+	writeJSONResponse(w, http.StatusCreated, createdDeck)
+}
+
+// writeStandardDeckError reports a failure to store a standard deck. The
+// request names no stored record, so every failure but ErrUnavailable is the
+// server's fault, whatever kind of storage error it is.
+func writeStandardDeckError(w http.ResponseWriter, err error) {
+	if errors.Is(err, storage.ErrUnavailable) {
+		writeStorageError(w, err, "", "Failed to create standard deck")
+		return
+	}
+	response := ErrorResponse{
+		Error:   "internal_error",
+		Message: "Failed to create standard deck",
+	}
+	writeJSONResponse(w, http.StatusInternalServerError, response)
+}
+
+// deletePlayingCards removes the cards a failed standard deck request
+// created, so that a retry does not leave duplicates behind. It carries on
+// after the request is canceled and only logs its own failures.
+func (h *DecksHandler) deletePlayingCards(ctx context.Context, ids []uuid.UUID) {
+	ctx = context.WithoutCancel(ctx)
+	for _, id := range ids {
+		if err := h.storage.DeletePlayingCard(ctx, id); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			h.logger.Error("Failed to delete playing card",
+				slog.String("operation", "create_standard_deck"),
+				slog.String("card_id", id.String()),
+				slog.Any("error", err))
+		}
+	}
+}
+
+// updateDeck handles PUT /decks/{id}
+func (h *DecksHandler) updateDeck(w http.ResponseWriter, r *http.Request, deckID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(deckID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid deck ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	var deck models.Deck
+	if !decodeJSON(w, r, &deck) || !validateModel(w, &deck, "deck") {
+		return
+	}
+
+	// Set the ID from the URL path. The version to replace comes from
+	// If-Match, not the body.
+	deck.ID = id
+	deck.Version, err = ifMatchVersion(r, id, h.deckVersion)
+	if err != nil {
+		h.logger.Error("Failed to update deck",
+			slog.String("operation", "update_deck"),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeWriteError(w, r, err, "Deck not found", "Failed to update deck")
+		return
+	}
+	h.saveDeck(w, r, deck)
+}
+
+// patchDeck handles PATCH /decks/{id}, applying a JSON merge patch or JSON
+// Patch to the stored deck
+func (h *DecksHandler) patchDeck(w http.ResponseWriter, r *http.Request, deckID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(deckID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid deck ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	ctx := r.Context()
+	current, err := h.storage.GetDeck(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to get deck",
+			slog.String("operation", "patch_deck"),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to update deck")
+		return
+	}
+	if !ifMatches(r, current.Version) {
+		writeWriteError(w, r, errPreconditionFailed, "Deck not found", "Failed to update deck")
+		return
+	}
+
+	var deck models.Deck
+	if !applyPatch(w, r, current, &deck, "deck") || !validateModel(w, &deck, "deck") {
+		return
+	}
+
+	// A patch that changes only the flat list of cards replaces the main
+	// section; otherwise the entries win
+	if !slices.Equal(deck.Cards, current.Cards) && slices.Equal(deck.Entries, current.Entries) {
+		deck.UseCards()
+	}
+
+	// The ID cannot be patched, and the stored deck must still be the
+	// version the patch was applied to
+	deck.ID = id
+	deck.Version = current.Version
+	h.saveDeck(w, r, deck)
+}
+
+// addDeckCard handles POST /decks/{id}/cards
+func (h *DecksHandler) addDeckCard(w http.ResponseWriter, r *http.Request, deckID string) {
+	cardReq := DeckCardRequest{Quantity: 1, Section: models.SectionMain}
+	if !decodeJSON(w, r, &cardReq) || !validateModel(w, &cardReq, "request") {
+		return
+	}
+
+	h.modifyDeck(w, r, deckID, "add_deck_card", func(deck *models.Deck) error {
+		// validateCards sets the card type
+		deck.AddCard(cardReq.CardID, "", cardReq.Section, cardReq.Quantity)
+		return nil
+	})
+}
+
+// removeDeckCard handles POST /decks/{id}/cards/remove
+func (h *DecksHandler) removeDeckCard(w http.ResponseWriter, r *http.Request, deckID string) {
+	cardReq := DeckCardRequest{Quantity: 1, Section: models.SectionMain}
+	if !decodeJSON(w, r, &cardReq) || !validateModel(w, &cardReq, "request") {
+		return
+	}
+
+	h.modifyDeck(w, r, deckID, "remove_deck_card", func(deck *models.Deck) error {
+		return deck.RemoveCard(cardReq.CardID, cardReq.Section, cardReq.Quantity)
+	})
+}
+
+// moveDeckCard handles POST /decks/{id}/cards/move
+func (h *DecksHandler) moveDeckCard(w http.ResponseWriter, r *http.Request, deckID string) {
+	moveReq := MoveDeckCardRequest{Quantity: 1}
+	if !decodeJSON(w, r, &moveReq) || !validateModel(w, &moveReq, "request") {
+		return
+	}
+
+	h.modifyDeck(w, r, deckID, "move_deck_card", func(deck *models.Deck) error {
+		return deck.MoveCard(moveReq.CardID, moveReq.From, moveReq.To, moveReq.Quantity)
+	})
+}
+
+// modifyDeck applies fn to the stored deck named in the URL and saves it,
+// honouring If-Match. The save fails with a conflict if the deck changed in
+// between.
+func (h *DecksHandler) modifyDeck(w http.ResponseWriter, r *http.Request, deckID, operation string, fn func(deck *models.Deck) error) {
+	// Validate UUID format
+	id, err := uuid.Parse(deckID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid deck ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	ctx := r.Context()
+	deck, err := h.storage.GetDeck(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to get deck",
+			slog.String("operation", operation),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to update deck")
+		return
+	}
+	if !ifMatches(r, deck.Version) {
+		writeWriteError(w, r, errPreconditionFailed, "Deck not found", "Failed to update deck")
+		return
+	}
+
+	if err := fn(deck); err != nil {
+		if errors.Is(err, models.ErrNotEnoughCopies) {
+			response := ErrorResponse{
+				Error:   "not_enough_copies",
+				Message: err.Error(),
+			}
+			writeJSONResponse(w, http.StatusConflict, response)
+			return
+		}
+		h.logger.Error("Failed to update deck",
+			slog.String("operation", operation),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to update deck")
+		return
+	}
+	h.saveDeck(w, r, *deck)
+}
+
+// saveDeck validates a full replacement for a stored deck and saves it
+func (h *DecksHandler) saveDeck(w http.ResponseWriter, r *http.Request, deck models.Deck) {
+	ctx := r.Context()
+	if !h.validateCards(ctx, w, &deck) {
+		return
+	}
+
+	updatedDeck, err := h.storage.UpdateDeck(ctx, deck)
+	if err != nil {
+		h.logger.Error("Failed to update deck",
+			slog.String("operation", "update_deck"),
+			slog.String("deck_id", deck.ID.String()),
+			slog.String("deck_name", deck.Name),
+			slog.Any("error", err))
+		writeWriteError(w, r, err, "Deck not found", "Failed to update deck")
+		return
+	}
+
+	setETag(w, updatedDeck.Version)
+	writeJSONResponse(w, http.StatusOK, updatedDeck)
+}
+
+// deckVersion returns the version of a stored deck, for ifMatchVersion
+func (h *DecksHandler) deckVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	deck, err := h.storage.GetDeck(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return deck.Version, nil
+}
+
+// deleteDeck handles DELETE /decks/{id}
+func (h *DecksHandler) deleteDeck(w http.ResponseWriter, r *http.Request, deckID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(deckID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid deck ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	version, err := ifMatchVersion(r, id, h.deckVersion)
+	if err == nil {
+		err = h.storage.DeleteDeck(r.Context(), id, version)
+	}
+	if err != nil {
+		h.logger.Error("Failed to delete deck",
+			slog.String("operation", "delete_deck"),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeWriteError(w, r, err, "Deck not found", "Failed to delete deck")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// createDeckState handles POST /decks/{id}/states
+func (h *DecksHandler) createDeckState(w http.ResponseWriter, r *http.Request, deckID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(deckID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid deck ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	ctx := r.Context()
+	deck, err := h.storage.GetDeck(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to get deck",
+			slog.String("operation", "create_deck_state"),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to retrieve deck")
+		return
+	}
+
+	state := models.NewDeckState(*deck)
+	state.CreatedAt = time.Now().UTC()
+	state.UpdatedAt = state.CreatedAt
+
+	createdState, err := h.storage.CreateDeckState(ctx, *state)
+	if err != nil {
+		h.logger.Error("Failed to create deck state",
+			slog.String("operation", "create_deck_state"),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to create deck state")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, createdState.Redacted())
+}
+
+// validateCards normalizes the entries of the deck, building them from
+// Cards if the client sent the flat format, and checks that every card they
+// reference exists in storage, setting the card type of each entry. It
+// writes a 422 response listing the unknown cards, at their place in the
+// request, and the merged entries holding more than MaxQuantity copies, and
+// returns false if there are any.
+func (h *DecksHandler) validateCards(ctx context.Context, w http.ResponseWriter, deck *models.Deck) bool {
+	type reference struct {
+		field  string
+		cardID uuid.UUID
+	}
+	var references []reference
+	if deck.Entries == nil {
+		for i, cardID := range deck.Cards {
+			references = append(references, reference{fmt.Sprintf("cards[%d]", i), cardID})
+		}
+		deck.UseCards()
+	} else {
+		for i, entry := range deck.Entries {
+			references = append(references, reference{fmt.Sprintf("entries[%d].card_id", i), entry.CardID})
+		}
+	}
+	deck.Normalize()
+
+	// Merging entries for the same card and section adds up their copies
+	var fields []models.FieldError
+	for i, entry := range deck.Entries {
+		if entry.Quantity > models.MaxQuantity {
+			fields = append(fields, models.FieldError{
+				Field:   fmt.Sprintf("entries[%d].quantity", i),
+				Message: fmt.Sprintf("is %d once merged; at most %d copies of a card may be in a section", entry.Quantity, models.MaxQuantity),
+			})
+		}
+	}
+
+	cardTypes := make(map[uuid.UUID]string, len(references))
+	for _, ref := range references {
+		cardType, checked := cardTypes[ref.cardID]
+		if !checked {
+			var err error
+			cardType, err = h.cardType(ctx, ref.cardID)
+			if err != nil {
+				h.logger.Error("Failed to look up deck card",
+					slog.String("operation", "validate_deck_cards"),
+					slog.String("card_id", ref.cardID.String()),
+					slog.Any("error", err))
+				writeStorageError(w, err, "Failed to validate deck cards: card not found", "Failed to validate deck cards")
+				return false
+			}
+			cardTypes[ref.cardID] = cardType
+		}
+		if cardType == "" {
+			fields = append(fields, models.FieldError{Field: ref.field, Message: "no card has ID " + ref.cardID.String()})
+		}
+	}
+
+	if len(fields) > 0 {
+		writeValidationError(w, fields, "deck")
+		return false
+	}
+
+	for i, entry := range deck.Entries {
+		deck.Entries[i].CardType = cardTypes[entry.CardID]
+	}
+	return true
+}
+
+// cardType returns the type of the card with the given ID, of any registered
+// type, or "" if there is none
+func (h *DecksHandler) cardType(ctx context.Context, id uuid.UUID) (string, error) {
+	card, err := storage.GetCard(ctx, h.storage, id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return card.GetCardType(), nil
+}
+
+// gameCards returns the game cards of a deck by ID. Cards of other types,
+// and cards deleted since the deck was saved, are left out.
+func gameCards(ctx context.Context, s storage.Storage, deck *models.Deck) (map[uuid.UUID]*models.GameCard, error) {
+	cards := make(map[uuid.UUID]*models.GameCard, len(deck.Entries))
+	for _, entry := range deck.Entries {
+		if _, checked := cards[entry.CardID]; checked || entry.CardType != models.CardTypeGameCard {
+			continue
+		}
+		card, err := s.GetGameCard(ctx, entry.CardID)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		cards[entry.CardID] = card
+	}
+	return cards, nil
+}
@@ -0,0 +1,615 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/patch"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+func TestDecksHandler_ListDecks_FilterByOwner(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	logger := testLogger()
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	otherOwnerID := uuid.New()
+	if _, err := mockStorage.CreateDeck(ctx, models.Deck{Name: "Mine", OwnerID: &ownerID}); err != nil {
+		t.Fatalf("Failed to create test deck: %v", err)
+	}
+	if _, err := mockStorage.CreateDeck(ctx, models.Deck{Name: "Theirs", OwnerID: &otherOwnerID}); err != nil {
+		t.Fatalf("Failed to create test deck: %v", err)
+	}
+
+	handler := NewDecksHandler(mockStorage, nil, logger)
+
+	req, err := http.NewRequest("GET", "/decks?owner_id="+ownerID.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	var decks []models.Deck
+	if err := json.Unmarshal(rr.Body.Bytes(), &decks); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if len(decks) != 1 || decks[0].Name != "Mine" {
+		t.Errorf("Expected only the owner's deck, got %+v", decks)
+	}
+
+	// Invalid owner ID
+	req, err = http.NewRequest("GET", "/decks?owner_id=not-a-uuid", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusBadRequest)
+	}
+}
+
+func TestDecksHandler_CreateDeck(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	logger := testLogger()
+
+	ctx := context.Background()
+	gameCard, err := mockStorage.CreateGameCard(ctx, models.GameCard{Name: "Fire Bolt"})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+	imageCard, err := mockStorage.CreateImageCard(ctx, models.ImageCard{Name: "Sunset"})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+
+	deckReq := models.Deck{
+		Name:  "Test Deck",
+		Cards: []uuid.UUID{gameCard.ID, gameCard.ID, imageCard.ID},
+	}
+	jsonBody, _ := json.Marshal(deckReq)
+	req, err := http.NewRequest("POST", "/decks", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	handler := NewDecksHandler(mockStorage, nil, logger)
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v",
+			status, http.StatusCreated)
+	}
+
+	var createdDeck models.Deck
+	if err := json.Unmarshal(rr.Body.Bytes(), &createdDeck); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if createdDeck.ID == uuid.Nil {
+		t.Error("Expected deck to have a generated ID")
+	}
+	if len(createdDeck.Cards) != 3 {
+		t.Errorf("Expected 3 cards in deck, got %d", len(createdDeck.Cards))
+	}
+}
+
+func TestDecksHandler_CreateDeck_UnknownCard(t *testing.T) {
+	deckReq := models.Deck{
+		Name:  "Test Deck",
+		Cards: []uuid.UUID{uuid.New()},
+	}
+	jsonBody, _ := json.Marshal(deckReq)
+	req, err := http.NewRequest("POST", "/decks", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	handler := NewDecksHandler(storage.NewMockStorage(), nil, testLogger())
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusUnprocessableEntity {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusUnprocessableEntity)
+	}
+	if !hasFieldError(t, rr, "cards[0]", "no card has ID") {
+		t.Errorf("Expected cards[0] to be an unknown card, got %s", rr.Body.String())
+	}
+}
+
+func TestDecksHandler_CreateDeck_TooManyCopies(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	card, err := mockStorage.CreateGameCard(context.Background(), models.GameCard{Name: "Fire Bolt"})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+	handler := NewDecksHandler(mockStorage, nil, testLogger())
+
+	// The flat format lists each copy, so the merged entry is checked
+	cards := slices.Repeat([]uuid.UUID{card.ID}, models.MaxQuantity+1)
+	jsonBody, _ := json.Marshal(models.Deck{Name: "Bolts", Cards: cards})
+	rr := serve(t, handler, "POST", "/decks", string(jsonBody), "Content-Type", "application/json")
+	if rr.Code != http.StatusUnprocessableEntity || !hasFieldError(t, rr, "entries[0].quantity", "at most 999") {
+		t.Errorf("Expected 422 for 1000 copies, got %v %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestDecksHandler_InvalidDeckFields(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	card, err := mockStorage.CreateGameCard(context.Background(), models.GameCard{Name: "Fire Bolt"})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+	handler := NewDecksHandler(mockStorage, nil, testLogger())
+
+	longName := strings.Repeat("D", models.MaxNameLength+1)
+	tests := []struct {
+		name, method, path, contentType, body string
+		wantField                             string
+	}{
+		{"long name", "POST", "/decks", "application/json", `{"name": "` + longName + `"}`, "name"},
+		{"sleeve URL", "POST", "/decks", "application/json", `{"name": "Burn", "sleeve_image_url": "sleeve.png"}`, "sleeve_image_url"},
+		{"standard deck name", "POST", "/decks/standard", "application/json", `{"name": "` + longName + `"}`, "name"},
+		{"imported name", "POST", "/decks/import?name=" + longName, "text/plain", "1 " + card.ID.String(), "name"},
+	}
+	for _, tt := range tests {
+		rr := serve(t, handler, tt.method, tt.path, tt.body, "Content-Type", tt.contentType)
+		if rr.Code != http.StatusUnprocessableEntity || !hasFieldError(t, rr, tt.wantField, "") {
+			t.Errorf("%s: expected 422 on %s, got %v %s", tt.name, tt.wantField, rr.Code, rr.Body.String())
+		}
+	}
+	if decks, _ := mockStorage.ListDecks(context.Background(), nil); len(decks) != 0 {
+		t.Errorf("Expected no decks to be created, got %d", len(decks))
+	}
+}
+
+func TestDecksHandler_GetDeck(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewDecksHandler(mockStorage, nil, testLogger())
+
+	deck, err := mockStorage.CreateDeck(context.Background(), models.Deck{Name: "Stored"})
+	if err != nil {
+		t.Fatalf("Failed to create test deck: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{"existing deck", "/decks/" + deck.ID.String(), http.StatusOK},
+		{"missing deck", "/decks/" + uuid.New().String(), http.StatusNotFound},
+		{"invalid id", "/decks/invalid-id", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					status, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestDecksHandler_UpdateDeck(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewDecksHandler(mockStorage, nil, testLogger())
+
+	deck, err := mockStorage.CreateDeck(context.Background(), models.Deck{Name: "Original"})
+	if err != nil {
+		t.Fatalf("Failed to create test deck: %v", err)
+	}
+
+	jsonBody, _ := json.Marshal(models.Deck{Name: "Renamed"})
+	req, err := http.NewRequest("PUT", "/decks/"+deck.ID.String(), bytes.NewBuffer(jsonBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	var updatedDeck models.Deck
+	if err := json.Unmarshal(rr.Body.Bytes(), &updatedDeck); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if updatedDeck.Name != "Renamed" {
+		t.Errorf("Expected updated deck name 'Renamed', got '%s'", updatedDeck.Name)
+	}
+
+	// Updating a missing deck
+	req, err = http.NewRequest("PUT", "/decks/"+uuid.New().String(), bytes.NewBuffer(jsonBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusNotFound)
+	}
+}
+
+func TestDecksHandler_PatchDeck(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewDecksHandler(mockStorage, nil, testLogger())
+	ctx := context.Background()
+
+	var cardIDs []uuid.UUID
+	for _, value := range []int{1, 2, 3} {
+		card, err := mockStorage.CreatePlayingCard(ctx, models.PlayingCard{Suite: models.SuiteHearts, Value: value})
+		if err != nil {
+			t.Fatalf("Failed to create test card: %v", err)
+		}
+		cardIDs = append(cardIDs, card.ID)
+	}
+	deck, err := mockStorage.CreateDeck(ctx, models.Deck{Name: "Hearts", Cards: cardIDs[:2]})
+	if err != nil {
+		t.Fatalf("Failed to create test deck: %v", err)
+	}
+	path := "/decks/" + deck.ID.String()
+
+	rr := serve(t, handler, "PATCH", path,
+		`[{"op": "remove", "path": "/cards/0"}, {"op": "add", "path": "/cards/-", "value": "`+cardIDs[2].String()+`"},
+		  {"op": "replace", "path": "/name", "value": "Low Hearts"}]`, "Content-Type", patch.MediaTypeJSONPatch)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var patched models.Deck
+	if err := json.Unmarshal(rr.Body.Bytes(), &patched); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	want := []uuid.UUID{cardIDs[1], cardIDs[2]}
+	if patched.Name != "Low Hearts" || len(patched.Cards) != 2 || patched.Cards[0] != want[0] || patched.Cards[1] != want[1] {
+		t.Errorf("Expected Low Hearts with cards %v, got %+v", want, patched)
+	}
+
+	// Added cards must exist
+	rr = serve(t, handler, "PATCH", path,
+		`[{"op": "add", "path": "/cards/-", "value": "`+uuid.New().String()+`"}]`, "Content-Type", patch.MediaTypeJSONPatch)
+	if rr.Code != http.StatusUnprocessableEntity || !hasFieldError(t, rr, "entries[2].card_id", "no card has ID") {
+		t.Errorf("unknown card: expected 422 for entries[2].card_id, got %v %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestDecksHandler_DeleteDeck(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewDecksHandler(mockStorage, nil, testLogger())
+
+	deck, err := mockStorage.CreateDeck(context.Background(), models.Deck{Name: "Doomed"})
+	if err != nil {
+		t.Fatalf("Failed to create test deck: %v", err)
+	}
+
+	req, err := http.NewRequest("DELETE", "/decks/"+deck.ID.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNoContent {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusNoContent)
+	}
+
+	// Deleting again should report not found
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusNotFound)
+	}
+}
+
+func TestDecksHandler_CreateStandardDeck(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewDecksHandler(mockStorage, nil, testLogger())
+
+	tests := []struct {
+		name           string
+		request        StandardDeckRequest
+		expectedStatus int
+		expectedCards  int
+		expectedStored int
+	}{
+		{"single deck", StandardDeckRequest{}, http.StatusCreated, 52, 52},
+		{"jokers", StandardDeckRequest{Jokers: 2}, http.StatusCreated, 54, 54},
+		{"six deck shoe", StandardDeckRequest{Decks: 6, Jokers: 1}, http.StatusCreated, 318, 53},
+		{"too many jokers", StandardDeckRequest{Jokers: 3}, http.StatusBadRequest, 0, 0},
+		{"too many decks", StandardDeckRequest{Decks: 9}, http.StatusBadRequest, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, _ := mockStorage.ListPlayingCards(context.Background())
+
+			jsonBody, _ := json.Marshal(tt.request)
+			req, err := http.NewRequest("POST", "/decks/standard", bytes.NewBuffer(jsonBody))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v",
+					status, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+
+			var deck models.Deck
+			if err := json.Unmarshal(rr.Body.Bytes(), &deck); err != nil {
+				t.Fatalf("Could not parse response body: %v", err)
+			}
+			if len(deck.Cards) != tt.expectedCards {
+				t.Errorf("Expected %d cards in deck, got %d", tt.expectedCards, len(deck.Cards))
+			}
+			if len(deck.Entries) != tt.expectedStored {
+				t.Errorf("Expected %d deck entries, got %d", tt.expectedStored, len(deck.Entries))
+			}
+			for _, entry := range deck.Entries {
+				if entry.CardType != models.CardTypePlayingCard || entry.Section != models.SectionMain {
+					t.Errorf("Expected a main section playing card entry, got %+v", entry)
+				}
+			}
+
+			after, _ := mockStorage.ListPlayingCards(context.Background())
+			if created := len(after) - len(before); created != tt.expectedStored {
+				t.Errorf("Expected %d playing cards to be stored, got %d", tt.expectedStored, created)
+			}
+		})
+	}
+}
+
+// failingStandardDeckStorage fails CreatePlayingCard once cardsLeft cards
+// have been created, and fails CreateDeck if failDeck is set, with err or
+// else storage.ErrUnavailable
+type failingStandardDeckStorage struct {
+	storage.Storage
+	cardsLeft int
+	failDeck  bool
+	err       error
+}
+
+func (s *failingStandardDeckStorage) failure() error {
+	if s.err != nil {
+		return s.err
+	}
+	return storage.ErrUnavailable
+}
+
+func (s *failingStandardDeckStorage) CreatePlayingCard(ctx context.Context, card models.PlayingCard) (*models.PlayingCard, error) {
+	if s.cardsLeft == 0 {
+		return nil, s.failure()
+	}
+	s.cardsLeft--
+	return s.Storage.CreatePlayingCard(ctx, card)
+}
+
+func (s *failingStandardDeckStorage) CreateDeck(ctx context.Context, deck models.Deck) (*models.Deck, error) {
+	if s.failDeck {
+		return nil, s.failure()
+	}
+	return s.Storage.CreateDeck(ctx, deck)
+}
+
+func TestDecksHandler_CreateStandardDeck_Failures(t *testing.T) {
+	tests := []struct {
+		name       string
+		storage    *failingStandardDeckStorage
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{"card fails partway", &failingStandardDeckStorage{cardsLeft: 20}, `{}`, http.StatusServiceUnavailable, "unavailable"},
+		{"deck fails", &failingStandardDeckStorage{cardsLeft: -1, failDeck: true}, `{}`, http.StatusServiceUnavailable, "unavailable"},
+		{"card not found", &failingStandardDeckStorage{cardsLeft: 3, err: storage.ErrNotFound}, `{}`, http.StatusInternalServerError, "internal_error"},
+		{"deck not found", &failingStandardDeckStorage{cardsLeft: -1, failDeck: true, err: storage.ErrNotFound}, `{}`, http.StatusInternalServerError, "internal_error"},
+		{"deck conflict", &failingStandardDeckStorage{cardsLeft: -1, failDeck: true, err: storage.ErrVersionMismatch}, `{}`, http.StatusInternalServerError, "internal_error"},
+		{"invalid image URL", &failingStandardDeckStorage{cardsLeft: -1},
+			`{"front_image_url": "ftp://example.com/front.png", "back_image_url": "back.png"}`, http.StatusUnprocessableEntity, "validation_failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.storage.Storage = storage.NewMockStorage()
+			handler := NewDecksHandler(tt.storage, nil, testLogger())
+
+			rr := serve(t, handler, "POST", "/decks/standard", tt.body, "Content-Type", "application/json")
+			var response ValidationErrorResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Could not parse response body: %v", err)
+			}
+			if rr.Code != tt.wantStatus || response.Error != tt.wantError {
+				t.Errorf("Expected %v %s, got %v %s", tt.wantStatus, tt.wantError, rr.Code, rr.Body.String())
+			}
+			// No record was looked up, so no storage error is the client's fault
+			if tt.wantStatus == http.StatusInternalServerError && response.Message != "Failed to create standard deck" {
+				t.Errorf("Expected the message to describe the failed creation, got %q", response.Message)
+			}
+			if tt.wantStatus == http.StatusUnprocessableEntity && len(response.Fields) != 2 {
+				t.Errorf("Expected both image URLs to be rejected, got %+v", response.Fields)
+			}
+
+			// Nothing is left behind for a retry to duplicate
+			if cards, _ := tt.storage.ListPlayingCards(context.Background()); len(cards) != 0 {
+				t.Errorf("Expected no playing cards to remain, got %d", len(cards))
+			}
+		})
+	}
+}
+
+func TestDecksHandler_CreateDeck_Entries(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewDecksHandler(mockStorage, nil, testLogger())
+
+	ctx := context.Background()
+	gameCard, err := mockStorage.CreateGameCard(ctx, models.GameCard{Name: "Fire Bolt"})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+	imageCard, err := mockStorage.CreateImageCard(ctx, models.ImageCard{Name: "Sunset"})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+
+	// Entries for the same card and section are merged and the section
+	// defaults to main
+	body := `{"name": "Burn", "entries": [
+		{"card_id": "` + gameCard.ID.String() + `", "quantity": 2},
+		{"card_id": "` + imageCard.ID.String() + `", "quantity": 1, "section": "sideboard"},
+		{"card_id": "` + gameCard.ID.String() + `", "quantity": 1, "section": "main"}]}`
+	rr := serve(t, handler, "POST", "/decks", body, "Content-Type", "application/json")
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+	var deck models.Deck
+	if err := json.Unmarshal(rr.Body.Bytes(), &deck); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	want := []models.DeckEntry{
+		{CardID: gameCard.ID, CardType: models.CardTypeGameCard, Quantity: 3, Section: models.SectionMain},
+		{CardID: imageCard.ID, CardType: models.CardTypeImageCard, Quantity: 1, Section: models.SectionSideboard},
+	}
+	if !slices.Equal(deck.Entries, want) {
+		t.Errorf("Expected entries %+v, got %+v", want, deck.Entries)
+	}
+	if len(deck.Cards) != 3 {
+		t.Errorf("Expected the 3 main cards in cards, got %v", deck.Cards)
+	}
+
+	// A patch of the flat list replaces the main section only
+	rr = serve(t, handler, "PATCH", "/decks/"+deck.ID.String(),
+		`{"cards": ["`+imageCard.ID.String()+`"]}`, "Content-Type", patch.MediaTypeMergePatch)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("patch: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &deck); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	want = []models.DeckEntry{
+		{CardID: imageCard.ID, CardType: models.CardTypeImageCard, Quantity: 1, Section: models.SectionMain},
+		{CardID: imageCard.ID, CardType: models.CardTypeImageCard, Quantity: 1, Section: models.SectionSideboard},
+	}
+	if !slices.Equal(deck.Entries, want) {
+		t.Errorf("Expected entries %+v after patching cards, got %+v", want, deck.Entries)
+	}
+
+	// Invalid entries are reported by field
+	body = `{"name": "Burn", "entries": [
+		{"card_id": "` + gameCard.ID.String() + `", "quantity": 0},
+		{"card_id": "` + gameCard.ID.String() + `", "quantity": 1, "section": "graveyard"}]}`
+	rr = serve(t, handler, "POST", "/decks", body, "Content-Type", "application/json")
+	var response ValidationErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if rr.Code != http.StatusUnprocessableEntity || len(response.Fields) != 2 ||
+		response.Fields[0].Field != "entries[0].quantity" || response.Fields[1].Field != "entries[1].section" {
+		t.Errorf("Expected 422 for the quantity and section, got %v %+v", rr.Code, response)
+	}
+}
+
+func TestDecksHandler_DeckCards(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewDecksHandler(mockStorage, nil, testLogger())
+
+	ctx := context.Background()
+	card, err := mockStorage.CreateGameCard(ctx, models.GameCard{Name: "Fire Bolt"})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+	deck, err := mockStorage.CreateDeck(ctx, models.Deck{Name: "Burn", Cards: []uuid.UUID{}})
+	if err != nil {
+		t.Fatalf("Failed to create test deck: %v", err)
+	}
+	path := "/decks/" + deck.ID.String() + "/cards"
+	cardID := card.ID.String()
+
+	tests := []struct {
+		name       string
+		path       string
+		ifMatch    string
+		body       string
+		wantStatus int
+		wantError  string
+		wantMain   int
+		wantSide   int
+	}{
+		{"add defaults", path, "", `{"card_id": "` + cardID + `"}`, http.StatusOK, "", 1, 0},
+		{"add to sideboard", path, `"2"`, `{"card_id": "` + cardID + `", "quantity": 3, "section": "sideboard"}`, http.StatusOK, "", 1, 3},
+		{"stale version", path, `"2"`, `{"card_id": "` + cardID + `"}`, http.StatusPreconditionFailed, "precondition_failed", 1, 3},
+		{"move", path + "/move", "", `{"card_id": "` + cardID + `", "quantity": 2, "from": "sideboard", "to": "main"}`, http.StatusOK, "", 3, 1},
+		{"move too many", path + "/move", "", `{"card_id": "` + cardID + `", "quantity": 2, "from": "sideboard", "to": "main"}`, http.StatusConflict, "not_enough_copies", 3, 1},
+		{"remove", path + "/remove", "", `{"card_id": "` + cardID + `", "section": "sideboard"}`, http.StatusOK, "", 3, 0},
+		{"remove missing", path + "/remove", "", `{"card_id": "` + cardID + `", "section": "sideboard"}`, http.StatusConflict, "not_enough_copies", 3, 0},
+		{"unknown card", path, "", `{"card_id": "` + uuid.New().String() + `"}`, http.StatusUnprocessableEntity, "validation_failed", 3, 0},
+		{"invalid request", path, "", `{"card_id": "` + cardID + `", "quantity": 0, "section": "graveyard"}`, http.StatusUnprocessableEntity, "validation_failed", 3, 0},
+		{"add the most copies", path, "", `{"card_id": "` + cardID + `", "quantity": 999, "section": "maybeboard"}`, http.StatusOK, "", 3, 0},
+		{"add them twice", path, "", `{"card_id": "` + cardID + `", "quantity": 999, "section": "maybeboard"}`, http.StatusUnprocessableEntity, "validation_failed", 3, 0},
+		{"missing deck", "/decks/" + uuid.New().String() + "/cards", "", `{"card_id": "` + cardID + `"}`, http.StatusNotFound, "not_found", 3, 0},
+	}
+	for _, tt := range tests {
+		rr := serve(t, handler, "POST", tt.path, tt.body, "If-Match", tt.ifMatch, "Content-Type", "application/json")
+		if rr.Code != tt.wantStatus {
+			t.Errorf("%s: got %v want %v: %s", tt.name, rr.Code, tt.wantStatus, rr.Body.String())
+		}
+		if tt.wantError != "" {
+			var response ErrorResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("%s: could not parse response body: %v", tt.name, err)
+			}
+			if response.Error != tt.wantError {
+				t.Errorf("%s: expected error %q, got %+v", tt.name, tt.wantError, response)
+			}
+		} else if rr.Header().Get("ETag") == "" {
+			t.Errorf("%s: expected an ETag", tt.name)
+		}
+
+		stored, err := mockStorage.GetDeck(ctx, deck.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if main, side := stored.Copies(card.ID, models.SectionMain), stored.Copies(card.ID, models.SectionSideboard); main != tt.wantMain || side != tt.wantSide {
+			t.Errorf("%s: expected %d main and %d sideboard copies, got %d and %d", tt.name, tt.wantMain, tt.wantSide, main, side)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/jwebster45206/tcg-api/internal/models"
+)
+
+// maxBodyBytes is the largest request body accepted; larger bodies are
+// rejected with 413
+const maxBodyBytes = 1 << 20
+
+// errTrailingData reports a body with more than one JSON value
+var errTrailingData = errors.New("unexpected data after the JSON value")
+
+// ValidationErrorResponse is the 422 response for a body whose fields break
+// the model's rules
+type ValidationErrorResponse struct {
+	ErrorResponse
+	Fields []models.FieldError `json:"fields"`
+}
+
+// decodeJSON decodes the request body into v. Unknown fields, trailing data
+// and bodies over maxBodyBytes are rejected. It writes an error response and
+// returns false if the body cannot be decoded.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	return decodeBody(w, r, v, false)
+}
+
+// decodeOptionalJSON is decodeJSON for endpoints whose body may be empty,
+// which leaves v unchanged
+func decodeOptionalJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	return decodeBody(w, r, v, true)
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v any, optional bool) bool {
+	if r.Body == nil {
+		if optional {
+			return true
+		}
+		writeDecodeError(w, io.EOF)
+		return false
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	if optional && errors.Is(err, io.EOF) {
+		return true
+	}
+	if err == nil {
+		// Only whitespace may follow the value
+		if _, tailErr := decoder.Token(); !errors.Is(tailErr, io.EOF) {
+			err = errTrailingData
+			if tailErr != nil {
+				err = tailErr
+			}
+		}
+	}
+	if err != nil {
+		writeDecodeError(w, err)
+		return false
+	}
+	return true
+}
+
+// writeDecodeError writes the response for a body that could not be decoded
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		response := ErrorResponse{
+			Error:   "body_too_large",
+			Message: fmt.Sprintf("Request body must be at most %d bytes", maxBytesErr.Limit),
+		}
+		writeJSONResponse(w, http.StatusRequestEntityTooLarge, response)
+		return
+	}
+
+	message := "Invalid JSON in request body"
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF):
+		message = "Request body is required"
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		message = fmt.Sprintf("Invalid JSON in request body: %s must be a JSON %s", typeErr.Field, jsonTypeName(typeErr))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json reports unknown fields with an unexported error type
+		message = "Invalid JSON in request body: unknown field " + strings.TrimPrefix(err.Error(), "json: unknown field ")
+	case errors.Is(err, errTrailingData):
+		message = "Invalid JSON in request body: " + errTrailingData.Error()
+	}
+	response := ErrorResponse{
+		Error:   "invalid_json",
+		Message: message,
+	}
+	writeJSONResponse(w, http.StatusBadRequest, response)
+}
+
+// jsonTypeName describes the JSON type a Go type is decoded from
+func jsonTypeName(typeErr *json.UnmarshalTypeError) string {
+	switch typeErr.Type.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	default:
+		return "value"
+	}
+}
+
+// validateModel checks the fields of v. It writes a 422 response listing
+// every invalid field and returns false if any are invalid.
+func validateModel(w http.ResponseWriter, v models.Validator, what string) bool {
+	fields := v.Validate()
+	if len(fields) == 0 {
+		return true
+	}
+	writeValidationError(w, fields, what)
+	return false
+}
+
+// writeValidationError writes the 422 response listing the invalid fields of
+// a request body
+func writeValidationError(w http.ResponseWriter, fields []models.FieldError, what string) {
+	response := ValidationErrorResponse{
+		ErrorResponse: ErrorResponse{
+			Error:   "validation_failed",
+			Message: fmt.Sprintf("Invalid %s: %d field(s) failed validation", what, len(fields)),
+		},
+		Fields: fields,
+	}
+	writeJSONResponse(w, http.StatusUnprocessableEntity, response)
+}
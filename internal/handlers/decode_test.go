@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// hasFieldError reports whether rr is a validation_failed response listing
+// field with a message containing msg
+func hasFieldError(t *testing.T, rr *httptest.ResponseRecorder, field, msg string) bool {
+	t.Helper()
+	var response ValidationErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if response.Error != "validation_failed" {
+		return false
+	}
+	for _, f := range response.Fields {
+		if f.Field == field && strings.Contains(f.Message, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDecodeJSON(t *testing.T) {
+	type body struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		optional    bool
+		wantStatus  int
+		wantError   string
+		wantMessage string
+	}{
+		{"valid", `{"name": "a", "count": 1}`, false, http.StatusOK, "", ""},
+		{"trailing whitespace", "{\"name\": \"a\"}\n", false, http.StatusOK, "", ""},
+		{"empty", "", false, http.StatusBadRequest, "invalid_json", "Request body is required"},
+		{"empty optional", "", true, http.StatusOK, "", ""},
+		{"malformed", `{"name": `, false, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body"},
+		{"unknown field", `{"name": "a", "colour": "red"}`, false, http.StatusBadRequest, "invalid_json",
+			`Invalid JSON in request body: unknown field "colour"`},
+		{"wrong type", `{"count": "three"}`, false, http.StatusBadRequest, "invalid_json",
+			"Invalid JSON in request body: count must be a JSON number"},
+		{"trailing value", `{"name": "a"} {"name": "b"}`, false, http.StatusBadRequest, "invalid_json",
+			"Invalid JSON in request body: unexpected data after the JSON value"},
+		{"too large", `{"name": "` + strings.Repeat("a", maxBodyBytes) + `"}`, false, http.StatusRequestEntityTooLarge,
+			"body_too_large", "Request body must be at most 1048576 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			var v body
+			decode := decodeJSON
+			if tt.optional {
+				decode = decodeOptionalJSON
+			}
+			if !decode(rr, req, &v) {
+				if rr.Code != tt.wantStatus {
+					t.Fatalf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+				}
+				var response ErrorResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Could not parse response body: %v", err)
+				}
+				if response.Error != tt.wantError || response.Message != tt.wantMessage {
+					t.Errorf("Expected %q / %q, got %+v", tt.wantError, tt.wantMessage, response)
+				}
+				return
+			}
+			if tt.wantStatus != http.StatusOK {
+				t.Errorf("Expected status %d, but the body decoded", tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// writeStorageError writes the response for an error returned by storage.
+// Each kind of storage error has its own status code; notFoundMessage is
+// used for storage.ErrNotFound and failureMessage for errors of no known
+// kind, which are reported as internal errors.
+func writeStorageError(w http.ResponseWriter, err error, notFoundMessage, failureMessage string) {
+	status, response := storageErrorResponse(err, notFoundMessage, failureMessage)
+	writeJSONResponse(w, status, response)
+}
+
+// storageErrorResponse maps a storage error to a status code and body
+func storageErrorResponse(err error, notFoundMessage, failureMessage string) (int, ErrorResponse) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return http.StatusNotFound, ErrorResponse{
+			Error:   "not_found",
+			Message: notFoundMessage,
+		}
+	case errors.Is(err, storage.ErrConflict):
+		return http.StatusConflict, ErrorResponse{
+			Error:   "conflict",
+			Message: storageErrorMessage(err, "Request conflicts with existing data"),
+		}
+	case errors.Is(err, storage.ErrValidation):
+		return http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation_failed",
+			Message: storageErrorMessage(err, "Request failed validation"),
+		}
+	case errors.Is(err, storage.ErrUnavailable):
+		return http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "unavailable",
+			Message: "Storage is temporarily unavailable; try again later",
+		}
+	default:
+		return http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: failureMessage,
+		}
+	}
+}
+
+// storageErrorMessage returns the client-facing message of a *storage.Error
+// in err's chain, or fallback if there is none
+func storageErrorMessage(err error, fallback string) string {
+	var storageErr *storage.Error
+	if !errors.As(err, &storageErr) || storageErr.Message == "" {
+		return fallback
+	}
+	r, size := utf8.DecodeRuneInString(storageErr.Message)
+	return string(unicode.ToUpper(r)) + storageErr.Message[size:]
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+func TestStorageErrorResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantError   string
+		wantMessage string
+	}{
+		{"not found", fmt.Errorf("get widget: %w", storage.ErrNotFound), http.StatusNotFound, "not_found", "Widget not found"},
+		{"conflict", &storage.Error{Kind: storage.ErrConflict, Message: "widget already exists"}, http.StatusConflict, "conflict", "Widget already exists"},
+		{"bare conflict", storage.ErrConflict, http.StatusConflict, "conflict", "Request conflicts with existing data"},
+		{"validation", storage.ErrUnsupportedCardType, http.StatusUnprocessableEntity, "validation_failed", "Unsupported card type"},
+		{"unavailable", &storage.Error{Kind: storage.ErrUnavailable, Message: "storage unavailable", Err: errors.New("connection refused")},
+			http.StatusServiceUnavailable, "unavailable", "Storage is temporarily unavailable; try again later"},
+		{"other", errors.New("disk on fire"), http.StatusInternalServerError, "internal_error", "Failed to load widget"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := storageErrorResponse(tt.err, "Widget not found", "Failed to load widget")
+			if status != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, status)
+			}
+			if response.Error != tt.wantError || response.Message != tt.wantMessage {
+				t.Errorf("Expected %q / %q, got %+v", tt.wantError, tt.wantMessage, response)
+			}
+		})
+	}
+}
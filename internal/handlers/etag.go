@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// errPreconditionFailed reports a write whose If-Match header does not name
+// the current version of the record
+var errPreconditionFailed = errors.New("precondition failed")
+
+// etag returns the strong entity tag of a record version
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// setETag sets the ETag header of a response carrying a versioned record
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", etag(version))
+}
+
+// ifMatches reports whether the If-Match header of r accepts a record at the
+// given version: the header is absent, is "*" or lists the version's ETag.
+// Weak tags never match, since If-Match uses the strong comparison.
+func ifMatches(r *http.Request, version int) bool {
+	values := r.Header.Values("If-Match")
+	if len(values) == 0 {
+		return true
+	}
+	want := etag(version)
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag == "*" || tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ifMatchVersion returns the version a PUT or DELETE of the record with the
+// given ID must find in storage. Without an If-Match header any version will
+// do and it returns 0. Otherwise it reads the current version with current
+// and returns it if If-Match accepts it, and errPreconditionFailed if not.
+func ifMatchVersion(r *http.Request, id uuid.UUID, current func(ctx context.Context, id uuid.UUID) (int, error)) (int, error) {
+	if len(r.Header.Values("If-Match")) == 0 {
+		return 0, nil
+	}
+	version, err := current(r.Context(), id)
+	if err != nil {
+		return 0, err
+	}
+	if !ifMatches(r, version) {
+		return 0, errPreconditionFailed
+	}
+	return version, nil
+}
+
+// writeWriteError writes the response for an error from a write to a
+// versioned record. A version that no longer matches is 412 when the client
+// sent If-Match, and otherwise a conflict like any other.
+func writeWriteError(w http.ResponseWriter, r *http.Request, err error, notFoundMessage, failureMessage string) {
+	if errors.Is(err, errPreconditionFailed) ||
+		(errors.Is(err, storage.ErrVersionMismatch) && len(r.Header.Values("If-Match")) > 0) {
+		response := ErrorResponse{
+			Error:   "precondition_failed",
+			Message: "If-Match does not match the current version; fetch it again and retry",
+		}
+		writeJSONResponse(w, http.StatusPreconditionFailed, response)
+		return
+	}
+	writeStorageError(w, err, notFoundMessage, failureMessage)
+}
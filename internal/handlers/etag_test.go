@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/patch"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+func TestIfMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []string
+		want   bool
+	}{
+		{"absent", nil, true},
+		{"any", []string{"*"}, true},
+		{"current", []string{`"3"`}, true},
+		{"stale", []string{`"2"`}, false},
+		{"list", []string{`"1", "3"`}, true},
+		{"repeated header", []string{`"1"`, `"3"`}, true},
+		{"weak", []string{`W/"3"`}, false},
+		{"unquoted", []string{"3"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/decks/x", nil)
+			for _, value := range tt.header {
+				req.Header.Add("If-Match", value)
+			}
+			if got := ifMatches(req, 3); got != tt.want {
+				t.Errorf("ifMatches(%q, 3) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameCardsHandler_Preconditions(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewGameCardsHandler(mockStorage, testLogger())
+
+	// Create reports the first version
+	rr := serve(t, handler, "POST", "/game-cards", `{"name": "Goblin", "version": 9}`, "Content-Type", "application/json")
+	if rr.Code != http.StatusCreated || rr.Header().Get("ETag") != `"1"` {
+		t.Fatalf("create: got %v with ETag %q: %s", rr.Code, rr.Header().Get("ETag"), rr.Body.String())
+	}
+	var card models.GameCard
+	if err := json.Unmarshal(rr.Body.Bytes(), &card); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	path := "/game-cards/" + card.ID.String()
+
+	if rr := serve(t, handler, "GET", path, ""); rr.Header().Get("ETag") != `"1"` {
+		t.Errorf("get: expected ETag \"1\", got %q", rr.Header().Get("ETag"))
+	}
+
+	// Writes that name the current version succeed and move it on
+	rr = serve(t, handler, "PUT", path, `{"name": "Orc"}`, "If-Match", `"1"`, "Content-Type", "application/json")
+	if rr.Code != http.StatusOK || rr.Header().Get("ETag") != `"2"` {
+		t.Fatalf("put: got %v with ETag %q: %s", rr.Code, rr.Header().Get("ETag"), rr.Body.String())
+	}
+	rr = serve(t, handler, "PATCH", path, `{"cost": 3}`, "If-Match", `"2"`, "Content-Type", patch.MediaTypeMergePatch)
+	if rr.Code != http.StatusOK || rr.Header().Get("ETag") != `"3"` {
+		t.Fatalf("patch: got %v with ETag %q: %s", rr.Code, rr.Header().Get("ETag"), rr.Body.String())
+	}
+
+	// Writes that name an old version fail and change nothing
+	for _, tt := range []struct {
+		method, contentType, body string
+	}{
+		{"PUT", "application/json", `{"name": "Troll"}`},
+		{"PATCH", patch.MediaTypeMergePatch, `{"name": "Troll"}`},
+		{"DELETE", "", ""},
+	} {
+		rr := serve(t, handler, tt.method, path, tt.body, "If-Match", `"2"`, "Content-Type", tt.contentType)
+		var response ErrorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: could not parse response body: %v", tt.method, err)
+		}
+		if rr.Code != http.StatusPreconditionFailed || response.Error != "precondition_failed" {
+			t.Errorf("%s with a stale version: expected 412 precondition_failed, got %v %+v", tt.method, rr.Code, response)
+		}
+	}
+	stored, err := mockStorage.GetGameCard(context.Background(), card.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Name != "Orc" || stored.Cost != 3 || stored.Version != 3 {
+		t.Errorf("Expected the card to be unchanged by stale writes, got %+v", stored)
+	}
+
+	// A missing card is still not found
+	rr = serve(t, handler, "DELETE", "/game-cards/"+uuid.New().String(), "", "If-Match", `"1"`)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("delete missing: got %v want %v", rr.Code, http.StatusNotFound)
+	}
+
+	if rr := serve(t, handler, "DELETE", path, "", "If-Match", `"3"`); rr.Code != http.StatusNoContent {
+		t.Errorf("delete: got %v want %v: %s", rr.Code, http.StatusNoContent, rr.Body.String())
+	}
+}
+
+func TestDecksHandler_Preconditions(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewDecksHandler(mockStorage, nil, testLogger())
+	deck, err := mockStorage.CreateDeck(context.Background(), models.Deck{Name: "Burn", Cards: []uuid.UUID{}})
+	if err != nil {
+		t.Fatalf("Failed to create test deck: %v", err)
+	}
+	path := "/decks/" + deck.ID.String()
+
+	if rr := serve(t, handler, "GET", path, ""); rr.Header().Get("ETag") != `"1"` {
+		t.Errorf("get: expected ETag \"1\", got %q", rr.Header().Get("ETag"))
+	}
+
+	// Without If-Match writes replace whatever version is stored
+	rr := serve(t, handler, "PUT", path, `{"name": "Big Burn", "cards": []}`, "Content-Type", "application/json")
+	if rr.Code != http.StatusOK || rr.Header().Get("ETag") != `"2"` {
+		t.Fatalf("put: got %v with ETag %q: %s", rr.Code, rr.Header().Get("ETag"), rr.Body.String())
+	}
+
+	if rr := serve(t, handler, "DELETE", path, "", "If-Match", `"1"`); rr.Code != http.StatusPreconditionFailed {
+		t.Errorf("delete with a stale version: got %v want %v", rr.Code, http.StatusPreconditionFailed)
+	}
+	if rr := serve(t, handler, "DELETE", path, "", "If-Match", "*"); rr.Code != http.StatusNoContent {
+		t.Errorf("delete with If-Match *: got %v want %v: %s", rr.Code, http.StatusNoContent, rr.Body.String())
+	}
+}
+
+func TestWriteWriteError(t *testing.T) {
+	// A version conflict without If-Match came from a concurrent PATCH
+	req := httptest.NewRequest("PATCH", "/decks/x", nil)
+	rr := httptest.NewRecorder()
+	writeWriteError(rr, req, storage.ErrVersionMismatch, "Deck not found", "Failed to update deck")
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected %v without If-Match, got %v", http.StatusConflict, rr.Code)
+	}
+
+	req.Header.Set("If-Match", `"4"`)
+	rr = httptest.NewRecorder()
+	writeWriteError(rr, req, storage.ErrVersionMismatch, "Deck not found", "Failed to update deck")
+	if rr.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected %v with If-Match, got %v", http.StatusPreconditionFailed, rr.Code)
+	}
+}
@@ -1,13 +1,18 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/query"
 	"github.com/jwebster45206/tcg-api/internal/storage"
 )
 
@@ -33,6 +38,9 @@ func (h *GameCardsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		if path == "" || path == "/" {
 			// GET /game-cards - List all cards
 			h.listCards(w, r)
+		} else if strings.Trim(path, "/") == "search" {
+			// GET /game-cards/search?q= - Search cards
+			h.searchCards(w, r)
 		} else {
 			// GET /game-cards/{id} - Get specific card
 			cardID := strings.Trim(path, "/")
@@ -56,6 +64,15 @@ func (h *GameCardsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Card ID required for update", http.StatusBadRequest)
 		}
 
+	case http.MethodPatch:
+		if path != "" && path != "/" {
+			// PATCH /game-cards/{id} - Partially update card
+			cardID := strings.Trim(path, "/")
+			h.patchCard(w, r, cardID)
+		} else {
+			http.Error(w, "Method not allowed for this path", http.StatusMethodNotAllowed)
+		}
+
 	case http.MethodDelete:
 		if path != "" && path != "/" {
 			// DELETE /game-cards/{id} - Delete card
@@ -70,28 +87,97 @@ func (h *GameCardsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// listCards handles GET /game-cards
+// listCards handles GET /game-cards. See parsePageRequest and
+// parseGameCardFilter for the query parameters.
 func (h *GameCardsHandler) listCards(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	params := r.URL.Query()
+	pageReq, err := parsePageRequest(params)
+	if err != nil {
+		writeInvalidQuery(w, err)
+		return
+	}
+	filter, err := parseGameCardFilter(params)
+	if err != nil {
+		writeInvalidQuery(w, err)
+		return
+	}
 
-	cards, err := h.storage.ListGameCards(ctx, "gamecard")
+	ctx := r.Context()
+	page, err := h.storage.ListGameCardsPage(ctx, filter, pageReq)
 	if err != nil {
 		h.logger.Error("Failed to list cards",
 			slog.String("operation", "list_game_cards"),
 			slog.Any("error", err))
-		response := ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to retrieve cards",
+		writeStorageError(w, err, "Card not found", "Failed to retrieve cards")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, newListResponse(page))
+}
+
+// searchCards handles GET /game-cards/search?q=, taking the same paging
+// parameters as listCards. See package query for the search syntax.
+func (h *GameCardsHandler) searchCards(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	pageReq, err := parsePageRequest(params)
+	if err != nil {
+		writeInvalidQuery(w, err)
+		return
+	}
+	expr, err := query.Parse(params.Get("q"))
+	if err != nil {
+		var syntaxErr *query.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			writeInvalidQuery(w, err)
+			return
 		}
-		writeJSONResponse(w, http.StatusInternalServerError, response)
+		response := SearchErrorResponse{
+			ErrorResponse: ErrorResponse{
+				Error:   "invalid_search",
+				Message: syntaxErr.Error(),
+			},
+			Column: syntaxErr.Offset + 1,
+			Token:  syntaxErr.Token,
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	ctx := r.Context()
+	page, err := h.storage.SearchGameCards(ctx, expr, pageReq)
+	if err != nil {
+		h.logger.Error("Failed to search cards",
+			slog.String("operation", "search_game_cards"),
+			slog.String("query", params.Get("q")),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card not found", "Failed to search cards")
 		return
 	}
 
-	writeJSONResponse(w, http.StatusOK, cards)
+	writeJSONResponse(w, http.StatusOK, newListResponse(page))
 }
 
-// getCard handles GET /game-cards/{id}
+// ExpandedGameCard is a game card with the registry entries of its
+// keywords, returned by GET /game-cards/{id}?expand=keywords
+type ExpandedGameCard struct {
+	*models.GameCard
+	// KeywordDetails follows the order of Keywords. Keywords missing from the
+	// registry have only a name.
+	KeywordDetails []*models.RegistryEntry `json:"keyword_details"`
+}
+
+// getCard handles GET /game-cards/{id}. ?expand=keywords adds the reminder
+// text and icons of the card's keywords.
 func (h *GameCardsHandler) getCard(w http.ResponseWriter, r *http.Request, cardID string) {
+	expandKeywords := false
+	for _, field := range splitList(r.URL.Query().Get("expand")) {
+		if field != models.RegistryKeywords {
+			writeInvalidQuery(w, errors.New("expand must be keywords"))
+			return
+		}
+		expandKeywords = true
+	}
+
 	// Validate UUID format
 	id, err := uuid.Parse(cardID)
 	if err != nil {
@@ -110,45 +196,61 @@ func (h *GameCardsHandler) getCard(w http.ResponseWriter, r *http.Request, cardI
 			slog.String("operation", "get_game_card"),
 			slog.String("card_id", cardID),
 			slog.Any("error", err))
-		response := ErrorResponse{
-			Error:   "not_found",
-			Message: "Card not found",
-		}
-		writeJSONResponse(w, http.StatusNotFound, response)
+		writeStorageError(w, err, "Card not found", "Failed to retrieve card")
+		return
+	}
+	setETag(w, card.Version)
+	if !expandKeywords {
+		writeJSONResponse(w, http.StatusOK, card)
 		return
 	}
 
-	writeJSONResponse(w, http.StatusOK, card)
+	entries, err := h.storage.ListRegistryEntries(ctx, models.RegistryKeywords)
+	if err != nil {
+		h.logger.Error("Failed to list keywords",
+			slog.String("operation", "get_game_card"),
+			slog.String("card_id", cardID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Keyword not found", "Failed to retrieve card")
+		return
+	}
+	resolver := models.NewResolver(entries)
+	expanded := ExpandedGameCard{GameCard: card, KeywordDetails: make([]*models.RegistryEntry, 0, len(card.Keywords))}
+	for _, keyword := range card.Keywords {
+		entry, ok := resolver.Resolve(keyword)
+		if !ok {
+			entry = &models.RegistryEntry{Name: keyword, Aliases: []string{}}
+		}
+		expanded.KeywordDetails = append(expanded.KeywordDetails, entry)
+	}
+
+	writeJSONResponse(w, http.StatusOK, expanded)
 }
 
 // createCard handles POST /game-cards
 func (h *GameCardsHandler) createCard(w http.ResponseWriter, r *http.Request) {
 	var card models.GameCard
 
-	if err := json.NewDecoder(r.Body).Decode(&card); err != nil {
-		response := ErrorResponse{
-			Error:   "invalid_json",
-			Message: "Invalid JSON in request body",
-		}
-		writeJSONResponse(w, http.StatusBadRequest, response)
+	if !decodeJSON(w, r, &card) || !validateModel(w, &card, "game card") {
 		return
 	}
 
 	ctx := r.Context()
+	if !h.validatePrintings(ctx, w, &card) || !h.normalizeRegistryValues(ctx, w, &card) ||
+		!h.validateGame(ctx, w, &card) {
+		return
+	}
 	createdCard, err := h.storage.CreateGameCard(ctx, card)
 	if err != nil {
 		h.logger.Error("Failed to create game card",
 			slog.String("operation", "create_game_card"),
 			slog.String("card_name", card.Name),
 			slog.Any("error", err))
-		response := ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to create card",
-		}
-		writeJSONResponse(w, http.StatusInternalServerError, response)
+		writeStorageError(w, err, "Card not found", "Failed to create card")
 		return
 	}
 
+	setETag(w, createdCard.Version)
 	writeJSONResponse(w, http.StatusCreated, createdCard)
 }
 
@@ -166,36 +268,101 @@ func (h *GameCardsHandler) updateCard(w http.ResponseWriter, r *http.Request, ca
 	}
 
 	var card models.GameCard
-	if err := json.NewDecoder(r.Body).Decode(&card); err != nil {
+	if !decodeJSON(w, r, &card) {
+		return
+	}
+
+	// Set the ID from the URL path. The version to replace comes from
+	// If-Match, not the body.
+	card.ID = id
+	card.Version, err = ifMatchVersion(r, id, h.cardVersion)
+	if err != nil {
+		h.logger.Error("Failed to update game card",
+			slog.String("operation", "update_game_card"),
+			slog.String("card_id", cardID),
+			slog.Any("error", err))
+		writeWriteError(w, r, err, "Card not found", "Failed to update card")
+		return
+	}
+	h.saveCard(w, r, card)
+}
+
+// patchCard handles PATCH /game-cards/{id}, applying a JSON merge patch or
+// JSON Patch to the stored card
+func (h *GameCardsHandler) patchCard(w http.ResponseWriter, r *http.Request, cardID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(cardID)
+	if err != nil {
 		response := ErrorResponse{
-			Error:   "invalid_json",
-			Message: "Invalid JSON in request body",
+			Error:   "invalid_id",
+			Message: "Invalid card ID format",
 		}
 		writeJSONResponse(w, http.StatusBadRequest, response)
 		return
 	}
 
 	ctx := r.Context()
-	// Set the ID from the URL path
+	current, err := h.storage.GetGameCard(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to get game card",
+			slog.String("operation", "patch_game_card"),
+			slog.String("card_id", cardID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card not found", "Failed to update card")
+		return
+	}
+	if !ifMatches(r, current.Version) {
+		writeWriteError(w, r, errPreconditionFailed, "Card not found", "Failed to update card")
+		return
+	}
+
+	var card models.GameCard
+	if !applyPatch(w, r, current, &card, "game card") {
+		return
+	}
+
+	// The ID cannot be patched, and the stored game card must still be the
+	// version the patch was applied to
 	card.ID = id
+	card.Version = current.Version
+	h.saveCard(w, r, card)
+}
+
+// saveCard validates a full replacement for a stored card and saves it
+func (h *GameCardsHandler) saveCard(w http.ResponseWriter, r *http.Request, card models.GameCard) {
+	if !validateModel(w, &card, "game card") {
+		return
+	}
+
+	ctx := r.Context()
+	if !h.validatePrintings(ctx, w, &card) || !h.normalizeRegistryValues(ctx, w, &card) ||
+		!h.validateGame(ctx, w, &card) {
+		return
+	}
 	updatedCard, err := h.storage.UpdateGameCard(ctx, card)
 	if err != nil {
 		h.logger.Error("Failed to update game card",
 			slog.String("operation", "update_game_card"),
-			slog.String("card_id", cardID),
+			slog.String("card_id", card.ID.String()),
 			slog.String("card_name", card.Name),
 			slog.Any("error", err))
-		response := ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to update card",
-		}
-		writeJSONResponse(w, http.StatusInternalServerError, response)
+		writeWriteError(w, r, err, "Card not found", "Failed to update card")
 		return
 	}
 
+	setETag(w, updatedCard.Version)
 	writeJSONResponse(w, http.StatusOK, updatedCard)
 }
 
+// cardVersion returns the version of a stored game card, for ifMatchVersion
+func (h *GameCardsHandler) cardVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	card, err := h.storage.GetGameCard(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return card.Version, nil
+}
+
 // deleteCard handles DELETE /game-cards/{id}
 func (h *GameCardsHandler) deleteCard(w http.ResponseWriter, r *http.Request, cardID string) {
 	// Validate UUID format
@@ -209,19 +376,182 @@ func (h *GameCardsHandler) deleteCard(w http.ResponseWriter, r *http.Request, ca
 		return
 	}
 
-	ctx := r.Context()
-	if err := h.storage.DeleteGameCard(ctx, id); err != nil {
+	version, err := ifMatchVersion(r, id, h.cardVersion)
+	if err == nil {
+		err = h.storage.DeleteGameCard(r.Context(), id, version)
+	}
+	if err != nil {
 		h.logger.Error("Failed to delete game card",
 			slog.String("operation", "delete_game_card"),
 			slog.String("card_id", cardID),
 			slog.Any("error", err))
-		response := ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to delete card",
-		}
-		writeJSONResponse(w, http.StatusInternalServerError, response)
+		writeWriteError(w, r, err, "Card not found", "Failed to delete card")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// validatePrintings checks that every printing of the card names an existing
+// set, a collector number and a known rarity, and that no printing is listed
+// twice. It writes a 422 response listing the invalid fields and returns false
+// if any are invalid.
+func (h *GameCardsHandler) validatePrintings(ctx context.Context, w http.ResponseWriter, card *models.GameCard) bool {
+	if card.Printings == nil {
+		card.Printings = []models.Printing{}
+	}
+
+	var fields []models.FieldError
+	knownSets := make(map[string]bool)
+	seen := make(map[models.Printing]int, len(card.Printings))
+	for i, printing := range card.Printings {
+		path := fmt.Sprintf("printings[%d]", i)
+		if printing.SetCode == "" {
+			fields = append(fields, models.FieldError{Field: path + ".set_code", Message: "is required"})
+		}
+		if printing.CollectorNumber == "" {
+			fields = append(fields, models.FieldError{Field: path + ".collector_number", Message: "is required"})
+		} else if utf8.RuneCountInString(printing.CollectorNumber) > models.MaxCollectorNumberLength {
+			fields = append(fields, models.FieldError{
+				Field:   path + ".collector_number",
+				Message: fmt.Sprintf("must be at most %d characters", models.MaxCollectorNumberLength),
+			})
+		}
+		if !models.IsRarity(printing.Rarity) {
+			fields = append(fields, models.FieldError{
+				Field:   path + ".rarity",
+				Message: "must be one of: " + strings.Join(models.Rarities, ", "),
+			})
+		}
+		if printing.SetCode == "" || printing.CollectorNumber == "" {
+			continue
+		}
+
+		key := models.Printing{SetCode: printing.SetCode, CollectorNumber: printing.CollectorNumber}
+		if first, ok := seen[key]; ok {
+			fields = append(fields, models.FieldError{
+				Field:   path,
+				Message: fmt.Sprintf("%s #%s is already listed as printings[%d]", printing.SetCode, printing.CollectorNumber, first),
+			})
+		} else {
+			seen[key] = i
+		}
+
+		known, checked := knownSets[printing.SetCode]
+		if !checked {
+			_, err := h.storage.GetCardSet(ctx, printing.SetCode)
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
+				h.logger.Error("Failed to look up card set",
+					slog.String("operation", "validate_printings"),
+					slog.String("set_code", printing.SetCode),
+					slog.Any("error", err))
+				writeStorageError(w, err, "Card set not found", "Failed to validate printings")
+				return false
+			}
+			known = err == nil
+			knownSets[printing.SetCode] = known
+		}
+		if !known {
+			fields = append(fields, models.FieldError{
+				Field:   path + ".set_code",
+				Message: fmt.Sprintf("no set has code %q", printing.SetCode),
+			})
+		}
+	}
+
+	if len(fields) > 0 {
+		writeValidationError(w, fields, "game card")
+		return false
+	}
+
+	return true
+}
+
+// normalizeRegistryValues replaces the card's keywords and colors with their
+// canonical names from the registries, dropping repeats. Values are only
+// checked against registries with entries, so an empty registry accepts
+// anything. It writes a 422 response listing the values that are not in
+// their registry and returns false if there are any.
+func (h *GameCardsHandler) normalizeRegistryValues(ctx context.Context, w http.ResponseWriter, card *models.GameCard) bool {
+	var fields []models.FieldError
+	for _, list := range []struct {
+		registry string
+		values   *[]string
+	}{
+		{models.RegistryKeywords, &card.Keywords},
+		{models.RegistryColors, &card.Colors},
+	} {
+		entries, err := h.storage.ListRegistryEntries(ctx, list.registry)
+		if err != nil {
+			h.logger.Error("Failed to list registry entries",
+				slog.String("operation", "normalize_registry_values"),
+				slog.String("registry", list.registry),
+				slog.Any("error", err))
+			writeStorageError(w, err, "Registry entry not found", "Failed to validate card")
+			return false
+		}
+		if len(entries) == 0 {
+			continue
+		}
+
+		resolver := models.NewResolver(entries)
+		normalized := make([]string, 0, len(*list.values))
+		for i, value := range *list.values {
+			entry, ok := resolver.Resolve(strings.TrimSpace(value))
+			if !ok {
+				fields = append(fields, models.FieldError{
+					Field:   fmt.Sprintf("%s[%d]", list.registry, i),
+					Message: fmt.Sprintf("unknown %s %q", strings.TrimSuffix(list.registry, "s"), value),
+				})
+				continue
+			}
+			if !slices.Contains(normalized, entry.Name) {
+				normalized = append(normalized, entry.Name)
+			}
+		}
+		*list.values = normalized
+	}
+
+	if len(fields) > 0 {
+		writeValidationError(w, fields, "game card")
+		return false
+	}
+	return true
+}
+
+// validateGame checks the card against the rules of its game. Cards without
+// a game follow no rules, but cannot have stats beyond the built-in ones. It
+// writes a 422 response listing the fields that break a rule and returns
+// false if there are any.
+func (h *GameCardsHandler) validateGame(ctx context.Context, w http.ResponseWriter, card *models.GameCard) bool {
+	if card.GameID == nil {
+		if len(card.Stats) == 0 {
+			return true
+		}
+		fields := []models.FieldError{{Field: "stats", Message: "needs a game_id"}}
+		writeValidationError(w, fields, "game card")
+		return false
+	}
+
+	game, err := h.storage.GetGameDefinition(ctx, *card.GameID)
+	if errors.Is(err, storage.ErrNotFound) {
+		fields := []models.FieldError{{Field: "game_id", Message: fmt.Sprintf("no game has ID %s", card.GameID)}}
+		writeValidationError(w, fields, "game card")
+		return false
+	}
+	if err != nil {
+		h.logger.Error("Failed to look up game definition",
+			slog.String("operation", "validate_game"),
+			slog.String("game_id", card.GameID.String()),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Game definition not found", "Failed to validate card")
+		return false
+	}
+
+	if fields := game.ValidateCard(card); len(fields) > 0 {
+		writeValidationError(w, fields, "game card")
+		return false
+	}
+
+	return true
+}
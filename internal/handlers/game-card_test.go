@@ -4,14 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/patch"
 	"github.com/jwebster45206/tcg-api/internal/storage"
 )
 
@@ -22,6 +27,25 @@ func testLogger() *slog.Logger {
 	}))
 }
 
+// serve sends one request to handler. header holds pairs of header names and
+// values; pairs with an empty value are left out.
+func serve(t *testing.T, handler http.Handler, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req, err := http.NewRequest(method, path, bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		if header[i+1] != "" {
+			req.Header.Set(header[i], header[i+1])
+		}
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
 func TestGameCardsHandler_ListCards(t *testing.T) {
 	req, err := http.NewRequest("GET", "/game-cards", nil)
 	if err != nil {
@@ -42,14 +66,166 @@ func TestGameCardsHandler_ListCards(t *testing.T) {
 			status, http.StatusOK)
 	}
 
-	var cards []interface{}
-	if err := json.Unmarshal(rr.Body.Bytes(), &cards); err != nil {
+	var response ListResponse[interface{}]
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
 		t.Errorf("Could not parse response body: %v", err)
 	}
 
 	// Should return an empty list since mock storage starts empty
-	if len(cards) != 0 {
-		t.Errorf("Expected empty card list, got %d cards", len(cards))
+	if response.Data == nil || len(response.Data) != 0 {
+		t.Errorf("Expected empty card list, got %#v", response.Data)
+	}
+	if response.NextCursor != "" {
+		t.Errorf("Expected no next cursor, got %q", response.NextCursor)
+	}
+}
+
+func TestGameCardsHandler_ListCards_PagesAndFilters(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	ctx := context.Background()
+	for i, cost := range []int{5, 1, 4, 2, 3} {
+		card := models.GameCard{
+			Name:   fmt.Sprintf("Card %d", i),
+			Cost:   cost,
+			Colors: []string{"Red"},
+		}
+		if cost%2 == 0 {
+			card.Colors = append(card.Colors, "Blue")
+		}
+		if _, err := mockStorage.CreateGameCard(ctx, card); err != nil {
+			t.Fatalf("Failed to create test card: %v", err)
+		}
+	}
+	handler := NewGameCardsHandler(mockStorage, testLogger())
+
+	list := func(query string) ListResponse[models.GameCard] {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/game-cards?"+query, nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET /game-cards?%s returned %d: %s", query, rr.Code, rr.Body.String())
+		}
+		var response ListResponse[models.GameCard]
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+		return response
+	}
+
+	// Walk every page, most expensive first
+	var costs []int
+	query := "sort=cost&order=desc&limit=2"
+	for {
+		page := list(query)
+		for _, card := range page.Data {
+			costs = append(costs, card.Cost)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		query = "sort=cost&order=desc&limit=2&cursor=" + url.QueryEscape(page.NextCursor)
+	}
+	if !slices.Equal(costs, []int{5, 4, 3, 2, 1}) {
+		t.Errorf("Expected costs 5 to 1 across pages, got %v", costs)
+	}
+
+	filtered := list("colors=Red,Blue&min_cost=3&sort=cost")
+	if len(filtered.Data) != 1 || filtered.Data[0].Cost != 4 {
+		t.Errorf("Expected only the cost 4 card, got %+v", filtered.Data)
+	}
+}
+
+func TestGameCardsHandler_SearchCards(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	ctx := context.Background()
+	for _, card := range []models.GameCard{
+		{Name: "Goblin King", Type: "Creature", Cost: 3, Offense: 2, Colors: []string{"Red"}, Keywords: []string{"Flying"}},
+		{Name: "Ogre", Type: "Creature", Cost: 4, Offense: 4, Colors: []string{"Red"}},
+		{Name: "Angel", Type: "Creature", Cost: 2, Offense: 2, Colors: []string{"White"}, Keywords: []string{"Flying"}},
+	} {
+		if _, err := mockStorage.CreateGameCard(ctx, card); err != nil {
+			t.Fatalf("Failed to create test card: %v", err)
+		}
+	}
+	handler := NewGameCardsHandler(mockStorage, testLogger())
+
+	q := url.QueryEscape("c:red cost<=3 kw:flying o>=2 type:creature")
+	req := httptest.NewRequest("GET", "/game-cards/search?q="+q, nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var response ListResponse[models.GameCard]
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if len(response.Data) != 1 || response.Data[0].Name != "Goblin King" {
+		t.Errorf("Expected only Goblin King, got %+v", response.Data)
+	}
+}
+
+func TestGameCardsHandler_SearchCards_SyntaxError(t *testing.T) {
+	handler := NewGameCardsHandler(storage.NewMockStorage(), testLogger())
+
+	req := httptest.NewRequest("GET", "/game-cards/search?q="+url.QueryEscape("c:red cost<=x"), nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+	var response SearchErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if response.Error != "invalid_search" || response.Column != 13 || response.Token != "cost<=x" {
+		t.Errorf("Expected the error to point at cost<=x, got %+v", response)
+	}
+	if !strings.Contains(response.Message, "whole number") {
+		t.Errorf("Expected a message about the bad number, got %q", response.Message)
+	}
+
+	// An empty query is an error too
+	req = httptest.NewRequest("GET", "/game-cards/search", nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a missing q, got %v", rr.Code)
+	}
+}
+
+func TestGameCardsHandler_ListCards_InvalidQuery(t *testing.T) {
+	handler := NewGameCardsHandler(storage.NewMockStorage(), testLogger())
+
+	tests := []struct {
+		query      string
+		wantStatus int
+		wantError  string
+	}{
+		{"limit=0", http.StatusBadRequest, "invalid_query"},
+		{"limit=1000", http.StatusBadRequest, "invalid_query"},
+		{"order=sideways", http.StatusBadRequest, "invalid_query"},
+		{"min_cost=cheap", http.StatusBadRequest, "invalid_query"},
+		{"is_resource=maybe", http.StatusBadRequest, "invalid_query"},
+		{"sort=power", http.StatusUnprocessableEntity, "validation_failed"},
+		{"cursor=bogus", http.StatusUnprocessableEntity, "validation_failed"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/game-cards?"+tt.query, nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != tt.wantStatus {
+			t.Errorf("%s: expected status %d, got %d", tt.query, tt.wantStatus, rr.Code)
+		}
+		var response ErrorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil || response.Error != tt.wantError {
+			t.Errorf("%s: expected error %q, got %s", tt.query, tt.wantError, rr.Body.String())
+		}
 	}
 }
 
@@ -162,6 +338,15 @@ func TestGameCardsHandler_CreateCard(t *testing.T) {
 	if createdCard.ID == uuid.Nil {
 		t.Error("Expected card to have a generated ID")
 	}
+
+	// The ID is written under "id", like those of the other models
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(rr.Body.Bytes(), &fields); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if _, ok := fields["id"]; !ok {
+		t.Errorf("Expected the card ID under \"id\", got %s", rr.Body.String())
+	}
 }
 
 func TestGameCardsHandler_CreateCard_InvalidJSON(t *testing.T) {
@@ -195,6 +380,43 @@ func TestGameCardsHandler_CreateCard_InvalidJSON(t *testing.T) {
 	}
 }
 
+func TestGameCardsHandler_CreateCard_ValidationFailed(t *testing.T) {
+	handler := NewGameCardsHandler(storage.NewMockStorage(), testLogger())
+
+	// Types, keywords and colors fit the VARCHAR(64) columns of MySQL
+	tag := strings.Repeat("x", models.MaxTagLength+1)
+	body := `{"name": " ", "type": "` + tag + `", "cost": -1, "offense": 1000, "keywords": ["Flying", ""],
+		"colors": ["Red", "` + tag + `"], "front_image_url": "ftp://example.com/a.png",
+		"printings": [{"set_code": "ABC", "front_image_url": "not a url"}]}`
+	req, err := http.NewRequest("POST", "/game-cards", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusUnprocessableEntity {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s",
+			status, http.StatusUnprocessableEntity, rr.Body.String())
+	}
+
+	var response ValidationErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if response.Error != "validation_failed" {
+		t.Errorf("Expected error 'validation_failed', got '%s'", response.Error)
+	}
+	var fields []string
+	for _, field := range response.Fields {
+		fields = append(fields, field.Field)
+	}
+	want := []string{"name", "type", "cost", "offense", "keywords[1]", "colors[1]", "front_image_url", "printings[0].front_image_url"}
+	if !slices.Equal(fields, want) {
+		t.Errorf("Expected invalid fields %v, got %+v", want, response.Fields)
+	}
+}
+
 func TestGameCardsHandler_UpdateCard(t *testing.T) {
 	cardReq := models.GameCard{
 		Name: "Original Card",
@@ -245,6 +467,47 @@ func TestGameCardsHandler_UpdateCard(t *testing.T) {
 	}
 }
 
+func TestGameCardsHandler_PatchCard(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewGameCardsHandler(mockStorage, testLogger())
+
+	card, err := mockStorage.CreateGameCard(context.Background(), models.GameCard{
+		ID: uuid.New(), Name: "Goblin", Type: "Creature", Cost: 1, Keywords: []string{"Haste"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+	path := "/game-cards/" + card.ID.String()
+
+	// A merge patch changes only the members it names
+	rr := serve(t, handler, "PATCH", path, `{"cost": 2, "subtitle": "Raider", "created_at": "2030-01-01T00:00:00Z"}`, "Content-Type", patch.MediaTypeMergePatch)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("merge patch: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	// A JSON Patch can add to and remove from arrays
+	rr = serve(t, handler, "PATCH", path,
+		`[{"op": "add", "path": "/keywords/-", "value": "Trample"}, {"op": "remove", "path": "/keywords/0"},
+		  {"op": "add", "path": "/colors/0", "value": "Red"}]`, "Content-Type", patch.MediaTypeJSONPatch)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("JSON Patch: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var patched models.GameCard
+	if err := json.Unmarshal(rr.Body.Bytes(), &patched); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if patched.ID != card.ID || patched.Name != "Goblin" || patched.Type != "Creature" || patched.Cost != 2 || patched.Subtitle != "Raider" {
+		t.Errorf("Expected only cost and subtitle to change, got %+v", patched)
+	}
+	if !slices.Equal(patched.Keywords, []string{"Trample"}) || !slices.Equal(patched.Colors, []string{"Red"}) {
+		t.Errorf("Expected keywords [Trample] and colors [Red], got %v and %v", patched.Keywords, patched.Colors)
+	}
+	if !patched.CreatedAt.Equal(card.CreatedAt) || patched.Version != card.Version+2 {
+		t.Errorf("Expected created_at to be kept and two new versions, got %v and version %d", patched.CreatedAt, patched.Version)
+	}
+}
+
 func TestGameCardsHandler_UpdateCard_NotFound(t *testing.T) {
 	cardID := uuid.New().String()
 	updateReq := models.GameCard{
@@ -267,9 +530,9 @@ func TestGameCardsHandler_UpdateCard_NotFound(t *testing.T) {
 
 	handler.ServeHTTP(rr, req)
 
-	if status := rr.Code; status != http.StatusInternalServerError {
+	if status := rr.Code; status != http.StatusNotFound {
 		t.Errorf("handler returned wrong status code: got %v want %v",
-			status, http.StatusInternalServerError)
+			status, http.StatusNotFound)
 	}
 
 	var response ErrorResponse
@@ -277,8 +540,262 @@ func TestGameCardsHandler_UpdateCard_NotFound(t *testing.T) {
 		t.Errorf("Could not parse response body: %v", err)
 	}
 
-	if response.Error != "internal_error" {
-		t.Errorf("Expected error 'internal_error', got '%s'", response.Error)
+	if response.Error != "not_found" {
+		t.Errorf("Expected error 'not_found', got '%s'", response.Error)
+	}
+}
+
+func TestGameCardsHandler_CreateCard_Duplicate(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	logger := testLogger()
+
+	card := models.GameCard{ID: uuid.New(), Name: "Existing Card"}
+	if _, err := mockStorage.CreateGameCard(context.Background(), card); err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+
+	jsonBody, _ := json.Marshal(card)
+	req, err := http.NewRequest("POST", "/game-cards", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	handler := NewGameCardsHandler(mockStorage, logger)
+
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusConflict {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusConflict)
+	}
+
+	var response ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Errorf("Could not parse response body: %v", err)
+	}
+
+	if response.Error != "conflict" || response.Message != "Card already exists" {
+		t.Errorf("Unexpected error response: %+v", response)
+	}
+}
+
+func TestGameCardsHandler_CreateCard_Printings(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	if _, err := mockStorage.CreateCardSet(context.Background(), models.CardSet{Code: "CORE", Name: "Core Set"}); err != nil {
+		t.Fatal(err)
+	}
+	handler := NewGameCardsHandler(mockStorage, testLogger())
+
+	tests := []struct {
+		printings  []models.Printing
+		wantStatus int
+		wantField  string
+		wantInMsg  string
+	}{
+		{[]models.Printing{{SetCode: "CORE", CollectorNumber: "1", Rarity: models.RarityRare,
+			FrontImageURL: "https://example.com/alt-art.png"}}, http.StatusCreated, "", ""},
+		{[]models.Printing{{SetCode: "NOPE", CollectorNumber: "1", Rarity: models.RarityRare}},
+			http.StatusUnprocessableEntity, "printings[0].set_code", `"NOPE"`},
+		{[]models.Printing{{SetCode: "CORE", CollectorNumber: "1", Rarity: "legendary"}},
+			http.StatusUnprocessableEntity, "printings[0].rarity", "must be one of"},
+		{[]models.Printing{{SetCode: "CORE", Rarity: models.RarityRare}},
+			http.StatusUnprocessableEntity, "printings[0].collector_number", "is required"},
+		{[]models.Printing{{SetCode: "CORE", CollectorNumber: strings.Repeat("7", models.MaxCollectorNumberLength+1), Rarity: models.RarityRare}},
+			http.StatusUnprocessableEntity, "printings[0].collector_number", "at most 16 characters"},
+		{[]models.Printing{{SetCode: "CORE", CollectorNumber: strings.Repeat("7", models.MaxCollectorNumberLength), Rarity: models.RarityRare}},
+			http.StatusCreated, "", ""},
+		{[]models.Printing{
+			{SetCode: "CORE", CollectorNumber: "1", Rarity: models.RarityRare},
+			{SetCode: "CORE", CollectorNumber: "1", Rarity: models.RarityCommon},
+		}, http.StatusUnprocessableEntity, "printings[1]", "already listed as printings[0]"},
+	}
+
+	for _, tt := range tests {
+		jsonBody, _ := json.Marshal(models.GameCard{Name: "Wyrm", Printings: tt.printings})
+		req, err := http.NewRequest("POST", "/game-cards", bytes.NewBuffer(jsonBody))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != tt.wantStatus {
+			t.Errorf("%+v: handler returned wrong status code: got %v want %v", tt.printings, rr.Code, tt.wantStatus)
+			continue
+		}
+		if tt.wantField == "" {
+			continue
+		}
+		if !hasFieldError(t, rr, tt.wantField, tt.wantInMsg) {
+			t.Errorf("%+v: expected %s to fail mentioning %q, got %s", tt.printings, tt.wantField, tt.wantInMsg, rr.Body.String())
+		}
+	}
+}
+
+func TestGameCardsHandler_CreateCard_Game(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	zero, ten := 0, 10
+	game, err := mockStorage.CreateGameDefinition(context.Background(), models.GameDefinition{
+		Name:      "Skirmish",
+		CardTypes: []string{"Creature", "Spell"},
+		Stats: []models.StatDefinition{
+			{Name: "cost", Type: models.StatTypeInteger, Min: &zero, Max: &ten},
+			{Name: "offense", Type: models.StatTypeInteger},
+			{Name: "loyalty", Type: models.StatTypeInteger, Min: &zero, Required: true},
+			{Name: "flavor", Type: models.StatTypeString},
+		},
+		Colors:   []string{"Red", "Blue"},
+		Keywords: []string{"Flying"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := NewGameCardsHandler(mockStorage, testLogger())
+	unknownGame := uuid.New()
+
+	valid := func() models.GameCard {
+		return models.GameCard{Name: "Wyrm", Type: "Creature", Cost: 7, Offense: 6, GameID: &game.ID,
+			Colors: []string{"Red"}, Keywords: []string{"Flying"},
+			Stats: map[string]any{"loyalty": 3, "flavor": "It remembers"}}
+	}
+	tests := []struct {
+		name       string
+		change     func(c *models.GameCard)
+		wantStatus int
+		wantField  string
+		wantInMsg  string
+	}{
+		{"valid", func(c *models.GameCard) {}, http.StatusCreated, "", ""},
+		{"no game", func(c *models.GameCard) { c.GameID, c.Stats = nil, nil }, http.StatusCreated, "", ""},
+		{"unknown game", func(c *models.GameCard) { c.GameID = &unknownGame }, http.StatusUnprocessableEntity, "game_id", unknownGame.String()},
+		{"stats without game", func(c *models.GameCard) { c.GameID = nil }, http.StatusUnprocessableEntity, "stats", "needs a game_id"},
+		{"card type", func(c *models.GameCard) { c.Type = "Land" }, http.StatusUnprocessableEntity, "type", `"Land" is not a card type`},
+		{"color", func(c *models.GameCard) { c.Colors = []string{"Red", "Green"} }, http.StatusUnprocessableEntity, "colors[1]", `"Green" is not a color`},
+		{"keyword", func(c *models.GameCard) { c.Keywords = []string{"Haste"} }, http.StatusUnprocessableEntity, "keywords[0]", `"Haste" is not a keyword`},
+		{"out of range", func(c *models.GameCard) { c.Cost = 11 }, http.StatusUnprocessableEntity, "cost", "is 11, outside 0 to 10"},
+		{"undeclared builtin", func(c *models.GameCard) { c.Defense = 2 }, http.StatusUnprocessableEntity, "defense", "is not a stat"},
+		{"missing required", func(c *models.GameCard) { delete(c.Stats, "loyalty") }, http.StatusUnprocessableEntity, "stats.loyalty", "is required"},
+		{"wrong stat type", func(c *models.GameCard) { c.Stats["flavor"] = 3 }, http.StatusUnprocessableEntity, "stats.flavor", "must be a string"},
+		{"fractional stat", func(c *models.GameCard) { c.Stats["loyalty"] = 1.5 }, http.StatusUnprocessableEntity, "stats.loyalty", "must be a whole number"},
+		{"unknown stat", func(c *models.GameCard) { c.Stats["speed"] = 2 }, http.StatusUnprocessableEntity, "stats.speed", "is not a stat"},
+	}
+
+	for _, tt := range tests {
+		card := valid()
+		tt.change(&card)
+		jsonBody, _ := json.Marshal(card)
+		req, err := http.NewRequest("POST", "/game-cards", bytes.NewBuffer(jsonBody))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != tt.wantStatus {
+			t.Errorf("%s: handler returned wrong status code: got %v want %v: %s", tt.name, rr.Code, tt.wantStatus, rr.Body.String())
+			continue
+		}
+		if tt.wantField == "" {
+			continue
+		}
+		if !hasFieldError(t, rr, tt.wantField, tt.wantInMsg) {
+			t.Errorf("%s: expected %s to fail mentioning %q, got %s", tt.name, tt.wantField, tt.wantInMsg, rr.Body.String())
+		}
+	}
+
+	// Cards of the game can be listed
+	req, err := http.NewRequest("GET", "/game-cards?game_id="+game.ID.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	var response ListResponse[models.GameCard]
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if len(response.Data) != 1 || response.Data[0].Stats["flavor"] != "It remembers" {
+		t.Errorf("Expected the one valid card of the game, got %+v", response.Data)
+	}
+}
+
+func TestGameCardsHandler_CreateCard_Registries(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	ctx := context.Background()
+	for registry, entries := range map[string][]models.RegistryEntry{
+		models.RegistryKeywords: {
+			{Name: "Flying", Aliases: []string{"flyer"}, ReminderText: "Can only be blocked by flyers."},
+			{Name: "Haste", ReminderText: "Can attack right away."},
+		},
+		models.RegistryColors: {{Name: "Red", Aliases: []string{"R"}}},
+	} {
+		for _, entry := range entries {
+			if _, err := mockStorage.CreateRegistryEntry(ctx, registry, entry); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	handler := NewGameCardsHandler(mockStorage, testLogger())
+
+	// Aliases and other cases are saved as the canonical names
+	jsonBody, _ := json.Marshal(models.GameCard{Name: "Wyrm",
+		Keywords: []string{"flyer", "HASTE", "Flying", "Trample"}, Colors: []string{"r"}})
+	req, err := http.NewRequest("POST", "/game-cards", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnprocessableEntity || !hasFieldError(t, rr, "keywords[3]", `unknown keyword "Trample"`) {
+		t.Errorf("Expected 422 for an unknown keyword, got %v %s", rr.Code, rr.Body.String())
+	}
+
+	jsonBody, _ = json.Marshal(models.GameCard{Name: "Wyrm",
+		Keywords: []string{"flyer", "HASTE", "Flying"}, Colors: []string{"r"}})
+	req, err = http.NewRequest("POST", "/game-cards", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	var created models.GameCard
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if rr.Code != http.StatusCreated || !slices.Equal(created.Keywords, []string{"Flying", "Haste"}) ||
+		!slices.Equal(created.Colors, []string{"Red"}) {
+		t.Fatalf("Expected canonical keywords and colors, got %v %+v", rr.Code, created)
+	}
+
+	// ?expand=keywords adds reminder text
+	req, err = http.NewRequest("GET", "/game-cards/"+created.ID.String()+"?expand=keywords", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	var expanded struct {
+		Name           string                  `json:"name"`
+		KeywordDetails []*models.RegistryEntry `json:"keyword_details"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &expanded); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if rr.Code != http.StatusOK || expanded.Name != "Wyrm" || len(expanded.KeywordDetails) != 2 ||
+		expanded.KeywordDetails[1].ReminderText != "Can attack right away." {
+		t.Errorf("Expected keyword details, got %v %s", rr.Code, rr.Body.String())
+	}
+
+	req, err = http.NewRequest("GET", "/game-cards/"+created.ID.String()+"?expand=colors", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown expand field, got %v", rr.Code)
 	}
 }
 
@@ -331,9 +848,9 @@ func TestGameCardsHandler_DeleteCard_NotFound(t *testing.T) {
 
 	handler.ServeHTTP(rr, req)
 
-	if status := rr.Code; status != http.StatusInternalServerError {
+	if status := rr.Code; status != http.StatusNotFound {
 		t.Errorf("handler returned wrong status code: got %v want %v",
-			status, http.StatusInternalServerError)
+			status, http.StatusNotFound)
 	}
 
 	var response ErrorResponse
@@ -341,8 +858,8 @@ func TestGameCardsHandler_DeleteCard_NotFound(t *testing.T) {
 		t.Errorf("Could not parse response body: %v", err)
 	}
 
-	if response.Error != "internal_error" {
-		t.Errorf("Expected error 'internal_error', got '%s'", response.Error)
+	if response.Error != "not_found" {
+		t.Errorf("Expected error 'not_found', got '%s'", response.Error)
 	}
 }
 
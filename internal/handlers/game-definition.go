@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// GameDefinitionsHandler serves /game-definitions, the rules game cards of
+// each game follow
+type GameDefinitionsHandler struct {
+	storage storage.Storage
+	logger  *slog.Logger
+}
+
+// NewGameDefinitionsHandler creates a new GameDefinitionsHandler with the
+// given dependencies
+func NewGameDefinitionsHandler(storage storage.Storage, logger *slog.Logger) *GameDefinitionsHandler {
+	return &GameDefinitionsHandler{
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+func (h *GameDefinitionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	gameID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/game-definitions"), "/")
+
+	switch r.Method {
+	case http.MethodGet:
+		if gameID == "" {
+			// GET /game-definitions - List all game definitions
+			h.listGames(w, r)
+		} else {
+			// GET /game-definitions/{id} - Get specific game definition
+			h.getGame(w, r, gameID)
+		}
+
+	case http.MethodPost:
+		if gameID == "" {
+			// POST /game-definitions - Create new game definition
+			h.createGame(w, r)
+		} else {
+			http.Error(w, "Method not allowed for this path", http.StatusMethodNotAllowed)
+		}
+
+	case http.MethodPut:
+		if gameID != "" {
+			// PUT /game-definitions/{id} - Update game definition
+			h.updateGame(w, r, gameID)
+		} else {
+			http.Error(w, "Game definition ID required for update", http.StatusBadRequest)
+		}
+
+	case http.MethodDelete:
+		if gameID != "" {
+			// DELETE /game-definitions/{id} - Delete game definition
+			h.deleteGame(w, r, gameID)
+		} else {
+			http.Error(w, "Game definition ID required for deletion", http.StatusBadRequest)
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listGames handles GET /game-definitions
+func (h *GameDefinitionsHandler) listGames(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	games, err := h.storage.ListGameDefinitions(ctx)
+	if err != nil {
+		h.logger.Error("Failed to list game definitions",
+			slog.String("operation", "list_game_definitions"),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Game definition not found", "Failed to retrieve game definitions")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, ListResponse[*models.GameDefinition]{Data: games})
+}
+
+// getGame handles GET /game-definitions/{id}
+func (h *GameDefinitionsHandler) getGame(w http.ResponseWriter, r *http.Request, gameID string) {
+	id, ok := parseGameID(w, gameID)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	game, err := h.storage.GetGameDefinition(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to get game definition",
+			slog.String("operation", "get_game_definition"),
+			slog.String("game_id", gameID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Game definition not found", "Failed to retrieve game definition")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, game)
+}
+
+// createGame handles POST /game-definitions
+func (h *GameDefinitionsHandler) createGame(w http.ResponseWriter, r *http.Request) {
+	var game models.GameDefinition
+	if !decodeJSON(w, r, &game) {
+		return
+	}
+	if !validateGameDefinition(w, &game) {
+		return
+	}
+
+	ctx := r.Context()
+	createdGame, err := h.storage.CreateGameDefinition(ctx, game)
+	if err != nil {
+		h.logger.Error("Failed to create game definition",
+			slog.String("operation", "create_game_definition"),
+			slog.String("game_name", game.Name),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Game definition not found", "Failed to create game definition")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, createdGame)
+}
+
+// updateGame handles PUT /game-definitions/{id}. Cards already in the game
+// are not checked against the new rules until they are next written.
+func (h *GameDefinitionsHandler) updateGame(w http.ResponseWriter, r *http.Request, gameID string) {
+	id, ok := parseGameID(w, gameID)
+	if !ok {
+		return
+	}
+
+	var game models.GameDefinition
+	if !decodeJSON(w, r, &game) {
+		return
+	}
+
+	// Set the ID from the URL path
+	game.ID = id
+	if !validateGameDefinition(w, &game) {
+		return
+	}
+
+	ctx := r.Context()
+	updatedGame, err := h.storage.UpdateGameDefinition(ctx, game)
+	if err != nil {
+		h.logger.Error("Failed to update game definition",
+			slog.String("operation", "update_game_definition"),
+			slog.String("game_id", gameID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Game definition not found", "Failed to update game definition")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, updatedGame)
+}
+
+// deleteGame handles DELETE /game-definitions/{id}. Games that cards still
+// belong to cannot be deleted.
+func (h *GameDefinitionsHandler) deleteGame(w http.ResponseWriter, r *http.Request, gameID string) {
+	id, ok := parseGameID(w, gameID)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.storage.DeleteGameDefinition(ctx, id); err != nil {
+		h.logger.Error("Failed to delete game definition",
+			slog.String("operation", "delete_game_definition"),
+			slog.String("game_id", gameID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Game definition not found", "Failed to delete game definition")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseGameID parses a game definition ID from the URL path. It writes an
+// error response and returns false if the ID is malformed.
+func parseGameID(w http.ResponseWriter, gameID string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(gameID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid game definition ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// validateGameDefinition checks that a game definition is self-consistent,
+// defaulting missing lists to empty ones. It writes an error response and
+// returns false if the definition is invalid.
+func validateGameDefinition(w http.ResponseWriter, game *models.GameDefinition) bool {
+	for _, list := range []*[]string{&game.CardTypes, &game.Colors, &game.Keywords} {
+		if *list == nil {
+			*list = []string{}
+		}
+	}
+	if game.Stats == nil {
+		game.Stats = []models.StatDefinition{}
+	}
+
+	return validateModel(w, game, "game definition")
+}
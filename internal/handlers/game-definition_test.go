@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+func TestGameDefinitionsHandler_CRUD(t *testing.T) {
+	handler := NewGameDefinitionsHandler(storage.NewMockStorage(), testLogger())
+
+	rr := serve(t, handler, "POST", "/game-definitions", `{
+		"name": "Skirmish",
+		"card_types": ["Creature", "Spell"],
+		"stats": [{"name": "cost", "type": "integer", "min": 0, "max": 10},
+		          {"name": "loyalty", "type": "integer", "required": true}],
+		"deck_rules": {"min_cards": 40, "max_copies": 3}
+	}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create: got %v want %v: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+	var created models.GameDefinition
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if created.ID == uuid.Nil || len(created.Stats) != 2 || *created.Stats[0].Max != 10 ||
+		created.Colors == nil || created.DeckRules.MaxCopies != 3 {
+		t.Errorf("create: expected the definition with an ID and empty lists, got %+v", created)
+	}
+	path := "/game-definitions/" + created.ID.String()
+
+	rr = serve(t, handler, "PUT", path, `{"name": "Skirmish 2E", "colors": ["Red"]}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("update: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	rr = serve(t, handler, "GET", path, "")
+	var game models.GameDefinition
+	if err := json.Unmarshal(rr.Body.Bytes(), &game); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if rr.Code != http.StatusOK || game.Name != "Skirmish 2E" || len(game.Colors) != 1 || len(game.Stats) != 0 {
+		t.Errorf("get: expected the updated definition, got %v %+v", rr.Code, game)
+	}
+
+	rr = serve(t, handler, "GET", "/game-definitions", "")
+	var list ListResponse[models.GameDefinition]
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].ID != created.ID {
+		t.Errorf("list: expected the one definition, got %+v", list.Data)
+	}
+
+	rr = serve(t, handler, "DELETE", path, "")
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("delete: got %v want %v", rr.Code, http.StatusNoContent)
+	}
+	for _, method := range []string{"GET", "DELETE"} {
+		if rr := serve(t, handler, method, path, ""); rr.Code != http.StatusNotFound {
+			t.Errorf("%s deleted definition: got %v want %v", method, rr.Code, http.StatusNotFound)
+		}
+	}
+	if rr := serve(t, handler, "GET", "/game-definitions/not-a-uuid", ""); rr.Code != http.StatusBadRequest {
+		t.Errorf("invalid ID: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGameDefinitionsHandler_InvalidDefinition(t *testing.T) {
+	handler := NewGameDefinitionsHandler(storage.NewMockStorage(), testLogger())
+
+	tests := []struct {
+		body      string
+		wantField string
+	}{
+		{`{"card_types": ["Creature"]}`, "name"},
+		{`{"name": "` + strings.Repeat("S", models.MaxNameLength+1) + `"}`, "name"},
+		{`{"name": "Skirmish", "stats": [{"name": "speed", "type": "float"}]}`, "stats[0].type"},
+		{`{"name": "Skirmish", "stats": [{"name": "speed", "type": "integer"}, {"name": "speed", "type": "integer"}]}`, "stats[1].name"},
+		{`{"name": "Skirmish", "stats": [{"name": "speed", "type": "integer", "min": 5, "max": 1}]}`, "stats[0].min"},
+		{`{"name": "Skirmish", "stats": [{"name": "flavor", "type": "string", "max": 1}]}`, "stats[0].max"},
+		{`{"name": "Skirmish", "stats": [{"name": "cost", "type": "string"}]}`, "stats[0].type"},
+		{`{"name": "Skirmish", "deck_rules": {"min_cards": 60, "max_cards": 40}}`, "deck_rules.min_cards"},
+		{`{"name": "Skirmish", "deck_rules": {"max_copies": -1}}`, "deck_rules.max_copies"},
+		{`{"name": "Skirmish", "formats": [{"max_copies": 1}]}`, "formats[0].name"},
+		{`{"name": "Skirmish", "formats": [{"name": "Singleton"}, {"name": "Singleton"}]}`, "formats[1].name"},
+		{`{"name": "Skirmish", "formats": [{"name": "Big", "min_cards": 60, "max_cards": 40}]}`, "formats[0].min_cards"},
+		{`{"name": "Skirmish", "formats": [{"name": "Lands", "min_resource_ratio": 0.5, "max_resource_ratio": 0.25}]}`, "formats[0].min_resource_ratio"},
+		{`{"name": "Skirmish", "formats": [{"name": "Lands", "max_resource_ratio": 1.5}]}`, "formats[0].max_resource_ratio"},
+		{`{"name": "Skirmish", "colors": ["Red"], "formats": [{"name": "Mono Blue", "colors": ["Blue"]}]}`, "formats[0].colors[0]"},
+	}
+	for _, tt := range tests {
+		rr := serve(t, handler, "POST", "/game-definitions", tt.body)
+		var response ValidationErrorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+		if rr.Code != http.StatusUnprocessableEntity || response.Error != "validation_failed" ||
+			len(response.Fields) != 1 || response.Fields[0].Field != tt.wantField {
+			t.Errorf("%.60s: expected 422 on %s, got %v %s", tt.body, tt.wantField, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestGameDefinitionsHandler_DeleteInUse(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	ctx := context.Background()
+	game, err := mockStorage.CreateGameDefinition(ctx, models.GameDefinition{Name: "Skirmish"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mockStorage.CreateGameCard(ctx, models.GameCard{Name: "Wyrm", GameID: &game.ID}); err != nil {
+		t.Fatal(err)
+	}
+	handler := NewGameDefinitionsHandler(mockStorage, testLogger())
+
+	rr := serve(t, handler, "DELETE", "/game-definitions/"+game.ID.String(), "")
+	var response ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if rr.Code != http.StatusConflict || response.Message != "Game still has cards" {
+		t.Errorf("delete game in use: expected 409, got %v %+v", rr.Code, response)
+	}
+}
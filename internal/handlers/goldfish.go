@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/goldfish"
+	"github.com/jwebster45206/tcg-api/internal/jobs"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// JobKindGoldfish is the kind of goldfish simulation jobs
+const JobKindGoldfish = "goldfish"
+
+// GoldfishHandler starts goldfish simulations of decks as background jobs.
+// DecksHandler routes POST /decks/{id}/goldfish to it.
+type GoldfishHandler struct {
+	storage storage.Storage
+	jobs    *jobs.Manager
+	logger  *slog.Logger
+}
+
+// NewGoldfishHandler creates a new GoldfishHandler with the given dependencies
+func NewGoldfishHandler(storage storage.Storage, jobs *jobs.Manager, logger *slog.Logger) *GoldfishHandler {
+	return &GoldfishHandler{
+		storage: storage,
+		jobs:    jobs,
+		logger:  logger,
+	}
+}
+
+// startGoldfish handles POST /decks/{id}/goldfish. The body holds the
+// simulation settings, all optional. It responds 202 with the job, which is
+// polled at GET /jobs/{id}.
+func (h *GoldfishHandler) startGoldfish(w http.ResponseWriter, r *http.Request, deckID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(deckID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid deck ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	var settings goldfish.Settings
+	if !decodeOptionalJSON(w, r, &settings) {
+		return
+	}
+
+	ctx := r.Context()
+	deck, err := h.storage.GetDeck(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to get deck",
+			slog.String("operation", "start_goldfish"),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to retrieve deck")
+		return
+	}
+	cards, err := gameCards(ctx, h.storage, deck)
+	if err != nil {
+		h.logger.Error("Failed to look up deck cards",
+			slog.String("operation", "start_goldfish"),
+			slog.String("deck_id", deckID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Deck not found", "Failed to retrieve deck cards")
+		return
+	}
+
+	// The game is played with the main and commander sections. Cards that
+	// are not game cards take up space but are never cast.
+	var library []goldfish.Card
+	for _, entry := range deck.PlayedEntries() {
+		card := goldfish.Card{ID: entry.CardID}
+		if gameCard := cards[entry.CardID]; gameCard != nil {
+			card.Cost, card.Resource, card.Castable = gameCard.Cost, gameCard.IsResource, true
+		}
+		for range entry.Quantity {
+			library = append(library, card)
+		}
+	}
+
+	settings.Defaults()
+	if fields := settings.Validate(len(library)); len(fields) > 0 {
+		writeValidationError(w, fields, "simulation settings")
+		return
+	}
+
+	job, err := h.jobs.Start(JobKindGoldfish, settings.Games, func(ctx context.Context, progress func(done int)) (any, error) {
+		return goldfish.Run(ctx, library, settings, progress)
+	})
+	if err != nil {
+		// Start only fails with jobs.ErrTooManyJobs
+		response := ErrorResponse{
+			Error:   "too_many_jobs",
+			Message: "Too many jobs are running; try again later",
+		}
+		writeJSONResponse(w, http.StatusServiceUnavailable, response)
+		return
+	}
+
+	w.Header().Set("Location", "/jobs/"+job.ID.String())
+	writeJSONResponse(w, http.StatusAccepted, job)
+}
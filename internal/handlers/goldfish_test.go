@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/goldfish"
+	"github.com/jwebster45206/tcg-api/internal/jobs"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// goldfishJob is a job whose result is a goldfish run
+type goldfishJob struct {
+	jobs.Job
+	Result *goldfish.Result `json:"result"`
+}
+
+// awaitJob polls GET /jobs/{id} until the job finishes
+func awaitJob(t *testing.T, handler http.Handler, id uuid.UUID) goldfishJob {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		rr := serve(t, handler, "GET", "/jobs/"+id.String(), "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("get job: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+		}
+		var job goldfishJob
+		if err := json.Unmarshal(rr.Body.Bytes(), &job); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+		if job.Finished() {
+			return job
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return goldfishJob{}
+}
+
+func TestDecksHandler_Goldfish(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	manager := jobs.NewManager(1, 10)
+	defer manager.Close()
+	handler := NewDecksHandler(mockStorage, NewGoldfishHandler(mockStorage, manager, testLogger()), testLogger())
+	jobsHandler := NewJobsHandler(manager, testLogger())
+	ctx := context.Background()
+
+	land, err := mockStorage.CreateGameCard(ctx, models.GameCard{Name: "Mountain", IsResource: true})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+	bolt, err := mockStorage.CreateGameCard(ctx, models.GameCard{Name: "Fire Bolt", Cost: 1})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+	wyrm, err := mockStorage.CreateGameCard(ctx, models.GameCard{Name: "Ancient Wyrm", Cost: 4})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+	deck := models.Deck{Name: "Burn"}
+	deck.AddCard(land.ID, models.CardTypeGameCard, models.SectionMain, 16)
+	deck.AddCard(bolt.ID, models.CardTypeGameCard, models.SectionMain, 12)
+	deck.AddCard(wyrm.ID, models.CardTypeGameCard, models.SectionMain, 12)
+	deck.AddCard(wyrm.ID, models.CardTypeGameCard, models.SectionSideboard, 3)
+	created, err := mockStorage.CreateDeck(ctx, deck)
+	if err != nil {
+		t.Fatalf("Failed to create test deck: %v", err)
+	}
+	path := "/decks/" + created.ID.String() + "/goldfish"
+	body := `{"games": 50, "seed": "goldfish", "turns": 6, "mulligan": {"min_resources": 2, "max_resources": 5, "max_mulligans": 1}}`
+
+	var results []*goldfish.Result
+	for range 2 {
+		rr := serve(t, handler, "POST", path, body, "Content-Type", "application/json")
+		if rr.Code != http.StatusAccepted {
+			t.Fatalf("handler returned wrong status code: got %v want %v: %s", rr.Code, http.StatusAccepted, rr.Body.String())
+		}
+		var job jobs.Job
+		if err := json.Unmarshal(rr.Body.Bytes(), &job); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+		if job.Kind != JobKindGoldfish || job.Total != 50 || rr.Header().Get("Location") != "/jobs/"+job.ID.String() {
+			t.Errorf("Unexpected job %+v at %q", job, rr.Header().Get("Location"))
+		}
+
+		finished := awaitJob(t, jobsHandler, job.ID)
+		if finished.Status != jobs.StatusSucceeded || finished.Done != 50 || finished.Result == nil {
+			t.Fatalf("Expected the job to succeed, got %+v", finished)
+		}
+		results = append(results, finished.Result)
+	}
+
+	// The same seed plays the same games
+	if !reflect.DeepEqual(results[0], results[1]) {
+		t.Errorf("Expected identical results for one seed:\n%+v\n%+v", results[0], results[1])
+	}
+	result := results[0]
+	if result.Seed != "goldfish" || len(result.Resources) != 6 || len(result.Mulligans) != 2 ||
+		len(result.FirstCastable) != 2 || result.FirstCastable[0].Cost != 1 || result.FirstCastable[1].Cost != 4 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if turns := result.FirstCastable[1].Turns; turns[0]+turns[1]+turns[2] != 0 {
+		t.Errorf("Expected no 4-cost card castable before turn 4, got %v", turns)
+	}
+
+	for _, tt := range []struct {
+		name, path, body string
+		wantStatus       int
+		wantError        string
+	}{
+		{"invalid settings", path, `{"games": -1, "hand_size": 41}`, http.StatusUnprocessableEntity, "validation_failed"},
+		{"unknown setting", path, `{"players": 2}`, http.StatusBadRequest, "invalid_json"},
+		{"missing deck", "/decks/" + uuid.New().String() + "/goldfish", "", http.StatusNotFound, "not_found"},
+		{"invalid deck", "/decks/nope/goldfish", "", http.StatusBadRequest, "invalid_id"},
+	} {
+		rr := serve(t, handler, "POST", tt.path, tt.body, "Content-Type", "application/json")
+		var response ErrorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: could not parse response body: %v", tt.name, err)
+		}
+		if rr.Code != tt.wantStatus || response.Error != tt.wantError {
+			t.Errorf("%s: expected %v %s, got %v %+v", tt.name, tt.wantStatus, tt.wantError, rr.Code, response)
+		}
+	}
+	rr := serve(t, handler, "POST", path, `{"hand_size": 7, "mulligan": {"max_mulligans": 7}}`, "Content-Type", "application/json")
+	if !hasFieldError(t, rr, "mulligan.max_mulligans", "less than hand_size") {
+		t.Errorf("Expected a mulligan.max_mulligans field error, got %s", rr.Body)
+	}
+	if rr := serve(t, handler, "GET", path, ""); rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: got %v want %v", rr.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestJobsHandler(t *testing.T) {
+	manager := jobs.NewManager(1, 10)
+	defer manager.Close()
+	handler := NewJobsHandler(manager, testLogger())
+
+	job, err := manager.Start("wait", 1, func(ctx context.Context, progress func(done int)) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	path := "/jobs/" + job.ID.String()
+
+	rr := serve(t, handler, "POST", path+"/cancel", "")
+	var canceled jobs.Job
+	if err := json.Unmarshal(rr.Body.Bytes(), &canceled); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if rr.Code != http.StatusOK || canceled.Status != jobs.StatusCanceled || canceled.FinishedAt == nil {
+		t.Errorf("cancel: got %v %+v", rr.Code, canceled)
+	}
+	if finished := awaitJob(t, handler, job.ID); finished.Status != jobs.StatusCanceled {
+		t.Errorf("Expected the job to stay canceled, got %+v", finished)
+	}
+
+	for _, tt := range []struct {
+		name, method, path string
+		wantStatus         int
+	}{
+		{"unknown job", "GET", "/jobs/" + uuid.New().String(), http.StatusNotFound},
+		{"cancel unknown job", "POST", "/jobs/" + uuid.New().String() + "/cancel", http.StatusNotFound},
+		{"invalid ID", "GET", "/jobs/nope", http.StatusBadRequest},
+		{"wrong method", "DELETE", path, http.StatusMethodNotAllowed},
+		{"unknown action", "POST", path + "/pause", http.StatusNotFound},
+	} {
+		if rr := serve(t, handler, tt.method, tt.path, ""); rr.Code != tt.wantStatus {
+			t.Errorf("%s: got %v want %v", tt.name, rr.Code, tt.wantStatus)
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
 	"log/slog"
 	"net/http"
 	"strings"
@@ -56,6 +56,15 @@ func (h *ImageCardsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Card ID required for update", http.StatusBadRequest)
 		}
 
+	case http.MethodPatch:
+		if path != "" && path != "/" {
+			// PATCH /image-cards/{id} - Partially update card
+			cardID := strings.Trim(path, "/")
+			h.patchCard(w, r, cardID)
+		} else {
+			http.Error(w, "Method not allowed for this path", http.StatusMethodNotAllowed)
+		}
+
 	case http.MethodDelete:
 		if path != "" && path != "/" {
 			// DELETE /image-cards/{id} - Delete card
@@ -70,24 +79,26 @@ func (h *ImageCardsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// listCards handles GET /image-cards
+// listCards handles GET /image-cards. See parsePageRequest for the query
+// parameters; image cards sort by name or created_at.
 func (h *ImageCardsHandler) listCards(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	pageReq, err := parsePageRequest(r.URL.Query())
+	if err != nil {
+		writeInvalidQuery(w, err)
+		return
+	}
 
-	cards, err := h.storage.ListImageCards(ctx)
+	ctx := r.Context()
+	page, err := h.storage.ListImageCardsPage(ctx, pageReq)
 	if err != nil {
 		h.logger.Error("Failed to list image cards",
 			slog.String("operation", "list_image_cards"),
 			slog.Any("error", err))
-		response := ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to retrieve image cards",
-		}
-		writeJSONResponse(w, http.StatusInternalServerError, response)
+		writeStorageError(w, err, "Card not found", "Failed to retrieve image cards")
 		return
 	}
 
-	writeJSONResponse(w, http.StatusOK, cards)
+	writeJSONResponse(w, http.StatusOK, newListResponse(page))
 }
 
 // getCard handles GET /image-cards/{id}
@@ -110,13 +121,10 @@ func (h *ImageCardsHandler) getCard(w http.ResponseWriter, r *http.Request, card
 			slog.String("operation", "get_image_card"),
 			slog.String("card_id", cardID),
 			slog.Any("error", err))
-		response := ErrorResponse{
-			Error:   "not_found",
-			Message: "Card not found",
-		}
-		writeJSONResponse(w, http.StatusNotFound, response)
+		writeStorageError(w, err, "Card not found", "Failed to retrieve image card")
 		return
 	}
+	setETag(w, card.Version)
 
 	writeJSONResponse(w, http.StatusOK, card)
 }
@@ -124,12 +132,7 @@ func (h *ImageCardsHandler) getCard(w http.ResponseWriter, r *http.Request, card
 // createCard handles POST /image-cards
 func (h *ImageCardsHandler) createCard(w http.ResponseWriter, r *http.Request) {
 	var card models.ImageCard
-	if err := json.NewDecoder(r.Body).Decode(&card); err != nil {
-		response := ErrorResponse{
-			Error:   "invalid_json",
-			Message: "Invalid JSON in request body",
-		}
-		writeJSONResponse(w, http.StatusBadRequest, response)
+	if !decodeJSON(w, r, &card) || !validateModel(w, &card, "image card") {
 		return
 	}
 
@@ -140,14 +143,11 @@ func (h *ImageCardsHandler) createCard(w http.ResponseWriter, r *http.Request) {
 			slog.String("operation", "create_image_card"),
 			slog.String("card_name", card.Name),
 			slog.Any("error", err))
-		response := ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to create image card",
-		}
-		writeJSONResponse(w, http.StatusInternalServerError, response)
+		writeStorageError(w, err, "Card not found", "Failed to create image card")
 		return
 	}
 
+	setETag(w, createdCard.Version)
 	writeJSONResponse(w, http.StatusCreated, createdCard)
 }
 
@@ -165,36 +165,97 @@ func (h *ImageCardsHandler) updateCard(w http.ResponseWriter, r *http.Request, c
 	}
 
 	var card models.ImageCard
-	if err := json.NewDecoder(r.Body).Decode(&card); err != nil {
+	if !decodeJSON(w, r, &card) {
+		return
+	}
+
+	// Set the ID from the URL path. The version to replace comes from
+	// If-Match, not the body.
+	card.ID = id
+	card.Version, err = ifMatchVersion(r, id, h.cardVersion)
+	if err != nil {
+		h.logger.Error("Failed to update image card",
+			slog.String("operation", "update_image_card"),
+			slog.String("card_id", cardID),
+			slog.Any("error", err))
+		writeWriteError(w, r, err, "Card not found", "Failed to update image card")
+		return
+	}
+	h.saveCard(w, r, card)
+}
+
+// patchCard handles PATCH /image-cards/{id}, applying a JSON merge patch or
+// JSON Patch to the stored card
+func (h *ImageCardsHandler) patchCard(w http.ResponseWriter, r *http.Request, cardID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(cardID)
+	if err != nil {
 		response := ErrorResponse{
-			Error:   "invalid_json",
-			Message: "Invalid JSON in request body",
+			Error:   "invalid_id",
+			Message: "Invalid card ID format",
 		}
 		writeJSONResponse(w, http.StatusBadRequest, response)
 		return
 	}
 
 	ctx := r.Context()
-	// Set the ID from the URL path
+	current, err := h.storage.GetImageCard(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to get image card",
+			slog.String("operation", "patch_image_card"),
+			slog.String("card_id", cardID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card not found", "Failed to update image card")
+		return
+	}
+	if !ifMatches(r, current.Version) {
+		writeWriteError(w, r, errPreconditionFailed, "Card not found", "Failed to update image card")
+		return
+	}
+
+	var card models.ImageCard
+	if !applyPatch(w, r, current, &card, "image card") {
+		return
+	}
+
+	// The ID cannot be patched, and the stored image card must still be the
+	// version the patch was applied to
 	card.ID = id
+	card.Version = current.Version
+	h.saveCard(w, r, card)
+}
+
+// saveCard validates a full replacement for a stored card and saves it
+func (h *ImageCardsHandler) saveCard(w http.ResponseWriter, r *http.Request, card models.ImageCard) {
+	if !validateModel(w, &card, "image card") {
+		return
+	}
+
+	ctx := r.Context()
 	updatedCard, err := h.storage.UpdateImageCard(ctx, card)
 	if err != nil {
 		h.logger.Error("Failed to update image card",
 			slog.String("operation", "update_image_card"),
-			slog.String("card_id", cardID),
+			slog.String("card_id", card.ID.String()),
 			slog.String("card_name", card.Name),
 			slog.Any("error", err))
-		response := ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to update image card",
-		}
-		writeJSONResponse(w, http.StatusInternalServerError, response)
+		writeWriteError(w, r, err, "Card not found", "Failed to update image card")
 		return
 	}
 
+	setETag(w, updatedCard.Version)
 	writeJSONResponse(w, http.StatusOK, updatedCard)
 }
 
+// cardVersion returns the version of a stored image card, for ifMatchVersion
+func (h *ImageCardsHandler) cardVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	card, err := h.storage.GetImageCard(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return card.Version, nil
+}
+
 // deleteCard handles DELETE /image-cards/{id}
 func (h *ImageCardsHandler) deleteCard(w http.ResponseWriter, r *http.Request, cardID string) {
 	// Validate UUID format
@@ -208,17 +269,16 @@ func (h *ImageCardsHandler) deleteCard(w http.ResponseWriter, r *http.Request, c
 		return
 	}
 
-	ctx := r.Context()
-	if err := h.storage.DeleteImageCard(ctx, id); err != nil {
+	version, err := ifMatchVersion(r, id, h.cardVersion)
+	if err == nil {
+		err = h.storage.DeleteImageCard(r.Context(), id, version)
+	}
+	if err != nil {
 		h.logger.Error("Failed to delete image card",
 			slog.String("operation", "delete_image_card"),
 			slog.String("card_id", cardID),
 			slog.Any("error", err))
-		response := ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to delete image card",
-		}
-		writeJSONResponse(w, http.StatusInternalServerError, response)
+		writeWriteError(w, r, err, "Card not found", "Failed to delete image card")
 		return
 	}
 
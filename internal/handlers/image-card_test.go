@@ -6,10 +6,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/patch"
 	"github.com/jwebster45206/tcg-api/internal/storage"
 )
 
@@ -33,14 +35,17 @@ func TestImageCardsHandler_ListCards(t *testing.T) {
 			status, http.StatusOK)
 	}
 
-	var cards []interface{}
-	if err := json.Unmarshal(rr.Body.Bytes(), &cards); err != nil {
+	var response ListResponse[interface{}]
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
 		t.Errorf("Could not parse response body: %v", err)
 	}
 
 	// Should return an empty list since mock storage starts empty
-	if len(cards) != 0 {
-		t.Errorf("Expected empty card list, got %d cards", len(cards))
+	if response.Data == nil || len(response.Data) != 0 {
+		t.Errorf("Expected empty card list, got %#v", response.Data)
+	}
+	if response.NextCursor != "" {
+		t.Errorf("Expected no next cursor, got %q", response.NextCursor)
 	}
 }
 
@@ -146,6 +151,36 @@ func TestImageCardsHandler_CreateCard_InvalidJSON(t *testing.T) {
 	}
 }
 
+func TestImageCardsHandler_CreateCard_ValidationFailed(t *testing.T) {
+	handler := NewImageCardsHandler(storage.NewMockStorage(), testLogger())
+
+	tests := []struct {
+		body      string
+		wantField string
+	}{
+		{`{"front_image_url": "https://example.com/a.png"}`, "name"},
+		{`{"name": "Sunset", "description": "` + strings.Repeat("x", models.MaxDescriptionLength+1) + `"}`, "description"},
+		{`{"name": "Sunset", "front_image_url": "/images/a.png"}`, "front_image_url"},
+		{`{"name": "Sunset", "back_image_url": "https://"}`, "back_image_url"},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest("POST", "/image-cards", bytes.NewBufferString(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		var response ValidationErrorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+		if rr.Code != http.StatusUnprocessableEntity || len(response.Fields) != 1 || response.Fields[0].Field != tt.wantField {
+			t.Errorf("Expected 422 for %s, got %v %+v", tt.wantField, rr.Code, response)
+		}
+	}
+}
+
 func TestImageCardsHandler_UpdateCard(t *testing.T) {
 	mockStorage := storage.NewMockStorage()
 	logger := testLogger()
@@ -202,6 +237,39 @@ func TestImageCardsHandler_UpdateCard(t *testing.T) {
 	}
 }
 
+func TestImageCardsHandler_PatchCard(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewImageCardsHandler(mockStorage, testLogger())
+
+	card, err := mockStorage.CreateImageCard(context.Background(), models.ImageCard{
+		ID: uuid.New(), Name: "Sunset", Description: "Over the bay", FrontImageURL: "https://example.com/sunset.png",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+
+	rr := serve(t, handler, "PATCH", "/image-cards/"+card.ID.String(),
+		`{"description": null, "back_image_url": "https://example.com/back.png"}`, "Content-Type", patch.MediaTypeMergePatch)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var patched models.ImageCard
+	if err := json.Unmarshal(rr.Body.Bytes(), &patched); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if patched.Name != "Sunset" || patched.Description != "" || patched.FrontImageURL != card.FrontImageURL ||
+		patched.BackImageURL != "https://example.com/back.png" {
+		t.Errorf("Expected description cleared and back image set, got %+v", patched)
+	}
+
+	rr = serve(t, handler, "PATCH", "/image-cards/"+card.ID.String(),
+		`[{"op": "replace", "path": "/front_image_url", "value": "sunset.png"}]`, "Content-Type", patch.MediaTypeJSONPatch)
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("invalid URL: got %v want %v", rr.Code, http.StatusUnprocessableEntity)
+	}
+}
+
 func TestImageCardsHandler_DeleteCard(t *testing.T) {
 	mockStorage := storage.NewMockStorage()
 	logger := testLogger()
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/jobs"
+)
+
+// JobsHandler serves the /jobs resource, background jobs such as goldfish
+// simulations
+type JobsHandler struct {
+	jobs   *jobs.Manager
+	logger *slog.Logger
+}
+
+// NewJobsHandler creates a new JobsHandler with the given dependencies
+func NewJobsHandler(jobs *jobs.Manager, logger *slog.Logger) *JobsHandler {
+	return &JobsHandler{
+		jobs:   jobs,
+		logger: logger,
+	}
+}
+
+func (h *JobsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/jobs"), "/")
+	if path == "" {
+		http.Error(w, "Job ID required", http.StatusBadRequest)
+		return
+	}
+	jobID, action, _ := strings.Cut(path, "/")
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		// GET /jobs/{id} - Get the status, progress and result of a job
+		h.getJob(w, r, jobID)
+	case action == "cancel" && r.Method == http.MethodPost:
+		// POST /jobs/{id}/cancel - Stop a queued or running job
+		h.cancelJob(w, r, jobID)
+	case action == "" || action == "cancel":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// getJob handles GET /jobs/{id}
+func (h *JobsHandler) getJob(w http.ResponseWriter, r *http.Request, jobID string) {
+	id, ok := parseJobID(w, jobID)
+	if !ok {
+		return
+	}
+
+	job, found := h.jobs.Get(id)
+	if !found {
+		writeJobNotFound(w)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, job)
+}
+
+// cancelJob handles POST /jobs/{id}/cancel. Canceling a finished job leaves
+// it as it was.
+func (h *JobsHandler) cancelJob(w http.ResponseWriter, r *http.Request, jobID string) {
+	id, ok := parseJobID(w, jobID)
+	if !ok {
+		return
+	}
+
+	job, found := h.jobs.Cancel(id)
+	if !found {
+		writeJobNotFound(w)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, job)
+}
+
+// parseJobID parses a job ID from the URL path. It writes an error response
+// and returns false if the ID is malformed.
+func parseJobID(w http.ResponseWriter, jobID string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(jobID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid job ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// writeJobNotFound writes the response for a job that is unknown, or was
+// dropped to make room for newer ones
+func writeJobNotFound(w http.ResponseWriter) {
+	response := ErrorResponse{
+		Error:   "not_found",
+		Message: "Job not found",
+	}
+	writeJSONResponse(w, http.StatusNotFound, response)
+}
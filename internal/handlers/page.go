@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// ListResponse is one page of a list endpoint. NextCursor is passed back as
+// ?cursor= to fetch the next page, and is omitted on the last page.
+type ListResponse[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// SearchErrorResponse reports a search query that cannot be parsed. Column
+// is the 1-based position of Token, the part of the query at fault.
+type SearchErrorResponse struct {
+	ErrorResponse
+	Column int    `json:"column"`
+	Token  string `json:"token,omitempty"`
+}
+
+// newListResponse wraps a storage page for the response body
+func newListResponse[T any](page *storage.Page[T]) ListResponse[T] {
+	data := page.Items
+	if data == nil {
+		data = []T{}
+	}
+	return ListResponse[T]{Data: data, NextCursor: page.NextCursor}
+}
+
+// writeInvalidQuery reports a malformed query parameter
+func writeInvalidQuery(w http.ResponseWriter, err error) {
+	response := ErrorResponse{
+		Error:   "invalid_query",
+		Message: err.Error(),
+	}
+	writeJSONResponse(w, http.StatusBadRequest, response)
+}
+
+// parsePageRequest reads ?limit=, ?cursor=, ?sort= and ?order= (asc or desc)
+func parsePageRequest(query url.Values) (storage.PageRequest, error) {
+	req := storage.PageRequest{
+		Cursor: query.Get("cursor"),
+		Sort:   query.Get("sort"),
+	}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > storage.MaxPageLimit {
+			return req, fmt.Errorf("limit must be between 1 and %d", storage.MaxPageLimit)
+		}
+		req.Limit = limit
+	}
+
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		req.Descending = true
+	default:
+		return req, errors.New("order must be asc or desc")
+	}
+	return req, nil
+}
+
+// parseGameCardFilter reads the game card filter parameters: ?type=,
+// ?min_cost=, ?max_cost=, ?min_offense=, ?max_offense=, ?min_defense=,
+// ?max_defense=, ?colors= and ?keywords= (comma separated, all must match),
+// ?is_resource=, ?set= and ?rarity=
+func parseGameCardFilter(query url.Values) (storage.GameCardFilter, error) {
+	filter := storage.GameCardFilter{
+		Type:   query.Get("type"),
+		Set:    query.Get("set"),
+		Rarity: query.Get("rarity"),
+	}
+	if filter.Rarity != "" && !models.IsRarity(filter.Rarity) {
+		return filter, fmt.Errorf("rarity must be one of: %s", strings.Join(models.Rarities, ", "))
+	}
+
+	for _, bound := range []struct {
+		param string
+		field **int
+	}{
+		{"min_cost", &filter.MinCost},
+		{"max_cost", &filter.MaxCost},
+		{"min_offense", &filter.MinOffense},
+		{"max_offense", &filter.MaxOffense},
+		{"min_defense", &filter.MinDefense},
+		{"max_defense", &filter.MaxDefense},
+	} {
+		raw := query.Get(bound.param)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return filter, fmt.Errorf("%s must be an integer", bound.param)
+		}
+		*bound.field = &n
+	}
+
+	filter.Colors = splitList(query.Get("colors"))
+	filter.Keywords = splitList(query.Get("keywords"))
+
+	if raw := query.Get("is_resource"); raw != "" {
+		isResource, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, errors.New("is_resource must be true or false")
+		}
+		filter.IsResource = &isResource
+	}
+
+	if raw := query.Get("game_id"); raw != "" {
+		gameID, err := uuid.Parse(raw)
+		if err != nil {
+			return filter, errors.New("game_id must be a UUID")
+		}
+		filter.GameID = &gameID
+	}
+	return filter, nil
+}
+
+// splitList splits a comma separated parameter, dropping empty entries
+func splitList(raw string) []string {
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
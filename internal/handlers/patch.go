@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/patch"
+)
+
+// applyPatch applies the PATCH request body to current and decodes the
+// result into patched. The body is a JSON merge patch or a JSON Patch, as
+// named by its Content-Type. It writes an error response and returns false
+// if the patch cannot be applied or the result is not a valid what.
+func applyPatch(w http.ResponseWriter, r *http.Request, current, patched any, what string) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	apply := map[string]func(doc, body []byte) ([]byte, error){
+		patch.MediaTypeMergePatch: patch.Merge,
+		patch.MediaTypeJSONPatch:  patch.Apply,
+	}[mediaType]
+	if apply == nil {
+		response := ErrorResponse{
+			Error:   "unsupported_media_type",
+			Message: "PATCH requires Content-Type " + patch.MediaTypeMergePatch + " or " + patch.MediaTypeJSONPatch,
+		}
+		w.Header().Set("Accept-Patch", patch.MediaTypeMergePatch+", "+patch.MediaTypeJSONPatch)
+		writeJSONResponse(w, http.StatusUnsupportedMediaType, response)
+		return false
+	}
+
+	if r.Body == nil {
+		writeDecodeError(w, io.EOF)
+		return false
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeDecodeError(w, err)
+		return false
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		writeDecodeError(w, io.EOF)
+		return false
+	}
+
+	doc, err := json.Marshal(current)
+	if err != nil {
+		writeJSONResponse(w, http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to apply patch",
+		})
+		return false
+	}
+	result, err := apply(doc, body)
+	switch {
+	case errors.Is(err, patch.ErrConflict):
+		response := ErrorResponse{
+			Error:   "patch_conflict",
+			Message: "Patch does not apply: " + err.Error(),
+		}
+		writeJSONResponse(w, http.StatusConflict, response)
+		return false
+	case err != nil:
+		response := ErrorResponse{
+			Error:   "invalid_patch",
+			Message: "Invalid patch: " + err.Error(),
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(result))
+	decoder.DisallowUnknownFields()
+	if !bytes.HasPrefix(result, []byte("{")) {
+		err = errors.New("must be a JSON object")
+	} else {
+		err = decoder.Decode(patched)
+	}
+	if err != nil {
+		response := ValidationErrorResponse{
+			ErrorResponse: ErrorResponse{
+				Error:   "validation_failed",
+				Message: "Patch leaves an invalid " + what,
+			},
+			Fields: []models.FieldError{patchedFieldError(err)},
+		}
+		writeJSONResponse(w, http.StatusUnprocessableEntity, response)
+		return false
+	}
+	return true
+}
+
+// patchedFieldError describes why a patched document could not be decoded
+func patchedFieldError(err error) models.FieldError {
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return models.FieldError{Field: typeErr.Field, Message: "must be a JSON " + jsonTypeName(typeErr)}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json reports unknown fields with an unexported error type
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return models.FieldError{Field: field, Message: "is not a known field"}
+	default:
+		return models.FieldError{Message: err.Error()}
+	}
+}
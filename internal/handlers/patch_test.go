@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/patch"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+func TestApplyPatch_Errors(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewGameCardsHandler(mockStorage, testLogger())
+	card, err := mockStorage.CreateGameCard(context.Background(), models.GameCard{
+		ID: uuid.New(), Name: "Goblin", Keywords: []string{"Haste"}, Colors: []string{}, Printings: []models.Printing{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test card: %v", err)
+	}
+	path := "/game-cards/" + card.ID.String()
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantError   string
+	}{
+		{"plain json", path, "application/json", `{"name": "Orc"}`, http.StatusUnsupportedMediaType, "unsupported_media_type"},
+		{"empty body", path, patch.MediaTypeMergePatch, "", http.StatusBadRequest, "invalid_json"},
+		{"malformed merge patch", path, patch.MediaTypeMergePatch, `{"name": `, http.StatusBadRequest, "invalid_patch"},
+		{"malformed json patch", path, patch.MediaTypeJSONPatch, `[{"op": "grow", "path": "/cost"}]`, http.StatusBadRequest, "invalid_patch"},
+		{"missing path", path, patch.MediaTypeJSONPatch, `[{"op": "remove", "path": "/keywords/3"}]`, http.StatusConflict, "patch_conflict"},
+		{"failed test", path, patch.MediaTypeJSONPatch,
+			`[{"op": "test", "path": "/name", "value": "Orc"}, {"op": "replace", "path": "/name", "value": "Troll"}]`,
+			http.StatusConflict, "patch_conflict"},
+		{"wrong type", path, patch.MediaTypeMergePatch, `{"cost": "three"}`, http.StatusUnprocessableEntity, "validation_failed"},
+		{"unknown field", path, patch.MediaTypeJSONPatch, `[{"op": "add", "path": "/power", "value": 3}]`,
+			http.StatusUnprocessableEntity, "validation_failed"},
+		{"not an object", path, patch.MediaTypeMergePatch, `["Goblin"]`, http.StatusUnprocessableEntity, "validation_failed"},
+		{"invalid card", path, patch.MediaTypeMergePatch, `{"name": ""}`, http.StatusUnprocessableEntity, "validation_failed"},
+		{"missing card", "/game-cards/" + uuid.New().String(), patch.MediaTypeMergePatch, `{"name": "Orc"}`,
+			http.StatusNotFound, "not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serve(t, handler, "PATCH", tt.path, tt.body, "Content-Type", tt.contentType)
+			var response ErrorResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Could not parse response body: %v", err)
+			}
+			if rr.Code != tt.wantStatus || response.Error != tt.wantError {
+				t.Errorf("Expected %v %s, got %v %+v", tt.wantStatus, tt.wantError, rr.Code, response)
+			}
+		})
+	}
+
+	// Failed patches leave the card as it was
+	stored, err := mockStorage.GetGameCard(context.Background(), card.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Name != "Goblin" || stored.Cost != 0 || len(stored.Keywords) != 1 {
+		t.Errorf("Expected the card to be unchanged, got %+v", stored)
+	}
+}
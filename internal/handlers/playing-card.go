@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// Handler struct with storage dependency
+type PlayingCardsHandler struct {
+	storage storage.Storage
+	logger  *slog.Logger
+}
+
+// NewPlayingCardsHandler creates a new PlayingCardsHandler with the given dependencies
+func NewPlayingCardsHandler(storage storage.Storage, logger *slog.Logger) *PlayingCardsHandler {
+	return &PlayingCardsHandler{
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+func (h *PlayingCardsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/playing-cards")
+
+	switch r.Method {
+	case http.MethodGet:
+		if path == "" || path == "/" {
+			// GET /playing-cards - List all cards
+			h.listCards(w, r)
+		} else {
+			// GET /playing-cards/{id} - Get specific card
+			cardID := strings.Trim(path, "/")
+			h.getCard(w, r, cardID)
+		}
+
+	case http.MethodPost:
+		if path == "" || path == "/" {
+			// POST /playing-cards - Create new card
+			h.createCard(w, r)
+		} else {
+			http.Error(w, "Method not allowed for this path", http.StatusMethodNotAllowed)
+		}
+
+	case http.MethodPut:
+		if path != "" && path != "/" {
+			// PUT /playing-cards/{id} - Update card
+			cardID := strings.Trim(path, "/")
+			h.updateCard(w, r, cardID)
+		} else {
+			http.Error(w, "Card ID required for update", http.StatusBadRequest)
+		}
+
+	case http.MethodDelete:
+		if path != "" && path != "/" {
+			// DELETE /playing-cards/{id} - Delete card
+			cardID := strings.Trim(path, "/")
+			h.deleteCard(w, r, cardID)
+		} else {
+			http.Error(w, "Card ID required for deletion", http.StatusBadRequest)
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listCards handles GET /playing-cards
+func (h *PlayingCardsHandler) listCards(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cards, err := h.storage.ListPlayingCards(ctx)
+	if err != nil {
+		h.logger.Error("Failed to list playing cards",
+			slog.String("operation", "list_playing_cards"),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card not found", "Failed to retrieve playing cards")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, cards)
+}
+
+// getCard handles GET /playing-cards/{id}
+func (h *PlayingCardsHandler) getCard(w http.ResponseWriter, r *http.Request, cardID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(cardID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid card ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	ctx := r.Context()
+	card, err := h.storage.GetPlayingCard(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to get playing card",
+			slog.String("operation", "get_playing_card"),
+			slog.String("card_id", cardID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card not found", "Failed to retrieve playing card")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, card)
+}
+
+// createCard handles POST /playing-cards
+func (h *PlayingCardsHandler) createCard(w http.ResponseWriter, r *http.Request) {
+	var card models.PlayingCard
+	if !decodeJSON(w, r, &card) || !validateModel(w, &card, "playing card") {
+		return
+	}
+
+	ctx := r.Context()
+	createdCard, err := h.storage.CreatePlayingCard(ctx, card)
+	if err != nil {
+		h.logger.Error("Failed to create playing card",
+			slog.String("operation", "create_playing_card"),
+			slog.String("card_name", card.GetName()),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card not found", "Failed to create playing card")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, createdCard)
+}
+
+// updateCard handles PUT /playing-cards/{id}
+func (h *PlayingCardsHandler) updateCard(w http.ResponseWriter, r *http.Request, cardID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(cardID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid card ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	var card models.PlayingCard
+	if !decodeJSON(w, r, &card) || !validateModel(w, &card, "playing card") {
+		return
+	}
+
+	ctx := r.Context()
+	// Set the ID from the URL path
+	card.ID = id
+	updatedCard, err := h.storage.UpdatePlayingCard(ctx, card)
+	if err != nil {
+		h.logger.Error("Failed to update playing card",
+			slog.String("operation", "update_playing_card"),
+			slog.String("card_id", cardID),
+			slog.String("card_name", card.GetName()),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card not found", "Failed to update playing card")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, updatedCard)
+}
+
+// deleteCard handles DELETE /playing-cards/{id}
+func (h *PlayingCardsHandler) deleteCard(w http.ResponseWriter, r *http.Request, cardID string) {
+	// Validate UUID format
+	id, err := uuid.Parse(cardID)
+	if err != nil {
+		response := ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid card ID format",
+		}
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.storage.DeletePlayingCard(ctx, id); err != nil {
+		h.logger.Error("Failed to delete playing card",
+			slog.String("operation", "delete_playing_card"),
+			slog.String("card_id", cardID),
+			slog.Any("error", err))
+		writeStorageError(w, err, "Card not found", "Failed to delete playing card")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+func TestPlayingCardsHandler_CreateAndGetCard(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	logger := testLogger()
+	handler := NewPlayingCardsHandler(mockStorage, logger)
+
+	card := models.PlayingCard{
+		Suite: models.SuiteSpades,
+		Value: 1,
+	}
+
+	jsonData, err := json.Marshal(card)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := http.NewRequest("POST", "/playing-cards", bytes.NewReader(jsonData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v",
+			status, http.StatusCreated)
+	}
+
+	var createdCard models.PlayingCard
+	if err := json.Unmarshal(rr.Body.Bytes(), &createdCard); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if createdCard.ID == uuid.Nil {
+		t.Fatal("Expected created card to have an ID")
+	}
+	if createdCard.GetName() != "Ace of Spades" {
+		t.Errorf("Expected card name 'Ace of Spades', got '%s'", createdCard.GetName())
+	}
+
+	req, err = http.NewRequest("GET", "/playing-cards/"+createdCard.ID.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+}
+
+func TestPlayingCardsHandler_CreateCard_ValidationFailed(t *testing.T) {
+	handler := NewPlayingCardsHandler(storage.NewMockStorage(), testLogger())
+
+	tests := []struct {
+		body        string
+		wantField   string
+		wantMessage string
+	}{
+		{`{"suite": "Stars", "value": 1}`, "suite", "must be one of: Hearts, Diamonds, Clubs, Spades, Joker"},
+		{`{"suite": "Hearts", "value": 14}`, "value", "must be between 1 and 13"},
+		{`{"suite": "Hearts"}`, "value", "must be between 1 and 13"},
+		{`{"suite": "Joker", "value": 3}`, "value", "must be 0 for jokers"},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest("POST", "/playing-cards", bytes.NewBufferString(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		var response ValidationErrorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+		want := []models.FieldError{{Field: tt.wantField, Message: tt.wantMessage}}
+		if rr.Code != http.StatusUnprocessableEntity || !slices.Equal(response.Fields, want) {
+			t.Errorf("%s: expected 422 %+v, got %v %+v", tt.body, want, rr.Code, response)
+		}
+	}
+}
+
+func TestPlayingCardsHandler_GetCard_NotFound(t *testing.T) {
+	handler := NewPlayingCardsHandler(storage.NewMockStorage(), testLogger())
+
+	req, err := http.NewRequest("GET", "/playing-cards/"+uuid.New().String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusNotFound)
+	}
+}
+
+func TestPlayingCardsHandler_UpdateAndDeleteCard(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	handler := NewPlayingCardsHandler(mockStorage, testLogger())
+
+	createdCard, err := mockStorage.CreatePlayingCard(context.Background(), models.PlayingCard{Suite: models.SuiteHearts, Value: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jsonData, _ := json.Marshal(models.PlayingCard{Suite: models.SuiteHearts, Value: 12})
+	req, err := http.NewRequest("PUT", "/playing-cards/"+createdCard.ID.String(), bytes.NewReader(jsonData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+	var updatedCard models.PlayingCard
+	if err := json.Unmarshal(rr.Body.Bytes(), &updatedCard); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if updatedCard.GetName() != "Queen of Hearts" {
+		t.Errorf("Expected card name 'Queen of Hearts', got '%s'", updatedCard.GetName())
+	}
+
+	req, err = http.NewRequest("DELETE", "/playing-cards/"+createdCard.ID.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNoContent {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusNoContent)
+	}
+	if _, err := mockStorage.GetPlayingCard(context.Background(), createdCard.ID); err == nil {
+		t.Error("Expected card to be deleted, but it still exists")
+	}
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// RegistryHandler serves one registry of canonical card attribute values,
+// /keywords or /colors
+type RegistryHandler struct {
+	storage  storage.Storage
+	registry string
+	logger   *slog.Logger
+}
+
+// NewRegistryHandler creates a new RegistryHandler serving registry, one of
+// models.Registries, at /{registry}
+func NewRegistryHandler(storage storage.Storage, registry string, logger *slog.Logger) *RegistryHandler {
+	return &RegistryHandler{
+		storage:  storage,
+		registry: registry,
+		logger:   logger,
+	}
+}
+
+func (h *RegistryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/"+h.registry), "/")
+
+	switch r.Method {
+	case http.MethodGet:
+		if name == "" {
+			// GET /{registry} - List all entries
+			h.listEntries(w, r)
+		} else {
+			// GET /{registry}/{name} - Get specific entry
+			h.getEntry(w, r, name)
+		}
+
+	case http.MethodPost:
+		if name == "" {
+			// POST /{registry} - Create new entry
+			h.createEntry(w, r)
+		} else {
+			http.Error(w, "Method not allowed for this path", http.StatusMethodNotAllowed)
+		}
+
+	case http.MethodPut:
+		if name != "" {
+			// PUT /{registry}/{name} - Update entry
+			h.updateEntry(w, r, name)
+		} else {
+			http.Error(w, "Name required for update", http.StatusBadRequest)
+		}
+
+	case http.MethodDelete:
+		if name != "" {
+			// DELETE /{registry}/{name} - Delete entry
+			h.deleteEntry(w, r, name)
+		} else {
+			http.Error(w, "Name required for deletion", http.StatusBadRequest)
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// noun returns the singular name of an entry, e.g. "keyword"
+func (h *RegistryHandler) noun() string {
+	return strings.TrimSuffix(h.registry, "s")
+}
+
+// notFound returns the message for a missing entry, e.g. "Keyword not found"
+func (h *RegistryHandler) notFound() string {
+	noun := h.noun()
+	return strings.ToUpper(noun[:1]) + noun[1:] + " not found"
+}
+
+// listEntries handles GET /{registry}
+func (h *RegistryHandler) listEntries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	entries, err := h.storage.ListRegistryEntries(ctx, h.registry)
+	if err != nil {
+		h.logger.Error("Failed to list registry entries",
+			slog.String("operation", "list_registry_entries"),
+			slog.String("registry", h.registry),
+			slog.Any("error", err))
+		writeStorageError(w, err, h.notFound(), "Failed to retrieve "+h.registry)
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, ListResponse[*models.RegistryEntry]{Data: entries})
+}
+
+// getEntry handles GET /{registry}/{name}
+func (h *RegistryHandler) getEntry(w http.ResponseWriter, r *http.Request, name string) {
+	ctx := r.Context()
+	entry, err := h.storage.GetRegistryEntry(ctx, h.registry, name)
+	if err != nil {
+		h.logger.Error("Failed to get registry entry",
+			slog.String("operation", "get_registry_entry"),
+			slog.String("registry", h.registry),
+			slog.String("name", name),
+			slog.Any("error", err))
+		writeStorageError(w, err, h.notFound(), "Failed to retrieve "+h.noun())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, entry)
+}
+
+// createEntry handles POST /{registry}
+func (h *RegistryHandler) createEntry(w http.ResponseWriter, r *http.Request) {
+	var entry models.RegistryEntry
+	if !decodeJSON(w, r, &entry) {
+		return
+	}
+	if !h.validateEntry(w, r, &entry) {
+		return
+	}
+
+	ctx := r.Context()
+	createdEntry, err := h.storage.CreateRegistryEntry(ctx, h.registry, entry)
+	if err != nil {
+		h.logger.Error("Failed to create registry entry",
+			slog.String("operation", "create_registry_entry"),
+			slog.String("registry", h.registry),
+			slog.String("name", entry.Name),
+			slog.Any("error", err))
+		writeStorageError(w, err, h.notFound(), "Failed to create "+h.noun())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, createdEntry)
+}
+
+// updateEntry handles PUT /{registry}/{name}. Cards already saved with an
+// alias that is removed keep their canonical value.
+func (h *RegistryHandler) updateEntry(w http.ResponseWriter, r *http.Request, name string) {
+	var entry models.RegistryEntry
+	if !decodeJSON(w, r, &entry) {
+		return
+	}
+
+	// Set the name from the URL path
+	entry.Name = name
+	if !h.validateEntry(w, r, &entry) {
+		return
+	}
+
+	ctx := r.Context()
+	updatedEntry, err := h.storage.UpdateRegistryEntry(ctx, h.registry, entry)
+	if err != nil {
+		h.logger.Error("Failed to update registry entry",
+			slog.String("operation", "update_registry_entry"),
+			slog.String("registry", h.registry),
+			slog.String("name", name),
+			slog.Any("error", err))
+		writeStorageError(w, err, h.notFound(), "Failed to update "+h.noun())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, updatedEntry)
+}
+
+// deleteEntry handles DELETE /{registry}/{name}. Cards keep the value, which
+// is then rejected the next time they are written unless the registry is
+// empty.
+func (h *RegistryHandler) deleteEntry(w http.ResponseWriter, r *http.Request, name string) {
+	ctx := r.Context()
+	if err := h.storage.DeleteRegistryEntry(ctx, h.registry, name); err != nil {
+		h.logger.Error("Failed to delete registry entry",
+			slog.String("operation", "delete_registry_entry"),
+			slog.String("registry", h.registry),
+			slog.String("name", name),
+			slog.Any("error", err))
+		writeStorageError(w, err, h.notFound(), "Failed to delete "+h.noun())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateEntry checks the fields of an entry, and that its name and aliases
+// do not resolve to another entry. It writes an error response and returns
+// false if the entry is invalid.
+func (h *RegistryHandler) validateEntry(w http.ResponseWriter, r *http.Request, entry *models.RegistryEntry) bool {
+	if entry.Aliases == nil {
+		entry.Aliases = []string{}
+	}
+
+	entry.Name = strings.TrimSpace(entry.Name)
+	for i, alias := range entry.Aliases {
+		entry.Aliases[i] = strings.TrimSpace(alias)
+	}
+	if !validateModel(w, entry, h.noun()) {
+		return false
+	}
+
+	ctx := r.Context()
+	entries, err := h.storage.ListRegistryEntries(ctx, h.registry)
+	if err != nil {
+		h.logger.Error("Failed to list registry entries",
+			slog.String("operation", "validate_registry_entry"),
+			slog.String("registry", h.registry),
+			slog.Any("error", err))
+		writeStorageError(w, err, h.notFound(), "Failed to validate "+h.noun())
+		return false
+	}
+	others := make([]*models.RegistryEntry, 0, len(entries))
+	for _, other := range entries {
+		// An exact name match is the entry being updated, or a duplicate
+		// create that storage reports
+		if other.Name != entry.Name {
+			others = append(others, other)
+		}
+	}
+	resolver := models.NewResolver(others)
+	for _, value := range append([]string{entry.Name}, entry.Aliases...) {
+		if other, ok := resolver.Resolve(value); ok {
+			response := ErrorResponse{
+				Error:   "conflict",
+				Message: fmt.Sprintf("%q already names the %s %q", value, h.noun(), other.Name),
+			}
+			writeJSONResponse(w, http.StatusConflict, response)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+func TestRegistryHandler_CRUD(t *testing.T) {
+	handler := NewRegistryHandler(storage.NewMockStorage(), models.RegistryKeywords, testLogger())
+
+	rr := serve(t, handler, "POST", "/keywords",
+		`{"name": "First Strike", "aliases": ["first-strike"], "reminder_text": "Deals combat damage first."}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create: got %v want %v: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+
+	rr = serve(t, handler, "PUT", "/keywords/First Strike",
+		`{"aliases": ["first-strike", "fs"], "icon_url": "https://example.com/fs.svg"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("update: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	rr = serve(t, handler, "GET", "/keywords/First Strike", "")
+	var entry models.RegistryEntry
+	if err := json.Unmarshal(rr.Body.Bytes(), &entry); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if rr.Code != http.StatusOK || len(entry.Aliases) != 2 || entry.ReminderText != "" || entry.IconURL == "" {
+		t.Errorf("get: expected the updated keyword, got %v %+v", rr.Code, entry)
+	}
+
+	rr = serve(t, handler, "GET", "/keywords", "")
+	var list ListResponse[models.RegistryEntry]
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].Name != "First Strike" {
+		t.Errorf("list: expected the one keyword, got %+v", list.Data)
+	}
+
+	rr = serve(t, handler, "DELETE", "/keywords/First Strike", "")
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("delete: got %v want %v", rr.Code, http.StatusNoContent)
+	}
+	rr = serve(t, handler, "GET", "/keywords/First Strike", "")
+	var response ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Could not parse response body: %v", err)
+	}
+	if rr.Code != http.StatusNotFound || response.Message != "Keyword not found" {
+		t.Errorf("get deleted: expected 404 Keyword not found, got %v %+v", rr.Code, response)
+	}
+}
+
+func TestRegistryHandler_InvalidEntry(t *testing.T) {
+	handler := NewRegistryHandler(storage.NewMockStorage(), models.RegistryColors, testLogger())
+	if rr := serve(t, handler, "POST", "/colors", `{"name": "Red", "aliases": ["R"]}`); rr.Code != http.StatusCreated {
+		t.Fatalf("create: got %v want %v", rr.Code, http.StatusCreated)
+	}
+
+	tests := []struct {
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{`{"name": " "}`, http.StatusUnprocessableEntity, "validation_failed"},
+		{`{"name": "Red/Blue"}`, http.StatusUnprocessableEntity, "validation_failed"},
+		{`{"name": "` + strings.Repeat("B", models.MaxTagLength+1) + `"}`, http.StatusUnprocessableEntity, "validation_failed"},
+		{`{"name": "Blue", "aliases": ["U", "u"]}`, http.StatusUnprocessableEntity, "validation_failed"},
+		{`{"name": "Blue", "aliases": [""]}`, http.StatusUnprocessableEntity, "validation_failed"},
+		{`{"name": "Blue", "aliases": ["blue"]}`, http.StatusUnprocessableEntity, "validation_failed"},
+		{`{"name": "Blue", "icon_url": "blue.svg"}`, http.StatusUnprocessableEntity, "validation_failed"},
+		{`{"name": "Blue", "icon_url": "https://example.com/` + strings.Repeat("b", models.MaxURLLength) + `"}`,
+			http.StatusUnprocessableEntity, "validation_failed"},
+		{`{"name": "Red"}`, http.StatusConflict, "conflict"},
+		{`{"name": "red"}`, http.StatusConflict, "conflict"},
+		{`{"name": "Rojo", "aliases": ["r"]}`, http.StatusConflict, "conflict"},
+		{`{"name": "R"}`, http.StatusConflict, "conflict"},
+	}
+	for _, tt := range tests {
+		rr := serve(t, handler, "POST", "/colors", tt.body)
+		var response ErrorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Could not parse response body: %v", err)
+		}
+		if rr.Code != tt.wantStatus || response.Error != tt.wantError {
+			t.Errorf("%s: expected %v %s, got %v %+v", tt.body, tt.wantStatus, tt.wantError, rr.Code, response)
+		}
+	}
+
+	// An update may keep its own name and aliases
+	if rr := serve(t, handler, "PUT", "/colors/Red", `{"aliases": ["R", "Rd"]}`); rr.Code != http.StatusOK {
+		t.Errorf("update: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+}
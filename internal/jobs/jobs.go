@@ -0,0 +1,225 @@
+// Package jobs runs long requests in the background. A job is started with a
+// function that reports its progress, and clients poll it by ID until it
+// finishes.
+//
+// Jobs are held in memory by the process that started them, so they do not
+// survive a restart and are not shared between replicas.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job statuses
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCanceled  = "canceled"
+)
+
+// Defaults for NewManager
+const (
+	DefaultWorkers = 2
+	DefaultRetain  = 100
+)
+
+// ErrTooManyJobs is returned by Start when every retained job is unfinished
+var ErrTooManyJobs = errors.New("too many unfinished jobs")
+
+// Func does the work of a job. It reports the units of work done so far
+// with progress, and should return early with ctx.Err() once ctx is done.
+type Func func(ctx context.Context, progress func(done int)) (any, error)
+
+// Job is a snapshot of a background job
+type Job struct {
+	ID     uuid.UUID `json:"id"`
+	Kind   string    `json:"kind"`
+	Status string    `json:"status"`
+	// Done counts the units of work finished out of Total
+	Done       int        `json:"done"`
+	Total      int        `json:"total"`
+	Result     any        `json:"result,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Finished reports whether the job has stopped, for better or worse
+func (j *Job) Finished() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed || j.Status == StatusCanceled
+}
+
+// entry is a job and the means to cancel it
+type entry struct {
+	job    Job
+	cancel context.CancelFunc
+}
+
+// Manager runs jobs on a fixed number of workers and keeps the most recent
+// ones for clients to poll
+type Manager struct {
+	mu     sync.Mutex
+	jobs   map[uuid.UUID]*entry
+	order  []uuid.UUID // oldest first
+	retain int
+	slots  chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewManager creates a Manager that runs up to workers jobs at once and
+// retains up to retain jobs, dropping the oldest finished ones first. Zero
+// selects DefaultWorkers and DefaultRetain.
+func NewManager(workers, retain int) *Manager {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if retain <= 0 {
+		retain = DefaultRetain
+	}
+	return &Manager{
+		jobs:   make(map[uuid.UUID]*entry),
+		retain: retain,
+		slots:  make(chan struct{}, workers),
+	}
+}
+
+// Start queues fn as a job of the given kind and total units of work and
+// returns it. The job runs once a worker is free.
+func (m *Manager) Start(kind string, total int, fn Func) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.order) >= m.retain && !m.evict() {
+		return Job{}, ErrTooManyJobs
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &entry{
+		job: Job{
+			ID:        uuid.New(),
+			Kind:      kind,
+			Status:    StatusQueued,
+			Total:     total,
+			CreatedAt: time.Now().UTC(),
+		},
+		cancel: cancel,
+	}
+	m.jobs[e.job.ID] = e
+	m.order = append(m.order, e.job.ID)
+
+	m.wg.Add(1)
+	go m.run(ctx, e, fn)
+	return e.job, nil
+}
+
+// evict drops the oldest finished job, reporting false if there is none.
+// The caller holds m.mu.
+func (m *Manager) evict() bool {
+	for i, id := range m.order {
+		if m.jobs[id].job.Finished() {
+			delete(m.jobs, id)
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// run waits for a worker and runs the job on it
+func (m *Manager) run(ctx context.Context, e *entry, fn Func) {
+	defer m.wg.Done()
+	defer e.cancel()
+
+	select {
+	case m.slots <- struct{}{}:
+		defer func() { <-m.slots }()
+	case <-ctx.Done():
+		m.finish(e, nil, ctx.Err())
+		return
+	}
+
+	m.mu.Lock()
+	if e.job.Status != StatusQueued {
+		m.mu.Unlock()
+		return
+	}
+	now := time.Now().UTC()
+	e.job.Status = StatusRunning
+	e.job.StartedAt = &now
+	m.mu.Unlock()
+
+	result, err := fn(ctx, func(done int) {
+		m.mu.Lock()
+		e.job.Done = done
+		m.mu.Unlock()
+	})
+	m.finish(e, result, err)
+}
+
+// finish records the outcome of a job
+func (m *Manager) finish(e *entry, result any, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e.job.Finished() {
+		return
+	}
+	now := time.Now().UTC()
+	e.job.FinishedAt = &now
+	switch {
+	case errors.Is(err, context.Canceled):
+		e.job.Status = StatusCanceled
+	case err != nil:
+		e.job.Status = StatusFailed
+		e.job.Error = err.Error()
+	default:
+		e.job.Status = StatusSucceeded
+		e.job.Done = e.job.Total
+		e.job.Result = result
+	}
+}
+
+// Get returns the job with the given ID, if it is retained
+func (m *Manager) Get(id uuid.UUID) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return e.job, true
+}
+
+// Cancel stops a queued or running job. The job stays retained with the
+// canceled status; canceling a finished job changes nothing.
+func (m *Manager) Cancel(id uuid.UUID) (Job, bool) {
+	m.mu.Lock()
+	e, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok {
+		return Job{}, false
+	}
+
+	e.cancel()
+	m.finish(e, nil, context.Canceled)
+	return m.Get(id)
+}
+
+// Close cancels every unfinished job and waits for them to stop
+func (m *Manager) Close() {
+	m.mu.Lock()
+	for _, e := range m.jobs {
+		e.cancel()
+	}
+	m.mu.Unlock()
+	m.wg.Wait()
+}
@@ -0,0 +1,152 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// wait polls a job until it finishes
+func wait(t *testing.T, m *Manager, id uuid.UUID) Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, ok := m.Get(id)
+		if !ok {
+			t.Fatalf("job %s is not retained", id)
+		}
+		if job.Finished() {
+			return job
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return Job{}
+}
+
+func TestManager_Succeeded(t *testing.T) {
+	m := NewManager(1, 10)
+	defer m.Close()
+
+	release := make(chan struct{})
+	job, err := m.Start("count", 3, func(ctx context.Context, progress func(done int)) (any, error) {
+		progress(1)
+		<-release
+		progress(2)
+		return "done", nil
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if job.Kind != "count" || job.Total != 3 || job.Finished() {
+		t.Errorf("Unexpected new job %+v", job)
+	}
+
+	// Progress is visible while the job runs
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		running, _ := m.Get(job.ID)
+		if running.Status == StatusRunning && running.Done == 1 && running.StartedAt != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the job to report progress, got %+v", running)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	finished := wait(t, m, job.ID)
+	if finished.Status != StatusSucceeded || finished.Result != "done" || finished.Done != 3 || finished.FinishedAt == nil {
+		t.Errorf("Unexpected finished job %+v", finished)
+	}
+}
+
+func TestManager_Failed(t *testing.T) {
+	m := NewManager(1, 10)
+	defer m.Close()
+
+	job, err := m.Start("fail", 1, func(ctx context.Context, progress func(done int)) (any, error) {
+		return nil, errors.New("boom")
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if finished := wait(t, m, job.ID); finished.Status != StatusFailed || finished.Error != "boom" {
+		t.Errorf("Unexpected failed job %+v", finished)
+	}
+}
+
+func TestManager_Cancel(t *testing.T) {
+	m := NewManager(1, 10)
+	defer m.Close()
+
+	block := func(ctx context.Context, progress func(done int)) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	running, err := m.Start("block", 1, block)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	// With one worker the second job waits in the queue
+	queued, err := m.Start("block", 1, block)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if job, _ := m.Get(queued.ID); job.Status != StatusQueued {
+		t.Errorf("Expected the second job to be queued, got %+v", job)
+	}
+
+	for _, id := range []uuid.UUID{queued.ID, running.ID} {
+		job, ok := m.Cancel(id)
+		if !ok || job.Status != StatusCanceled {
+			t.Errorf("Cancel: got %+v, %v", job, ok)
+		}
+	}
+	if _, ok := m.Cancel(uuid.New()); ok {
+		t.Error("Expected canceling an unknown job to fail")
+	}
+}
+
+func TestManager_Retain(t *testing.T) {
+	m := NewManager(1, 2)
+	defer m.Close()
+
+	quick := func(ctx context.Context, progress func(done int)) (any, error) { return nil, nil }
+	first, _ := m.Start("quick", 1, quick)
+	wait(t, m, first.ID)
+	second, _ := m.Start("quick", 1, quick)
+	wait(t, m, second.ID)
+
+	// The oldest finished job makes way for a new one
+	third, err := m.Start("quick", 1, quick)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	wait(t, m, third.ID)
+	if _, ok := m.Get(first.ID); ok {
+		t.Error("Expected the oldest job to be dropped")
+	}
+	if _, ok := m.Get(second.ID); !ok {
+		t.Error("Expected the second job to be retained")
+	}
+
+	// Unfinished jobs are never dropped
+	block := func(ctx context.Context, progress func(done int)) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if _, err := m.Start("block", 1, block); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := m.Start("block", 1, block); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := m.Start("block", 1, block); !errors.Is(err, ErrTooManyJobs) {
+		t.Errorf("Expected ErrTooManyJobs, got %v", err)
+	}
+}
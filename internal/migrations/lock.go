@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// mysqlLockName names the MySQL lock held while migrating. Named locks are
+// server wide, so migrations of other databases on the server wait too.
+const mysqlLockName = "tcg_api.schema_migrations"
+
+// mysqlLockTimeout is how long to wait for another process to finish
+// migrating
+const mysqlLockTimeout = 5 * time.Minute
+
+// ErrLockTimeout is returned by Up and Down when another process held the
+// migration lock for too long
+var ErrLockTimeout = errors.New("timed out waiting for the migration lock")
+
+// lockMySQL takes the MySQL named lock for migrations. The lock belongs to a
+// session, so it is held on a connection of its own until unlock closes it.
+func lockMySQL(ctx context.Context, db *sql.DB) (func() error, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// GET_LOCK returns 1 once the lock is taken, 0 on timeout and NULL on
+	// error
+	var locked sql.NullInt64
+	err = conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, ?)`,
+		mysqlLockName, int(mysqlLockTimeout/time.Second)).Scan(&locked)
+	if err == nil && locked.Int64 != 1 {
+		err = fmt.Errorf("%w %q", ErrLockTimeout, mysqlLockName)
+	}
+	if err != nil {
+		return nil, errors.Join(err, conn.Close())
+	}
+
+	return func() error {
+		_, err := conn.ExecContext(context.Background(), `SELECT RELEASE_LOCK(?)`, mysqlLockName)
+		return errors.Join(err, conn.Close())
+	}, nil
+}
@@ -0,0 +1,334 @@
+// Package migrations applies versioned schema changes to SQL storage backends.
+//
+// Migrations are embedded SQL files named {version}_{name}.up.sql and
+// {version}_{name}.down.sql, one directory per storage driver. Applied
+// versions are recorded in the schema_migrations table.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jwebster45206/tcg-api/internal/config"
+)
+
+//go:embed mysql/*.sql sqlite/*.sql
+var embedded embed.FS
+
+// ErrUnknownDriver is returned by ForDriver for a driver without migrations
+var ErrUnknownDriver = errors.New("no migrations for storage driver")
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// ForDriver returns the embedded migrations for a storage driver, ordered by
+// version
+func ForDriver(driver string) ([]Migration, error) {
+	if _, err := fs.Stat(embedded, driver); err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDriver, driver)
+	}
+	fsys, err := fs.Sub(embedded, driver)
+	if err != nil {
+		return nil, err
+	}
+	return Load(fsys)
+}
+
+// Load reads migrations from the top level of fsys, ordered by version. Every
+// version must have both an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+
+		contents, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(contents)
+		} else {
+			m.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Migrator applies and rolls back migrations against a database
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+
+	// lock serializes Up and Down across processes sharing the database,
+	// returning a function that releases it. It is nil when the database
+	// needs no lock of its own.
+	lock func(ctx context.Context, db *sql.DB) (unlock func() error, err error)
+}
+
+// New creates a Migrator for the given migrations, which must be ordered by
+// version
+func New(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}
+}
+
+// NewForDriver creates a Migrator for the embedded migrations of a storage
+// driver. On MySQL, Up and Down hold a named lock while they run, so servers
+// that migrate on start together apply each migration once. SQLite needs no
+// lock, since it allows a single writer.
+func NewForDriver(db *sql.DB, driver string) (*Migrator, error) {
+	driverMigrations, err := ForDriver(driver)
+	if err != nil {
+		return nil, err
+	}
+	m := New(db, driverMigrations)
+	if driver == config.StorageDriverMySQL {
+		m.lock = lockMySQL
+	}
+	return m, nil
+}
+
+// withLock runs fn while holding the migration lock, if the database has one
+func (m *Migrator) withLock(ctx context.Context, fn func() error) (err error) {
+	if m.lock == nil {
+		return fn()
+	}
+	unlock, err := m.lock(ctx, m.db)
+	if err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		if unlockErr := unlock(); unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("release migration lock: %w", unlockErr))
+		}
+	}()
+	return fn()
+}
+
+// Up applies every pending migration in version order and returns the ones
+// it applied. Applied versions are read once the migration lock is held, so
+// migrations another process applied meanwhile are skipped.
+func (m *Migrator) Up(ctx context.Context) (done []Migration, err error) {
+	err = m.withLock(ctx, func() error {
+		done, err = m.up(ctx)
+		return err
+	})
+	return done, err
+}
+
+// up applies pending migrations while the migration lock is held
+func (m *Migrator) up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.run(ctx, migration.Up, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx,
+				`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+				migration.Version, migration.Name, time.Now().UTC().Truncate(time.Second))
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down rolls back the most recently applied migration and returns it, or nil
+// if no migrations are applied
+func (m *Migrator) Down(ctx context.Context) (rolledBack *Migration, err error) {
+	err = m.withLock(ctx, func() error {
+		rolledBack, err = m.down(ctx)
+		return err
+	})
+	return rolledBack, err
+}
+
+// down rolls back the latest migration while the migration lock is held
+func (m *Migrator) down(ctx context.Context) (*Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := -1
+	for version := range applied {
+		if version > latest {
+			latest = version
+		}
+	}
+	if latest < 0 {
+		return nil, nil
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version != latest {
+			continue
+		}
+		err := m.run(ctx, migration.Down, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, migration.Version)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("roll back migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		return &migration, nil
+	}
+	return nil, fmt.Errorf("applied migration %d is not known to this build", latest)
+}
+
+// Status lists every known migration and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses[i] = MigrationStatus{
+			Migration: migration,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		}
+	}
+	return statuses, nil
+}
+
+// applied creates the tracking table if needed and returns the applied
+// versions with the time each was applied
+func (m *Migrator) applied(ctx context.Context) (map[int]time.Time, error) {
+	_, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT       NOT NULL PRIMARY KEY,
+		name       VARCHAR(255) NOT NULL,
+		applied_at DATETIME     NOT NULL
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt.UTC()
+	}
+	return applied, rows.Err()
+}
+
+// run executes the statements of a migration script followed by record in a
+// single transaction. Databases such as MySQL commit DDL implicitly, so a
+// failed script may still leave earlier statements applied.
+func (m *Migrator) run(ctx context.Context, script string, record func(tx *sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+	}
+	if err := record(tx); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	return tx.Commit()
+}
+
+// splitStatements splits a script into statements on semicolons, dropping
+// comment lines. Statements must not contain semicolons of their own.
+func splitStatements(script string) []string {
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			lines = append(lines, line)
+		}
+	}
+
+	var stmts []string
+	for _, stmt := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts
+}
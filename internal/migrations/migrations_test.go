@@ -0,0 +1,348 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "modernc.org/sqlite"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_sets.up.sql":        {Data: []byte("CREATE TABLE sets (id INT);")},
+		"0002_add_sets.down.sql":      {Data: []byte("DROP TABLE sets;")},
+		"0001_create_tables.up.sql":   {Data: []byte("CREATE TABLE cards (id INT);")},
+		"0001_create_tables.down.sql": {Data: []byte("DROP TABLE cards;")},
+	}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("Expected 2 migrations, got %d", len(migrations))
+	}
+	if migrations[0].Version != 1 || migrations[0].Name != "create_tables" || migrations[0].Down != "DROP TABLE cards;" {
+		t.Errorf("Unexpected first migration: %+v", migrations[0])
+	}
+	if migrations[1].Version != 2 || migrations[1].Up != "CREATE TABLE sets (id INT);" {
+		t.Errorf("Unexpected second migration: %+v", migrations[1])
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{
+			name: "missing down file",
+			fsys: fstest.MapFS{
+				"0001_create_tables.up.sql": {Data: []byte("CREATE TABLE cards (id INT);")},
+			},
+		},
+		{
+			name: "mismatched names",
+			fsys: fstest.MapFS{
+				"0001_create_tables.up.sql":  {Data: []byte("CREATE TABLE cards (id INT);")},
+				"0001_create_cards.down.sql": {Data: []byte("DROP TABLE cards;")},
+			},
+		},
+		{
+			name: "bad file name",
+			fsys: fstest.MapFS{
+				"create_tables.sql": {Data: []byte("CREATE TABLE cards (id INT);")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.fsys); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestForDriver(t *testing.T) {
+	for _, driver := range []string{"mysql", "sqlite"} {
+		migrations, err := ForDriver(driver)
+		if err != nil {
+			t.Fatalf("ForDriver(%q): %v", driver, err)
+		}
+		if len(migrations) == 0 || migrations[0].Version != 1 {
+			t.Errorf("Expected embedded %s migrations starting at version 1, got %+v", driver, migrations)
+		}
+		for _, m := range migrations {
+			if len(splitStatements(m.Up)) == 0 || len(splitStatements(m.Down)) == 0 {
+				t.Errorf("Migration %s/%d_%s has an empty script", driver, m.Version, m.Name)
+			}
+		}
+	}
+
+	if _, err := ForDriver("oracle"); !errors.Is(err, ErrUnknownDriver) {
+		t.Errorf("Expected ErrUnknownDriver, got %v", err)
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- Create the table
+CREATE TABLE cards (
+    id INT
+);
+
+-- And an index
+CREATE INDEX idx_cards_id ON cards (id);
+`
+	stmts := splitStatements(script)
+	if len(stmts) != 2 {
+		t.Fatalf("Expected 2 statements, got %d: %q", len(stmts), stmts)
+	}
+	if stmts[1] != "CREATE INDEX idx_cards_id ON cards (id)" {
+		t.Errorf("Unexpected statement: %q", stmts[1])
+	}
+}
+
+// TestMigrator_MySQL runs the embedded MySQL migrations up and down against
+// the database named by TCG_TEST_MYSQL_DSN. It drops every table it creates.
+func TestMigrator_MySQL(t *testing.T) {
+	dsn := os.Getenv("TCG_TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TCG_TEST_MYSQL_DSN not set")
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	testMigrator(t, db, "mysql")
+}
+
+func TestMigrator_SQLite(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "tcg.db")+"?_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	testMigrator(t, db, "sqlite")
+}
+
+// TestMigrator_Lock checks that Up and Down hold the migration lock and read
+// the applied versions only once it is held
+func TestMigrator_Lock(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "tcg.db")+"?_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	ctx := context.Background()
+
+	other, err := NewForDriver(db, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New(db, other.migrations)
+
+	// Another process finishes migrating while Up waits for the lock
+	var held bool
+	m.lock = func(ctx context.Context, db *sql.DB) (func() error, error) {
+		if _, err := other.Up(ctx); err != nil {
+			return nil, err
+		}
+		held = true
+		return func() error {
+			held = false
+			return nil
+		}, nil
+	}
+	if applied, err := m.Up(ctx); err != nil || len(applied) != 0 {
+		t.Errorf("Expected Up to find every migration applied, got %d applied, err %v", len(applied), err)
+	}
+	if held {
+		t.Error("Expected Up to release the lock")
+	}
+
+	lockErr := errors.New("lock unavailable")
+	m.lock = func(ctx context.Context, db *sql.DB) (func() error, error) {
+		return nil, lockErr
+	}
+	if _, err := m.Down(ctx); !errors.Is(err, lockErr) {
+		t.Errorf("Expected Down to fail without the lock, got %v", err)
+	}
+	if statuses, err := other.Status(ctx); err != nil || !statuses[len(statuses)-1].Applied {
+		t.Errorf("Expected nothing rolled back without the lock, got %+v, err %v", statuses, err)
+	}
+}
+
+// testMigrator rolls db back to an empty schema, then checks that the
+// driver's migrations apply, report their status and roll back
+func testMigrator(t *testing.T, db *sql.DB, driver string) {
+	t.Helper()
+
+	m, err := NewForDriver(db, driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrations := m.migrations
+	ctx := context.Background()
+
+	// Start from an empty schema
+	for {
+		rolledBack, err := m.Down(ctx)
+		if err != nil {
+			t.Fatalf("Down: %v", err)
+		}
+		if rolledBack == nil {
+			break
+		}
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(applied) != len(migrations) {
+		t.Errorf("Expected %d migrations applied, got %d", len(migrations), len(applied))
+	}
+	if applied, err := m.Up(ctx); err != nil || len(applied) != 0 {
+		t.Errorf("Expected second Up to do nothing, got %d applied, err %v", len(applied), err)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied || status.AppliedAt.IsZero() {
+			t.Errorf("Expected migration %d to be applied, got %+v", status.Version, status)
+		}
+	}
+
+	latest := migrations[len(migrations)-1]
+	rolledBack, err := m.Down(ctx)
+	if err != nil || rolledBack == nil || rolledBack.Version != latest.Version {
+		t.Fatalf("Down: got %+v, err %v", rolledBack, err)
+	}
+	statuses, err = m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if statuses[len(statuses)-1].Applied {
+		t.Error("Expected the latest migration to be pending after Down")
+	}
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	testDeckEntriesDown(t, db, m)
+	testPrintingSets(t, db)
+}
+
+// testDeckEntriesDown checks that rolling back deck entries keeps every copy
+// of the main deck cards
+func testDeckEntriesDown(t *testing.T, db *sql.DB, m *Migrator) {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, `INSERT INTO decks (id, name) VALUES ('deck-1', 'Burn')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.ExecContext(ctx, `INSERT INTO deck_cards (deck_id, position, card_id, card_type, quantity, section)
+		VALUES ('deck-1', 0, 'bolt', 'game-card', 4, 'main'),
+		       ('deck-1', 1, 'blot', 'game-card', 1, 'sideboard'),
+		       ('deck-1', 2, 'mountain', 'game-card', 2, 'main')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		rolledBack, err := m.Down(ctx)
+		if err != nil || rolledBack == nil {
+			t.Fatalf("Down: got %+v, err %v", rolledBack, err)
+		}
+		if rolledBack.Version == 6 {
+			break
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT position, card_id FROM deck_cards WHERE deck_id = 'deck-1' ORDER BY position`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = rows.Close() }()
+	var cards []string
+	for i := 0; rows.Next(); i++ {
+		var position int
+		var cardID string
+		if err := rows.Scan(&position, &cardID); err != nil {
+			t.Fatal(err)
+		}
+		if position != i {
+			t.Errorf("Expected position %d, got %d", i, position)
+		}
+		cards = append(cards, cardID)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"bolt", "bolt", "bolt", "bolt", "mountain", "mountain"}
+	if !slices.Equal(cards, want) {
+		t.Errorf("Expected one row per main deck copy %v, got %v", want, cards)
+	}
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `DELETE FROM decks WHERE id = 'deck-1'`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testPrintingSets checks that printings must name a set that exists, and
+// that a set cannot be deleted while cards are printed in it
+func testPrintingSets(t *testing.T, db *sql.DB) {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, `INSERT INTO game_cards (id, name) VALUES ('card-1', 'Wyrm')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.ExecContext(ctx, `INSERT INTO game_card_printings (card_id, position, set_code, collector_number, rarity)
+		VALUES ('card-1', 0, 'OLD', '1', 'rare')`)
+	if err == nil {
+		t.Error("Expected a printing in an unknown set to fail")
+	}
+
+	if _, err := db.ExecContext(ctx, `INSERT INTO card_sets (code, name) VALUES ('OLD', 'Old')`); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.ExecContext(ctx, `INSERT INTO game_card_printings (card_id, position, set_code, collector_number, rarity)
+		VALUES ('card-1', 0, 'OLD', '1', 'rare')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, `DELETE FROM card_sets WHERE code = 'OLD'`); err == nil {
+		t.Error("Expected deleting a set in use to fail")
+	}
+
+	if _, err := db.ExecContext(ctx, `DELETE FROM game_cards WHERE id = 'card-1'`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, `DELETE FROM card_sets WHERE code = 'OLD'`); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,8 @@
+DROP TABLE deck_states;
+DROP TABLE deck_cards;
+DROP TABLE decks;
+DROP TABLE playing_cards;
+DROP TABLE image_cards;
+DROP TABLE game_card_colors;
+DROP TABLE game_card_keywords;
+DROP TABLE game_cards;
@@ -0,0 +1,83 @@
+-- Initial schema for the MySQL storage backend
+
+CREATE TABLE game_cards (
+    id              CHAR(36)      NOT NULL PRIMARY KEY,
+    name            VARCHAR(255)  NOT NULL,
+    subtitle        VARCHAR(255)  NOT NULL DEFAULT '',
+    cost            INT           NOT NULL DEFAULT 0,
+    type            VARCHAR(64)   NOT NULL DEFAULT '',
+    offense         INT           NOT NULL DEFAULT 0,
+    defense         INT           NOT NULL DEFAULT 0,
+    is_resource     BOOLEAN       NOT NULL DEFAULT FALSE,
+    front_image_url VARCHAR(2048) NOT NULL DEFAULT '',
+    back_image_url  VARCHAR(2048) NOT NULL DEFAULT '',
+    created_at      DATETIME(6)   NULL,
+    updated_at      DATETIME(6)   NULL
+);
+
+CREATE TABLE game_card_keywords (
+    card_id  CHAR(36)    NOT NULL,
+    position INT         NOT NULL,
+    value    VARCHAR(64) NOT NULL,
+    PRIMARY KEY (card_id, position),
+    INDEX idx_game_card_keywords_value (value),
+    CONSTRAINT fk_game_card_keywords_card FOREIGN KEY (card_id) REFERENCES game_cards (id) ON DELETE CASCADE
+);
+
+CREATE TABLE game_card_colors (
+    card_id  CHAR(36)    NOT NULL,
+    position INT         NOT NULL,
+    value    VARCHAR(64) NOT NULL,
+    PRIMARY KEY (card_id, position),
+    INDEX idx_game_card_colors_value (value),
+    CONSTRAINT fk_game_card_colors_card FOREIGN KEY (card_id) REFERENCES game_cards (id) ON DELETE CASCADE
+);
+
+CREATE TABLE image_cards (
+    id              CHAR(36)      NOT NULL PRIMARY KEY,
+    name            VARCHAR(255)  NOT NULL,
+    description     TEXT          NOT NULL,
+    front_image_url VARCHAR(2048) NOT NULL DEFAULT '',
+    back_image_url  VARCHAR(2048) NOT NULL DEFAULT '',
+    created_at      DATETIME(6)   NULL,
+    updated_at      DATETIME(6)   NULL
+);
+
+CREATE TABLE playing_cards (
+    id              CHAR(36)      NOT NULL PRIMARY KEY,
+    suite           VARCHAR(16)   NOT NULL,
+    value           INT           NOT NULL,
+    front_image_url VARCHAR(2048) NOT NULL DEFAULT '',
+    back_image_url  VARCHAR(2048) NOT NULL DEFAULT '',
+    created_at      DATETIME(6)   NULL,
+    updated_at      DATETIME(6)   NULL
+);
+
+CREATE TABLE decks (
+    id               CHAR(36)      NOT NULL PRIMARY KEY,
+    name             VARCHAR(255)  NOT NULL,
+    owner_id         CHAR(36)      NULL,
+    sleeve_image_url VARCHAR(2048) NULL,
+    back_image_url   VARCHAR(2048) NULL,
+    created_at       DATETIME(6)   NULL,
+    updated_at       DATETIME(6)   NULL,
+    INDEX idx_decks_owner_id (owner_id)
+);
+
+-- Cards may be of any type, so card_id has no foreign key
+CREATE TABLE deck_cards (
+    deck_id  CHAR(36) NOT NULL,
+    position INT      NOT NULL,
+    card_id  CHAR(36) NOT NULL,
+    PRIMARY KEY (deck_id, position),
+    CONSTRAINT fk_deck_cards_deck FOREIGN KEY (deck_id) REFERENCES decks (id) ON DELETE CASCADE
+);
+
+CREATE TABLE deck_states (
+    id         CHAR(36)    NOT NULL PRIMARY KEY,
+    deck_id    CHAR(36)    NOT NULL,
+    data       JSON        NOT NULL,
+    created_at DATETIME(6) NULL,
+    updated_at DATETIME(6) NULL,
+    INDEX idx_deck_states_deck_id (deck_id)
+);
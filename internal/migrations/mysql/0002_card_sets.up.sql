@@ -0,0 +1,25 @@
+-- Card sets and the printings of game cards in them
+
+CREATE TABLE card_sets (
+    code         VARCHAR(16)  NOT NULL PRIMARY KEY,
+    name         VARCHAR(255) NOT NULL,
+    release_date CHAR(10)     NOT NULL DEFAULT '',
+    size         INT          NOT NULL DEFAULT 0,
+    created_at   DATETIME(6)  NULL,
+    updated_at   DATETIME(6)  NULL
+);
+
+-- Printings reference their set with a foreign key, so a set cannot be
+-- deleted while a concurrent write adds a printing in it
+CREATE TABLE game_card_printings (
+    card_id          CHAR(36)      NOT NULL,
+    position         INT           NOT NULL,
+    set_code         VARCHAR(16)   NOT NULL,
+    collector_number VARCHAR(16)   NOT NULL,
+    rarity           VARCHAR(16)   NOT NULL,
+    front_image_url  VARCHAR(2048) NOT NULL DEFAULT '',
+    PRIMARY KEY (card_id, position),
+    INDEX idx_game_card_printings_set (set_code, rarity),
+    CONSTRAINT fk_game_card_printings_card FOREIGN KEY (card_id) REFERENCES game_cards (id) ON DELETE CASCADE,
+    CONSTRAINT fk_game_card_printings_set FOREIGN KEY (set_code) REFERENCES card_sets (code) ON DELETE RESTRICT
+);
@@ -0,0 +1,5 @@
+ALTER TABLE game_cards
+    DROP INDEX idx_game_cards_game_id,
+    DROP COLUMN stats,
+    DROP COLUMN game_id;
+DROP TABLE game_definitions;
@@ -0,0 +1,18 @@
+-- Game definitions and the game each game card belongs to
+
+-- The rules of a game are read and written as a whole, so they are kept as
+-- one document, like deck state data
+CREATE TABLE game_definitions (
+    id         CHAR(36)     NOT NULL PRIMARY KEY,
+    name       VARCHAR(255) NOT NULL,
+    definition JSON         NOT NULL,
+    created_at DATETIME(6)  NULL,
+    updated_at DATETIME(6)  NULL
+);
+
+-- Games are checked by the API rather than a foreign key, so a game in use
+-- can be reported as a conflict
+ALTER TABLE game_cards
+    ADD COLUMN game_id CHAR(36) NULL,
+    ADD COLUMN stats   JSON     NULL,
+    ADD INDEX idx_game_cards_game_id (game_id);
@@ -0,0 +1,2 @@
+DROP TABLE colors;
+DROP TABLE keywords;
@@ -0,0 +1,20 @@
+-- Registries of canonical keywords and colors. Aliases are only resolved by
+-- the API, so they are kept as one JSON list.
+
+CREATE TABLE keywords (
+    name          VARCHAR(64)   NOT NULL PRIMARY KEY,
+    aliases       JSON          NOT NULL,
+    reminder_text TEXT          NOT NULL,
+    icon_url      VARCHAR(2048) NOT NULL DEFAULT '',
+    created_at    DATETIME(6)   NULL,
+    updated_at    DATETIME(6)   NULL
+);
+
+CREATE TABLE colors (
+    name          VARCHAR(64)   NOT NULL PRIMARY KEY,
+    aliases       JSON          NOT NULL,
+    reminder_text TEXT          NOT NULL,
+    icon_url      VARCHAR(2048) NOT NULL DEFAULT '',
+    created_at    DATETIME(6)   NULL,
+    updated_at    DATETIME(6)   NULL
+);
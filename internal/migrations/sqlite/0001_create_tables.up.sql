@@ -0,0 +1,84 @@
+-- Initial schema for the SQLite storage backend
+
+CREATE TABLE game_cards (
+    id              TEXT        NOT NULL PRIMARY KEY,
+    name            TEXT        NOT NULL,
+    subtitle        TEXT        NOT NULL DEFAULT '',
+    cost            INTEGER     NOT NULL DEFAULT 0,
+    type            TEXT        NOT NULL DEFAULT '',
+    offense         INTEGER     NOT NULL DEFAULT 0,
+    defense         INTEGER     NOT NULL DEFAULT 0,
+    is_resource     BOOLEAN     NOT NULL DEFAULT FALSE,
+    front_image_url TEXT        NOT NULL DEFAULT '',
+    back_image_url  TEXT        NOT NULL DEFAULT '',
+    created_at      DATETIME    NULL,
+    updated_at      DATETIME    NULL
+);
+
+CREATE TABLE game_card_keywords (
+    card_id  TEXT    NOT NULL REFERENCES game_cards (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    value    TEXT    NOT NULL,
+    PRIMARY KEY (card_id, position)
+);
+
+CREATE INDEX idx_game_card_keywords_value ON game_card_keywords (value);
+
+CREATE TABLE game_card_colors (
+    card_id  TEXT    NOT NULL REFERENCES game_cards (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    value    TEXT    NOT NULL,
+    PRIMARY KEY (card_id, position)
+);
+
+CREATE INDEX idx_game_card_colors_value ON game_card_colors (value);
+
+CREATE TABLE image_cards (
+    id              TEXT     NOT NULL PRIMARY KEY,
+    name            TEXT     NOT NULL,
+    description     TEXT     NOT NULL,
+    front_image_url TEXT     NOT NULL DEFAULT '',
+    back_image_url  TEXT     NOT NULL DEFAULT '',
+    created_at      DATETIME NULL,
+    updated_at      DATETIME NULL
+);
+
+CREATE TABLE playing_cards (
+    id              TEXT     NOT NULL PRIMARY KEY,
+    suite           TEXT     NOT NULL,
+    value           INTEGER  NOT NULL,
+    front_image_url TEXT     NOT NULL DEFAULT '',
+    back_image_url  TEXT     NOT NULL DEFAULT '',
+    created_at      DATETIME NULL,
+    updated_at      DATETIME NULL
+);
+
+CREATE TABLE decks (
+    id               TEXT     NOT NULL PRIMARY KEY,
+    name             TEXT     NOT NULL,
+    owner_id         TEXT     NULL,
+    sleeve_image_url TEXT     NULL,
+    back_image_url   TEXT     NULL,
+    created_at       DATETIME NULL,
+    updated_at       DATETIME NULL
+);
+
+CREATE INDEX idx_decks_owner_id ON decks (owner_id);
+
+-- Cards may be of any type, so card_id has no foreign key
+CREATE TABLE deck_cards (
+    deck_id  TEXT    NOT NULL REFERENCES decks (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    card_id  TEXT    NOT NULL,
+    PRIMARY KEY (deck_id, position)
+);
+
+CREATE TABLE deck_states (
+    id         TEXT     NOT NULL PRIMARY KEY,
+    deck_id    TEXT     NOT NULL,
+    data       TEXT     NOT NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL
+);
+
+CREATE INDEX idx_deck_states_deck_id ON deck_states (deck_id);
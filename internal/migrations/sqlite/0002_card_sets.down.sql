@@ -0,0 +1,2 @@
+DROP TABLE game_card_printings;
+DROP TABLE card_sets;
@@ -0,0 +1,27 @@
+-- Card sets and the printings of game cards in them
+
+CREATE TABLE card_sets (
+    code         TEXT     NOT NULL PRIMARY KEY,
+    name         TEXT     NOT NULL,
+    release_date TEXT     NOT NULL DEFAULT '',
+    size         INTEGER  NOT NULL DEFAULT 0,
+    created_at   DATETIME NULL,
+    updated_at   DATETIME NULL
+);
+
+-- Printings reference their set with a foreign key, so a set cannot be
+-- deleted while a concurrent write adds a printing in it. The set key takes
+-- the default NO ACTION, which refuses deleting a set in use just as RESTRICT
+-- does, since the key is not deferred, but reports the failure as a foreign
+-- key error rather than a trigger error.
+CREATE TABLE game_card_printings (
+    card_id          TEXT    NOT NULL REFERENCES game_cards (id) ON DELETE CASCADE,
+    position         INTEGER NOT NULL,
+    set_code         TEXT    NOT NULL REFERENCES card_sets (code),
+    collector_number TEXT    NOT NULL,
+    rarity           TEXT    NOT NULL,
+    front_image_url  TEXT    NOT NULL DEFAULT '',
+    PRIMARY KEY (card_id, position)
+);
+
+CREATE INDEX idx_game_card_printings_set ON game_card_printings (set_code, rarity);
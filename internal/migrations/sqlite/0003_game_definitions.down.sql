@@ -0,0 +1,4 @@
+DROP INDEX idx_game_cards_game_id;
+ALTER TABLE game_cards DROP COLUMN stats;
+ALTER TABLE game_cards DROP COLUMN game_id;
+DROP TABLE game_definitions;
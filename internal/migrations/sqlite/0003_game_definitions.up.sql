@@ -0,0 +1,18 @@
+-- Game definitions and the game each game card belongs to
+
+-- The rules of a game are read and written as a whole, so they are kept as
+-- one document, like deck state data
+CREATE TABLE game_definitions (
+    id         TEXT     NOT NULL PRIMARY KEY,
+    name       TEXT     NOT NULL,
+    definition TEXT     NOT NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL
+);
+
+-- Games are checked by the API rather than a foreign key, so a game in use
+-- can be reported as a conflict
+ALTER TABLE game_cards ADD COLUMN game_id TEXT NULL;
+ALTER TABLE game_cards ADD COLUMN stats TEXT NULL;
+
+CREATE INDEX idx_game_cards_game_id ON game_cards (game_id);
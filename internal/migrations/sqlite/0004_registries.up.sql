@@ -0,0 +1,20 @@
+-- Registries of canonical keywords and colors. Aliases are only resolved by
+-- the API, so they are kept as one JSON list.
+
+CREATE TABLE keywords (
+    name          TEXT     NOT NULL PRIMARY KEY,
+    aliases       TEXT     NOT NULL,
+    reminder_text TEXT     NOT NULL DEFAULT '',
+    icon_url      TEXT     NOT NULL DEFAULT '',
+    created_at    DATETIME NULL,
+    updated_at    DATETIME NULL
+);
+
+CREATE TABLE colors (
+    name          TEXT     NOT NULL PRIMARY KEY,
+    aliases       TEXT     NOT NULL,
+    reminder_text TEXT     NOT NULL DEFAULT '',
+    icon_url      TEXT     NOT NULL DEFAULT '',
+    created_at    DATETIME NULL,
+    updated_at    DATETIME NULL
+);
@@ -0,0 +1,3 @@
+ALTER TABLE decks DROP COLUMN version;
+ALTER TABLE image_cards DROP COLUMN version;
+ALTER TABLE game_cards DROP COLUMN version;
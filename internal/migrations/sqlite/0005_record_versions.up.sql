@@ -0,0 +1,5 @@
+-- Version counters for optimistic concurrency. Existing rows start at 1,
+-- as if just created.
+ALTER TABLE game_cards ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE image_cards ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE decks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,19 @@
+-- The flat format has no sections or quantities: cards outside the main
+-- deck are dropped and each entry is expanded into one row per copy, in
+-- entry order
+CREATE TABLE deck_cards_flat AS
+WITH RECURSIVE copies (deck_id, position, card_id, quantity, n) AS (
+    SELECT deck_id, position, card_id, quantity, 1 FROM deck_cards WHERE section = 'main' AND quantity > 0
+    UNION ALL
+    SELECT deck_id, position, card_id, quantity, n + 1 FROM copies WHERE n < quantity
+)
+SELECT deck_id, ROW_NUMBER() OVER (PARTITION BY deck_id ORDER BY position, n) - 1 AS position, card_id
+FROM copies;
+
+DELETE FROM deck_cards;
+INSERT INTO deck_cards (deck_id, position, card_id) SELECT deck_id, position, card_id FROM deck_cards_flat;
+DROP TABLE deck_cards_flat;
+
+ALTER TABLE deck_cards DROP COLUMN card_type;
+ALTER TABLE deck_cards DROP COLUMN section;
+ALTER TABLE deck_cards DROP COLUMN quantity;
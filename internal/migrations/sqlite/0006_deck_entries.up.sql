@@ -0,0 +1,10 @@
+-- Deck entries: each row of deck_cards holds a number of copies of a card in
+-- one section. Existing rows are single main deck copies; storage merges
+-- repeated rows of a card when it reads them.
+ALTER TABLE deck_cards ADD COLUMN quantity INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE deck_cards ADD COLUMN section TEXT NOT NULL DEFAULT 'main';
+ALTER TABLE deck_cards ADD COLUMN card_type TEXT NOT NULL DEFAULT '';
+
+UPDATE deck_cards SET card_type = 'game-card' WHERE card_id IN (SELECT id FROM game_cards);
+UPDATE deck_cards SET card_type = 'image-card' WHERE card_id IN (SELECT id FROM image_cards);
+UPDATE deck_cards SET card_type = 'playing-card' WHERE card_id IN (SELECT id FROM playing_cards);
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"regexp"
+	"slices"
+	"time"
+)
+
+// CardSet is a set or expansion that game cards are printed in
+type CardSet struct {
+	Code        string `json:"code"` // Short unique code, e.g. "CORE"
+	Name        string `json:"name"`
+	ReleaseDate string `json:"release_date,omitempty"` // YYYY-MM-DD
+	Size        int    `json:"size"`                   // Number of cards in the set
+	// Storage sets the timestamps; see storage.Storage
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReleaseDateLayout is the time layout of CardSet.ReleaseDate
+const ReleaseDateLayout = "2006-01-02"
+
+// setCodePattern matches valid set codes, which appear in URLs and fit the
+// VARCHAR(16) column of the SQL stores
+var setCodePattern = regexp.MustCompile(`^[A-Za-z0-9-]{1,16}$`)
+
+// Validate checks the fields of the set
+func (s *CardSet) Validate() []FieldError {
+	var errs fieldErrors
+	if !setCodePattern.MatchString(s.Code) {
+		errs.add("code", "must be 1-16 letters, digits or dashes")
+	}
+	errs.required("name", s.Name, MaxNameLength)
+	if s.ReleaseDate != "" {
+		if _, err := time.Parse(ReleaseDateLayout, s.ReleaseDate); err != nil {
+			errs.add("release_date", "must be formatted YYYY-MM-DD")
+		}
+	}
+	if s.Size < 0 {
+		errs.add("size", "cannot be negative")
+	}
+	return errs
+}
+
+// Printing is one printing of a game card in a set. A card may be printed in
+// several sets, or more than once in a set with different art.
+type Printing struct {
+	SetCode         string `json:"set_code"`
+	CollectorNumber string `json:"collector_number"`
+	Rarity          string `json:"rarity"`
+	FrontImageURL   string `json:"front_image_url,omitempty"` // Overrides the card's front image
+}
+
+// MaxCollectorNumberLength fits Printing.CollectorNumber in the VARCHAR(16)
+// column of the SQL stores
+const MaxCollectorNumberLength = 16
+
+// Printing rarities, from most to least common
+const (
+	RarityCommon   = "common"
+	RarityUncommon = "uncommon"
+	RarityRare     = "rare"
+	RarityMythic   = "mythic"
+	RaritySpecial  = "special"
+)
+
+// Rarities lists the valid printing rarities
+var Rarities = []string{RarityCommon, RarityUncommon, RarityRare, RarityMythic, RaritySpecial}
+
+// IsRarity reports whether s is one of Rarities
+func IsRarity(s string) bool {
+	return slices.Contains(Rarities, s)
+}
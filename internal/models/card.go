@@ -10,3 +10,10 @@ type CardInterface interface {
 	GetBackImageURL() string
 	GetCardType() string // Used for routing to correct storage/handlers
 }
+
+// Card type identifiers returned by GetCardType
+const (
+	CardTypeGameCard    = "game-card"
+	CardTypeImageCard   = "image-card"
+	CardTypePlayingCard = "playing-card"
+)
@@ -0,0 +1,199 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/shuffle"
+)
+
+var (
+	ErrCommitmentPending = errors.New("a seed commitment is pending")
+	ErrNothingToReveal   = errors.New("no committed shuffle to reveal")
+	ErrRevealPending     = errors.New("the last committed shuffle has not been revealed")
+)
+
+// DeckState tracks a deck while it is being played: the order of the draw
+// pile, the cards drawn so far and the discard pile. The top of the draw pile
+// is index 0.
+type DeckState struct {
+	ID       uuid.UUID   `json:"id"`
+	DeckID   uuid.UUID   `json:"deck_id"`
+	DrawPile []uuid.UUID `json:"draw_pile"`
+	Drawn    []uuid.UUID `json:"drawn"`
+	Discard  []uuid.UUID `json:"discard"`
+
+	// LastShuffle records how the draw pile was last shuffled
+	LastShuffle *ShuffleRecord `json:"last_shuffle,omitempty"`
+	// Commitment is a server seed published as a hash before shuffling in
+	// commit-reveal mode
+	Commitment *SeedCommitment `json:"commitment,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ShuffleRecord holds everything needed to reproduce a shuffle: replaying
+// the algorithm with Seed over Input yields the resulting draw pile
+type ShuffleRecord struct {
+	Algorithm string      `json:"algorithm"`
+	Seed      string      `json:"seed,omitempty"`
+	Input     []uuid.UUID `json:"input"`
+
+	// Commit-reveal fields. ServerSeed is withheld from clients until the
+	// shuffle is revealed; Seed is derived from ServerSeed and ClientSeed.
+	Commitment string `json:"commitment,omitempty"`
+	ServerSeed string `json:"server_seed,omitempty"`
+	ClientSeed string `json:"client_seed,omitempty"`
+	Revealed   bool   `json:"revealed,omitempty"`
+
+	ShuffledAt time.Time `json:"shuffled_at"`
+}
+
+// SeedCommitment is a server seed that has been committed to but not yet
+// used for a shuffle
+type SeedCommitment struct {
+	Commitment string `json:"commitment"`
+	ServerSeed string `json:"server_seed,omitempty"`
+}
+
+// NewDeckState creates a state for the given deck with every card in the
+// draw pile, in deck order
+func NewDeckState(deck Deck) *DeckState {
+	state := &DeckState{DeckID: deck.ID}
+	state.Reset(deck)
+	return state
+}
+
+// Reset returns every card of the deck to the draw pile in deck order and
+// clears the drawn and discard piles
+func (s *DeckState) Reset(deck Deck) {
+	s.DrawPile = make([]uuid.UUID, len(deck.Cards))
+	copy(s.DrawPile, deck.Cards)
+	s.Drawn = []uuid.UUID{}
+	s.Discard = []uuid.UUID{}
+}
+
+// Shuffle reorders the draw pile using the given seed and records the
+// shuffle so it can be reproduced. It fails if a commitment is pending,
+// since that shuffle must use the committed seed, and while the last
+// committed shuffle is unrevealed, since replacing its record would lose
+// the only copy of its server seed.
+func (s *DeckState) Shuffle(seed string) error {
+	if s.Commitment != nil {
+		return ErrCommitmentPending
+	}
+	if s.RevealPending() {
+		return ErrRevealPending
+	}
+
+	s.LastShuffle = &ShuffleRecord{
+		Algorithm:  shuffle.Algorithm,
+		Seed:       seed,
+		Input:      append([]uuid.UUID{}, s.DrawPile...),
+		ShuffledAt: time.Now().UTC(),
+	}
+	shuffle.Shuffle(seed, s.DrawPile)
+	return nil
+}
+
+// Commit publishes the hash of a new server seed for the next shuffle,
+// replacing any earlier unused commitment. It fails while the last committed
+// shuffle is unrevealed.
+func (s *DeckState) Commit(serverSeed string) error {
+	if s.RevealPending() {
+		return ErrRevealPending
+	}
+	s.Commitment = &SeedCommitment{
+		Commitment: shuffle.Commit(serverSeed),
+		ServerSeed: serverSeed,
+	}
+	return nil
+}
+
+// ShuffleCommitted shuffles the draw pile with the pending committed seed,
+// mixed with an optional seed chosen by the players. It fails while the last
+// committed shuffle is unrevealed.
+func (s *DeckState) ShuffleCommitted(clientSeed string) error {
+	if s.RevealPending() {
+		return ErrRevealPending
+	}
+	commitment := s.Commitment
+	s.Commitment = nil
+
+	seed := shuffle.Combine(commitment.ServerSeed, clientSeed)
+	_ = s.Shuffle(seed)
+	s.LastShuffle.Commitment = commitment.Commitment
+	s.LastShuffle.ServerSeed = commitment.ServerSeed
+	s.LastShuffle.ClientSeed = clientSeed
+	return nil
+}
+
+// RevealPending reports whether the last shuffle was committed and has not
+// been revealed yet
+func (s *DeckState) RevealPending() bool {
+	return s.LastShuffle != nil && s.LastShuffle.Commitment != "" && !s.LastShuffle.Revealed
+}
+
+// Reveal marks the last committed shuffle as revealed, making its seeds
+// public so players can verify it
+func (s *DeckState) Reveal() error {
+	if s.LastShuffle == nil || s.LastShuffle.Commitment == "" {
+		return ErrNothingToReveal
+	}
+	s.LastShuffle.Revealed = true
+	return nil
+}
+
+// Redacted returns a copy of the state with unrevealed seeds removed, for
+// returning to clients
+func (s *DeckState) Redacted() *DeckState {
+	stateCopy := *s
+	if s.Commitment != nil {
+		stateCopy.Commitment = &SeedCommitment{Commitment: s.Commitment.Commitment}
+	}
+	if s.RevealPending() {
+		record := *s.LastShuffle
+		record.Seed = ""
+		record.ServerSeed = ""
+		stateCopy.LastShuffle = &record
+	}
+	return &stateCopy
+}
+
+// Draw removes up to count cards from the top of the draw pile and adds them
+// to the drawn cards. Fewer cards are returned if the draw pile runs out.
+func (s *DeckState) Draw(count int) []uuid.UUID {
+	cards := s.Peek(count)
+	s.DrawPile = s.DrawPile[len(cards):]
+	s.Drawn = append(s.Drawn, cards...)
+	return cards
+}
+
+// Peek returns up to count cards from the top of the draw pile without
+// removing them
+func (s *DeckState) Peek(count int) []uuid.UUID {
+	if count > len(s.DrawPile) {
+		count = len(s.DrawPile)
+	}
+	if count < 0 {
+		count = 0
+	}
+	cards := make([]uuid.UUID, count)
+	copy(cards, s.DrawPile[:count])
+	return cards
+}
+
+// DiscardCard moves a drawn card to the discard pile. It reports false if the
+// card has not been drawn.
+func (s *DeckState) DiscardCard(cardID uuid.UUID) bool {
+	for i, id := range s.Drawn {
+		if id == cardID {
+			s.Drawn = append(s.Drawn[:i], s.Drawn[i+1:]...)
+			s.Discard = append(s.Discard, cardID)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"math"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// DefaultHandSize is the opening hand DeckStats reports odds for by default
+const DefaultHandSize = 7
+
+// DeckStats summarizes the played cards of a deck, its main and commander
+// sections. Only game cards have costs, colors and keywords; other cards
+// count toward Cards and the odds but nothing else.
+type DeckStats struct {
+	DeckID uuid.UUID `json:"deck_id"`
+	// Cards counts every copy; GameCards the copies that are game cards
+	Cards         int     `json:"cards"`
+	GameCards     int     `json:"game_cards"`
+	Resources     int     `json:"resources"`
+	NonResources  int     `json:"non_resources"`
+	ResourceRatio float64 `json:"resource_ratio"`
+	// CostCurve counts the non-resource copies at each cost
+	CostCurve map[int]int `json:"cost_curve"`
+	// Colors counts the copies of each color, a multicolored card counting
+	// toward each of its colors
+	Colors    map[string]int `json:"colors"`
+	Colorless int            `json:"colorless"`
+	Keywords  map[string]int `json:"keywords"`
+	// The averages are over the non-resource copies
+	AverageCost    float64         `json:"average_cost"`
+	AverageOffense float64         `json:"average_offense"`
+	AverageDefense float64         `json:"average_defense"`
+	OpeningHand    OpeningHandOdds `json:"opening_hand"`
+}
+
+// OpeningHandOdds are the chances of drawing cards in an opening hand from
+// the top of a shuffled deck
+type OpeningHandOdds struct {
+	Size int `json:"size"`
+	// AtLeastOneResource is the chance of one or more resource cards
+	AtLeastOneResource float64 `json:"at_least_one_resource"`
+	// Resources holds the chance of exactly 0, 1, ... Size resource cards
+	Resources []float64 `json:"resources"`
+	// Cards holds the chance of at least one copy of each card, those with
+	// the most copies first
+	Cards []CardOdds `json:"cards"`
+}
+
+// CardOdds is the chance of drawing at least one copy of a card
+type CardOdds struct {
+	CardID     uuid.UUID `json:"card_id"`
+	Copies     int       `json:"copies"`
+	AtLeastOne float64   `json:"at_least_one"`
+}
+
+// NewDeckStats computes the stats of a deck. cards holds the game cards of
+// the deck by ID; a card missing from it is not a game card. The opening
+// hand is handSize cards, or the whole deck if it is smaller.
+func NewDeckStats(deck *Deck, cards map[uuid.UUID]*GameCard, handSize int) DeckStats {
+	stats := DeckStats{
+		DeckID:    deck.ID,
+		CostCurve: map[int]int{},
+		Colors:    map[string]int{},
+		Keywords:  map[string]int{},
+	}
+
+	var cost, offense, defense int
+	copies := map[uuid.UUID]int{}
+	var order []uuid.UUID
+	for _, entry := range deck.PlayedEntries() {
+		n := entry.Quantity
+		stats.Cards += n
+		if copies[entry.CardID] == 0 {
+			order = append(order, entry.CardID)
+		}
+		copies[entry.CardID] += n
+
+		card := cards[entry.CardID]
+		if card == nil {
+			continue
+		}
+		stats.GameCards += n
+		if card.IsResource {
+			stats.Resources += n
+		} else {
+			stats.NonResources += n
+			stats.CostCurve[card.Cost] += n
+			cost += card.Cost * n
+			offense += card.Offense * n
+			defense += card.Defense * n
+		}
+		for _, color := range card.Colors {
+			stats.Colors[color] += n
+		}
+		if len(card.Colors) == 0 {
+			stats.Colorless += n
+		}
+		for _, keyword := range card.Keywords {
+			stats.Keywords[keyword] += n
+		}
+	}
+
+	if stats.Cards > 0 {
+		stats.ResourceRatio = float64(stats.Resources) / float64(stats.Cards)
+	}
+	if stats.NonResources > 0 {
+		stats.AverageCost = float64(cost) / float64(stats.NonResources)
+		stats.AverageOffense = float64(offense) / float64(stats.NonResources)
+		stats.AverageDefense = float64(defense) / float64(stats.NonResources)
+	}
+
+	hand := min(handSize, stats.Cards)
+	stats.OpeningHand = OpeningHandOdds{
+		Size:               hand,
+		AtLeastOneResource: atLeastOne(stats.Cards, stats.Resources, hand),
+		Resources:          make([]float64, hand+1),
+		Cards:              make([]CardOdds, 0, len(order)),
+	}
+	for k := range stats.OpeningHand.Resources {
+		stats.OpeningHand.Resources[k] = hypergeometric(stats.Cards, stats.Resources, hand, k)
+	}
+	for _, cardID := range order {
+		stats.OpeningHand.Cards = append(stats.OpeningHand.Cards, CardOdds{
+			CardID:     cardID,
+			Copies:     copies[cardID],
+			AtLeastOne: atLeastOne(stats.Cards, copies[cardID], hand),
+		})
+	}
+	slices.SortStableFunc(stats.OpeningHand.Cards, func(a, b CardOdds) int { return b.Copies - a.Copies })
+	return stats
+}
+
+// hypergeometric returns the chance that drawing draws cards without
+// replacement from population cards, successes of which are hits, gives
+// exactly k hits
+func hypergeometric(population, successes, draws, k int) float64 {
+	if k < 0 || k > successes || k > draws || draws-k > population-successes {
+		return 0
+	}
+	return math.Exp(logChoose(successes, k) + logChoose(population-successes, draws-k) - logChoose(population, draws))
+}
+
+// atLeastOne returns the chance of one or more hits, as for hypergeometric
+func atLeastOne(population, successes, draws int) float64 {
+	return 1 - hypergeometric(population, successes, draws, 0)
+}
+
+// logChoose returns the natural log of the binomial coefficient n choose k
+func logChoose(n, k int) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// exactHypergeometric is hypergeometric computed with exact binomials
+func exactHypergeometric(population, successes, draws, k int) float64 {
+	if k < 0 || k > successes || k > draws || draws-k > population-successes {
+		return 0
+	}
+	var hits, misses, all big.Int
+	hits.Binomial(int64(successes), int64(k))
+	misses.Binomial(int64(population-successes), int64(draws-k))
+	all.Binomial(int64(population), int64(draws))
+	p, _ := new(big.Rat).SetFrac(hits.Mul(&hits, &misses), &all).Float64()
+	return p
+}
+
+func TestHypergeometric(t *testing.T) {
+	tests := []struct {
+		population, successes, draws int
+	}{
+		{40, 17, 7},
+		{60, 24, 7},
+		{60, 4, 7},
+		{60, 1, 60},
+		{100, 40, 10},
+		{250, 100, 7},
+		{500, 3, 20},
+	}
+	for _, tt := range tests {
+		for k := -1; k <= tt.draws+1; k++ {
+			got := hypergeometric(tt.population, tt.successes, tt.draws, k)
+			want := exactHypergeometric(tt.population, tt.successes, tt.draws, k)
+			if math.Abs(got-want) > 1e-12+1e-9*want {
+				t.Errorf("hypergeometric(%d, %d, %d, %d): expected %v, got %v", tt.population, tt.successes, tt.draws, k, want, got)
+			}
+		}
+	}
+}
+
+func TestNewDeckStats_OpeningHand(t *testing.T) {
+	land, spell := uuid.New(), uuid.New()
+	cards := map[uuid.UUID]*GameCard{
+		land:  {ID: land, Name: "Mountain", IsResource: true},
+		spell: {ID: spell, Name: "Fire Bolt", Cost: 1},
+	}
+
+	tests := []struct {
+		name                string
+		resources, spells   int
+		handSize            int
+		wantSize            int
+		wantAtLeastResource float64
+	}{
+		{"40 cards", 17, 23, 7, 7, 1 - exactHypergeometric(40, 17, 7, 0)},
+		{"60 cards", 24, 36, 7, 7, 1 - exactHypergeometric(60, 24, 7, 0)},
+		{"100 cards", 38, 62, 10, 10, 1 - exactHypergeometric(100, 38, 10, 0)},
+		{"250 cards", 100, 150, 7, 7, 1 - exactHypergeometric(250, 100, 7, 0)},
+		{"no resources", 0, 60, 7, 7, 0},
+		{"only resources", 60, 0, 7, 7, 1},
+		{"hand larger than the deck", 2, 3, 7, 5, 1},
+		{"empty deck", 0, 0, 7, 0, 0},
+	}
+	for _, tt := range tests {
+		var deck Deck
+		if tt.resources > 0 {
+			deck.AddCard(land, CardTypeGameCard, SectionMain, tt.resources)
+		}
+		if tt.spells > 0 {
+			deck.AddCard(spell, CardTypeGameCard, SectionMain, tt.spells)
+		}
+		hand := NewDeckStats(&deck, cards, tt.handSize).OpeningHand
+
+		if hand.Size != tt.wantSize || len(hand.Resources) != tt.wantSize+1 {
+			t.Errorf("%s: expected a hand of %d with %d resource odds, got %d with %d", tt.name, tt.wantSize, tt.wantSize+1, hand.Size, len(hand.Resources))
+			continue
+		}
+		if math.Abs(hand.AtLeastOneResource-tt.wantAtLeastResource) > 1e-9 {
+			t.Errorf("%s: expected at least one resource %v, got %v", tt.name, tt.wantAtLeastResource, hand.AtLeastOneResource)
+		}
+		if math.Abs(hand.AtLeastOneResource-(1-hand.Resources[0])) > 1e-9 {
+			t.Errorf("%s: at least one resource %v does not match no resources %v", tt.name, hand.AtLeastOneResource, hand.Resources[0])
+		}
+
+		sum := 0.0
+		for k, p := range hand.Resources {
+			want := exactHypergeometric(tt.resources+tt.spells, tt.resources, tt.wantSize, k)
+			if p < 0 || p > 1 || math.Abs(p-want) > 1e-9 {
+				t.Errorf("%s: expected %d resources with chance %v, got %v", tt.name, k, want, p)
+			}
+			sum += p
+		}
+		if math.Abs(sum-1) > 1e-9 {
+			t.Errorf("%s: expected the resource odds to sum to 1, got %v", tt.name, sum)
+		}
+
+		for _, odds := range hand.Cards {
+			want := 1 - exactHypergeometric(tt.resources+tt.spells, odds.Copies, tt.wantSize, 0)
+			if math.Abs(odds.AtLeastOne-want) > 1e-9 {
+				t.Errorf("%s: expected at least one of %d copies with chance %v, got %v", tt.name, odds.Copies, want, odds.AtLeastOne)
+			}
+		}
+	}
+}
+
+func TestNewDeckStats(t *testing.T) {
+	land, bolt, wyrm, image := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	cards := map[uuid.UUID]*GameCard{
+		land: {ID: land, Name: "Mountain", IsResource: true, Colors: []string{"Red"}},
+		bolt: {ID: bolt, Name: "Fire Bolt", Cost: 1, Offense: 3, Colors: []string{"Red"}},
+		wyrm: {ID: wyrm, Name: "Ancient Wyrm", Cost: 7, Offense: 6, Defense: 6, Keywords: []string{"Flying"}},
+	}
+	var deck Deck
+	deck.AddCard(land, CardTypeGameCard, SectionMain, 4)
+	deck.AddCard(bolt, CardTypeGameCard, SectionMain, 3)
+	deck.AddCard(wyrm, CardTypeGameCard, SectionCommander, 1)
+	deck.AddCard(image, CardTypeImageCard, SectionMain, 2)
+	deck.AddCard(wyrm, CardTypeGameCard, SectionSideboard, 5)
+
+	stats := NewDeckStats(&deck, cards, DefaultHandSize)
+	if stats.Cards != 10 || stats.GameCards != 8 || stats.Resources != 4 || stats.NonResources != 4 || stats.ResourceRatio != 0.4 {
+		t.Errorf("Unexpected counts %+v", stats)
+	}
+	if len(stats.CostCurve) != 2 || stats.CostCurve[1] != 3 || stats.CostCurve[7] != 1 {
+		t.Errorf("Unexpected cost curve %v", stats.CostCurve)
+	}
+	if stats.Colors["Red"] != 7 || stats.Colorless != 1 || stats.Keywords["Flying"] != 1 {
+		t.Errorf("Unexpected colors %v, colorless %d and keywords %v", stats.Colors, stats.Colorless, stats.Keywords)
+	}
+	if stats.AverageCost != 2.5 || stats.AverageOffense != 3.75 || stats.AverageDefense != 1.5 {
+		t.Errorf("Unexpected averages %v, %v and %v", stats.AverageCost, stats.AverageOffense, stats.AverageDefense)
+	}
+	if got := stats.OpeningHand.Cards; len(got) != 4 || got[0].CardID != land || got[1].CardID != bolt || got[2].CardID != image || got[3].CardID != wyrm {
+		t.Errorf("Expected the card odds by copies, got %+v", got)
+	}
+}
@@ -1,19 +1,212 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Deck sections. Only the main section is played; the others travel with
+// the deck.
+const (
+	SectionMain       = "main"
+	SectionSideboard  = "sideboard"
+	SectionMaybeboard = "maybeboard"
+	SectionCommander  = "commander"
+)
+
+// DeckSections lists the valid sections in the order decks list them
+var DeckSections = []string{SectionMain, SectionSideboard, SectionMaybeboard, SectionCommander}
+
+// MaxQuantity is the most copies of a card one deck entry may hold
+const MaxQuantity = 999
+
+// ErrNotEnoughCopies is returned when removing or moving more copies of a
+// card than a section holds
+var ErrNotEnoughCopies = errors.New("not enough copies")
+
 // Deck represents the base deck structure shared across all games
 type Deck struct {
-	ID             uuid.UUID   `json:"id"`
-	Name           string      `json:"name"`
-	OwnerID        *uuid.UUID  `json:"owner_id,omitempty"`
-	SleeveImageURL *string     `json:"sleeve_image_url,omitempty"`
-	BackImageURL   *string     `json:"back_image_url,omitempty"`
-	Cards          []uuid.UUID `json:"cards"` // Array of card identifiers
-	CreatedAt      time.Time   `json:"created_at"`
-	UpdatedAt      time.Time   `json:"updated_at"`
+	ID             uuid.UUID  `json:"id"`
+	Name           string     `json:"name"`
+	OwnerID        *uuid.UUID `json:"owner_id,omitempty"`
+	SleeveImageURL *string    `json:"sleeve_image_url,omitempty"`
+	BackImageURL   *string    `json:"back_image_url,omitempty"`
+	// Entries lists the cards of the deck, one entry per card and section
+	Entries []DeckEntry `json:"entries"`
+	// Cards is the main section as a flat list, each card repeated once per
+	// copy. It is the format decks had before entries and is kept in step
+	// with Entries by Normalize.
+	Cards []uuid.UUID `json:"cards"`
+	// Version counts the writes to the deck, starting at 1. Storage sets it
+	// and the timestamps; see storage.Storage.
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DeckEntry is a number of copies of one card in one section of a deck
+type DeckEntry struct {
+	CardID uuid.UUID `json:"card_id"`
+	// CardType is the type of the card, e.g. CardTypeGameCard. The server
+	// sets it from the card.
+	CardType string `json:"card_type"`
+	Quantity int    `json:"quantity"`
+	Section  string `json:"section"`
+}
+
+// EntriesFromCards converts a flat list of card IDs into main section
+// entries, one per card in order of first appearance
+func EntriesFromCards(cards []uuid.UUID) []DeckEntry {
+	entries := []DeckEntry{}
+	index := make(map[uuid.UUID]int, len(cards))
+	for _, cardID := range cards {
+		if i, ok := index[cardID]; ok {
+			entries[i].Quantity++
+			continue
+		}
+		index[cardID] = len(entries)
+		entries = append(entries, DeckEntry{CardID: cardID, Quantity: 1, Section: SectionMain})
+	}
+	return entries
+}
+
+// UseCards replaces the main section with the cards of Cards, for clients
+// that write decks in the flat format. Entries of other sections are kept.
+func (d *Deck) UseCards() {
+	entries := make([]DeckEntry, 0, len(d.Entries))
+	for _, entry := range d.Entries {
+		if entry.Section != SectionMain && entry.Section != "" {
+			entries = append(entries, entry)
+		}
+	}
+	d.Entries = append(EntriesFromCards(d.Cards), entries...)
+}
+
+// Normalize defaults the section of each entry to main, merges entries for
+// the same card and section, drops entries of no copies and sets Cards to
+// the main section
+func (d *Deck) Normalize() {
+	entries := make([]DeckEntry, 0, len(d.Entries))
+	type key struct {
+		cardID  uuid.UUID
+		section string
+	}
+	index := make(map[key]int, len(d.Entries))
+	for _, entry := range d.Entries {
+		if entry.Section == "" {
+			entry.Section = SectionMain
+		}
+		k := key{entry.CardID, entry.Section}
+		if i, ok := index[k]; ok {
+			entries[i].Quantity += entry.Quantity
+			continue
+		}
+		index[k] = len(entries)
+		entries = append(entries, entry)
+	}
+	d.Entries = slices.DeleteFunc(entries, func(entry DeckEntry) bool { return entry.Quantity == 0 })
+	d.Cards = d.SectionCards(SectionMain)
+}
+
+// SectionCards returns the cards of a section as a flat list, each card
+// repeated once per copy
+func (d *Deck) SectionCards(section string) []uuid.UUID {
+	cards := []uuid.UUID{}
+	for _, entry := range d.Entries {
+		if entry.Section == section {
+			for range entry.Quantity {
+				cards = append(cards, entry.CardID)
+			}
+		}
+	}
+	return cards
+}
+
+// PlayedEntries returns the entries of the main and commander sections,
+// the cards a game is played with
+func (d *Deck) PlayedEntries() []DeckEntry {
+	var entries []DeckEntry
+	for _, entry := range d.Entries {
+		if entry.Section == SectionMain || entry.Section == SectionCommander {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Copies returns how many copies of a card a section holds
+func (d *Deck) Copies(cardID uuid.UUID, section string) int {
+	for _, entry := range d.Entries {
+		if entry.CardID == cardID && entry.Section == section {
+			return entry.Quantity
+		}
+	}
+	return 0
+}
+
+// AddCard adds copies of a card to a section
+func (d *Deck) AddCard(cardID uuid.UUID, cardType, section string, quantity int) {
+	d.Entries = append(d.Entries, DeckEntry{CardID: cardID, CardType: cardType, Quantity: quantity, Section: section})
+	d.Normalize()
+}
+
+// RemoveCard removes copies of a card from a section. It fails with
+// ErrNotEnoughCopies, leaving the deck unchanged, if the section holds fewer.
+func (d *Deck) RemoveCard(cardID uuid.UUID, section string, quantity int) error {
+	i := slices.IndexFunc(d.Entries, func(entry DeckEntry) bool {
+		return entry.CardID == cardID && entry.Section == section
+	})
+	if i < 0 || d.Entries[i].Quantity < quantity {
+		return fmt.Errorf("%w: %s holds %d of card %s", ErrNotEnoughCopies, section, d.Copies(cardID, section), cardID)
+	}
+	d.Entries[i].Quantity -= quantity
+	d.Normalize()
+	return nil
+}
+
+// MoveCard moves copies of a card from one section to another
+func (d *Deck) MoveCard(cardID uuid.UUID, from, to string, quantity int) error {
+	i := slices.IndexFunc(d.Entries, func(entry DeckEntry) bool {
+		return entry.CardID == cardID && entry.Section == from
+	})
+	if i < 0 || d.Entries[i].Quantity < quantity {
+		return fmt.Errorf("%w: %s holds %d of card %s", ErrNotEnoughCopies, from, d.Copies(cardID, from), cardID)
+	}
+	cardType := d.Entries[i].CardType
+	d.Entries[i].Quantity -= quantity
+	d.AddCard(cardID, cardType, to, quantity)
+	return nil
+}
+
+// Validate checks the name, image URLs and entries of the deck. A deck may
+// be unnamed.
+func (d *Deck) Validate() []FieldError {
+	var errs fieldErrors
+	errs.maxLength("name", d.Name, MaxNameLength)
+	for _, image := range []struct {
+		field string
+		value *string
+	}{
+		{"sleeve_image_url", d.SleeveImageURL},
+		{"back_image_url", d.BackImageURL},
+	} {
+		if image.value != nil {
+			errs.url(image.field, *image.value)
+		}
+	}
+	for i, entry := range d.Entries {
+		field := fmt.Sprintf("entries[%d]", i)
+		if entry.CardID == uuid.Nil {
+			errs.add(field+".card_id", "is required")
+		}
+		errs.intRange(field+".quantity", entry.Quantity, 1, MaxQuantity)
+		if entry.Section != "" {
+			errs.oneOf(field+".section", entry.Section, DeckSections)
+		}
+	}
+	return errs
 }
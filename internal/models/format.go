@@ -0,0 +1,312 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Format is a named set of deck construction rules within a game, such as a
+// constructed or a singleton format. Zero size and copy limits fall back to
+// the game's DeckRules.
+//
+// The deck is the main and commander sections together; the sideboard is
+// limited separately and the maybeboard is never checked. Copy limits,
+// banned cards, colors and sets apply to the deck and sideboard alike.
+type Format struct {
+	Name     string `json:"name"`
+	MinCards int    `json:"min_cards,omitempty"`
+	MaxCards int    `json:"max_cards,omitempty"`
+	// MaxCopies caps the copies of any one card across the deck and
+	// sideboard. Resource cards are exempt, as in DeckRules, and restricted
+	// cards are limited by Restricted instead.
+	MaxCopies    int `json:"max_copies,omitempty"`
+	MaxSideboard int `json:"max_sideboard,omitempty"`
+	// Banned cards may not be played; Restricted cards may have one copy
+	Banned     []uuid.UUID `json:"banned,omitempty"`
+	Restricted []uuid.UUID `json:"restricted,omitempty"`
+	// Colors and Sets list the allowed colors and set codes; empty allows any.
+	// A card is in an allowed set if any of its printings is.
+	Colors []string `json:"colors,omitempty"`
+	Sets   []string `json:"sets,omitempty"`
+	// MinResourceRatio and MaxResourceRatio bound the share of the deck that
+	// is resource cards, between 0 and 1. Zero means no bound.
+	MinResourceRatio float64 `json:"min_resource_ratio,omitempty"`
+	MaxResourceRatio float64 `json:"max_resource_ratio,omitempty"`
+}
+
+// Format rules reported in Violation.Rule
+const (
+	RuleGame             = "game"
+	RuleMinCards         = "min_cards"
+	RuleMaxCards         = "max_cards"
+	RuleMaxSideboard     = "max_sideboard"
+	RuleMaxCopies        = "max_copies"
+	RuleBanned           = "banned"
+	RuleRestricted       = "restricted"
+	RuleColors           = "colors"
+	RuleSets             = "sets"
+	RuleMinResourceRatio = "min_resource_ratio"
+	RuleMaxResourceRatio = "max_resource_ratio"
+)
+
+// Violation is one way a deck breaks the rules of a format. CardIDs lists
+// the offending cards, if the rule is about particular cards.
+type Violation struct {
+	Rule    string      `json:"rule"`
+	Message string      `json:"message"`
+	CardIDs []uuid.UUID `json:"card_ids,omitempty"`
+}
+
+// validate checks that the format is self-consistent within game d,
+// reporting problems under field
+func (f *Format) validate(errs *fieldErrors, field string, d *GameDefinition) {
+	if errs.nonNegative(
+		limit{field + ".min_cards", f.MinCards},
+		limit{field + ".max_cards", f.MaxCards},
+		limit{field + ".max_copies", f.MaxCopies},
+		limit{field + ".max_sideboard", f.MaxSideboard},
+	) && f.MaxCards > 0 && f.MinCards > f.MaxCards {
+		errs.add(field+".min_cards", "is above max_cards")
+	}
+
+	ratiosValid := true
+	for _, ratio := range []struct {
+		name  string
+		value float64
+	}{
+		{"min_resource_ratio", f.MinResourceRatio},
+		{"max_resource_ratio", f.MaxResourceRatio},
+	} {
+		if ratio.value < 0 || ratio.value > 1 {
+			errs.add(field+"."+ratio.name, "must be between 0 and 1")
+			ratiosValid = false
+		}
+	}
+	if ratiosValid && f.MaxResourceRatio > 0 && f.MinResourceRatio > f.MaxResourceRatio {
+		errs.add(field+".min_resource_ratio", "is above max_resource_ratio")
+	}
+
+	if len(d.Colors) > 0 {
+		for i, color := range f.Colors {
+			if !slices.Contains(d.Colors, color) {
+				errs.add(fmt.Sprintf("%s.colors[%d]", field, i), "%q is not a color of %s", color, d.Name)
+			}
+		}
+	}
+}
+
+// Format returns the named format of the game with zero limits filled in
+// from DeckRules, or a format of just the DeckRules if name is empty
+func (d *GameDefinition) Format(name string) (Format, bool) {
+	var format Format
+	if name != "" {
+		i := slices.IndexFunc(d.Formats, func(f Format) bool { return f.Name == name })
+		if i < 0 {
+			return Format{}, false
+		}
+		format = d.Formats[i]
+	}
+	if format.MinCards == 0 {
+		format.MinCards = d.DeckRules.MinCards
+	}
+	if format.MaxCards == 0 {
+		format.MaxCards = d.DeckRules.MaxCards
+	}
+	if format.MaxCopies == 0 {
+		format.MaxCopies = d.DeckRules.MaxCopies
+	}
+	return format, true
+}
+
+// FormatNames returns the names of the game's formats
+func (d *GameDefinition) FormatNames() []string {
+	names := make([]string, len(d.Formats))
+	for i, format := range d.Formats {
+		names[i] = format.Name
+	}
+	return names
+}
+
+// deckContents is a deck prepared for the format rules
+type deckContents struct {
+	gameID uuid.UUID
+	// deck holds the main and commander entries, sideboard the sideboard's
+	deck, sideboard []DeckEntry
+	// cards holds the game cards of the deck by ID; other cards are missing
+	cards map[uuid.UUID]*GameCard
+}
+
+// size returns the number of copies in entries
+func size(entries []DeckEntry) int {
+	n := 0
+	for _, entry := range entries {
+		n += entry.Quantity
+	}
+	return n
+}
+
+// checked returns the entries every card rule applies to
+func (c *deckContents) checked() []DeckEntry {
+	return append(slices.Clone(c.deck), c.sideboard...)
+}
+
+// offending returns the IDs of the checked cards for which bad is true, in
+// deck order
+func (c *deckContents) offending(bad func(cardID uuid.UUID, card *GameCard) bool) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, entry := range c.checked() {
+		if !slices.Contains(ids, entry.CardID) && bad(entry.CardID, c.cards[entry.CardID]) {
+			ids = append(ids, entry.CardID)
+		}
+	}
+	return ids
+}
+
+// formatRule checks one rule of a format, returning a violation if the deck
+// breaks it
+type formatRule func(f *Format, c *deckContents) *Violation
+
+// formatRules are the rules CheckDeck applies, in the order it reports them
+var formatRules = []formatRule{
+	func(f *Format, c *deckContents) *Violation {
+		ids := c.offending(func(_ uuid.UUID, card *GameCard) bool {
+			return card == nil || card.GameID == nil || *card.GameID != c.gameID
+		})
+		return cardViolation(RuleGame, ids, "cards are not game cards of this game")
+	},
+	func(f *Format, c *deckContents) *Violation {
+		if n := size(c.deck); f.MinCards > 0 && n < f.MinCards {
+			return &Violation{Rule: RuleMinCards, Message: fmt.Sprintf("deck has %d cards; at least %d are required", n, f.MinCards)}
+		}
+		return nil
+	},
+	func(f *Format, c *deckContents) *Violation {
+		if n := size(c.deck); f.MaxCards > 0 && n > f.MaxCards {
+			return &Violation{Rule: RuleMaxCards, Message: fmt.Sprintf("deck has %d cards; at most %d are allowed", n, f.MaxCards)}
+		}
+		return nil
+	},
+	func(f *Format, c *deckContents) *Violation {
+		if n := size(c.sideboard); f.MaxSideboard > 0 && n > f.MaxSideboard {
+			return &Violation{Rule: RuleMaxSideboard, Message: fmt.Sprintf("sideboard has %d cards; at most %d are allowed", n, f.MaxSideboard)}
+		}
+		return nil
+	},
+	func(f *Format, c *deckContents) *Violation {
+		if f.MaxCopies == 0 {
+			return nil
+		}
+		copies := c.copies()
+		ids := c.offending(func(cardID uuid.UUID, card *GameCard) bool {
+			resource := card != nil && card.IsResource
+			return !resource && !slices.Contains(f.Restricted, cardID) && copies[cardID] > f.MaxCopies
+		})
+		return cardViolation(RuleMaxCopies, ids, fmt.Sprintf("cards have more copies than max_copies (%d)", f.MaxCopies))
+	},
+	func(f *Format, c *deckContents) *Violation {
+		ids := c.offending(func(cardID uuid.UUID, _ *GameCard) bool { return slices.Contains(f.Banned, cardID) })
+		return cardViolation(RuleBanned, ids, "cards are banned")
+	},
+	func(f *Format, c *deckContents) *Violation {
+		copies := c.copies()
+		ids := c.offending(func(cardID uuid.UUID, _ *GameCard) bool {
+			return slices.Contains(f.Restricted, cardID) && copies[cardID] > 1
+		})
+		return cardViolation(RuleRestricted, ids, "restricted cards have more than one copy")
+	},
+	func(f *Format, c *deckContents) *Violation {
+		if len(f.Colors) == 0 {
+			return nil
+		}
+		ids := c.offending(func(_ uuid.UUID, card *GameCard) bool {
+			return card != nil && slices.ContainsFunc(card.Colors, func(color string) bool {
+				return !slices.Contains(f.Colors, color)
+			})
+		})
+		return cardViolation(RuleColors, ids, "cards have colors other than "+strings.Join(f.Colors, ", "))
+	},
+	func(f *Format, c *deckContents) *Violation {
+		if len(f.Sets) == 0 {
+			return nil
+		}
+		ids := c.offending(func(_ uuid.UUID, card *GameCard) bool {
+			return card != nil && !slices.ContainsFunc(card.Printings, func(p Printing) bool {
+				return slices.Contains(f.Sets, p.SetCode)
+			})
+		})
+		return cardViolation(RuleSets, ids, "cards are not printed in "+strings.Join(f.Sets, ", "))
+	},
+	func(f *Format, c *deckContents) *Violation {
+		ratio, ok := c.resourceRatio()
+		if ok && f.MinResourceRatio > 0 && ratio < f.MinResourceRatio {
+			return &Violation{Rule: RuleMinResourceRatio,
+				Message: fmt.Sprintf("resource cards are %.0f%% of the deck; at least %.0f%% are required", ratio*100, f.MinResourceRatio*100)}
+		}
+		return nil
+	},
+	func(f *Format, c *deckContents) *Violation {
+		ratio, ok := c.resourceRatio()
+		if ok && f.MaxResourceRatio > 0 && ratio > f.MaxResourceRatio {
+			return &Violation{Rule: RuleMaxResourceRatio,
+				Message: fmt.Sprintf("resource cards are %.0f%% of the deck; at most %.0f%% are allowed", ratio*100, f.MaxResourceRatio*100)}
+		}
+		return nil
+	},
+}
+
+// copies counts the copies of each card in the deck and sideboard
+func (c *deckContents) copies() map[uuid.UUID]int {
+	copies := make(map[uuid.UUID]int)
+	for _, entry := range c.checked() {
+		copies[entry.CardID] += entry.Quantity
+	}
+	return copies
+}
+
+// resourceRatio returns the share of the deck that is resource cards, or
+// false for an empty deck
+func (c *deckContents) resourceRatio() (float64, bool) {
+	total := size(c.deck)
+	if total == 0 {
+		return 0, false
+	}
+	resources := 0
+	for _, entry := range c.deck {
+		if card := c.cards[entry.CardID]; card != nil && card.IsResource {
+			resources += entry.Quantity
+		}
+	}
+	return float64(resources) / float64(total), true
+}
+
+// cardViolation reports the cards ids as breaking rule, or nil if there are
+// none
+func cardViolation(rule string, ids []uuid.UUID, message string) *Violation {
+	if len(ids) == 0 {
+		return nil
+	}
+	return &Violation{Rule: rule, Message: message, CardIDs: ids}
+}
+
+// CheckDeck checks a deck against a format of the game, returning every
+// violation. cards holds the game cards of the deck by ID; a card missing
+// from it is not a game card.
+func (d *GameDefinition) CheckDeck(deck *Deck, format Format, cards map[uuid.UUID]*GameCard) []Violation {
+	contents := &deckContents{gameID: d.ID, deck: deck.PlayedEntries(), cards: cards}
+	for _, entry := range deck.Entries {
+		if entry.Section == SectionSideboard {
+			contents.sideboard = append(contents.sideboard, entry)
+		}
+	}
+
+	violations := []Violation{}
+	for _, rule := range formatRules {
+		if violation := rule(&format, contents); violation != nil {
+			violations = append(violations, *violation)
+		}
+	}
+	return violations
+}
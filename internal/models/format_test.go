@@ -0,0 +1,153 @@
+package models
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// testGame is a game with deck rules and formats that rely on them
+func testGame() *GameDefinition {
+	return &GameDefinition{
+		ID:        uuid.New(),
+		Name:      "Skirmish",
+		DeckRules: DeckRules{MinCards: 4, MaxCards: 8, MaxCopies: 2},
+		Formats: []Format{
+			{Name: "open"},
+			{Name: "singleton", MaxCopies: 1, MaxSideboard: 2},
+			{Name: "wide", MinCards: 2, MaxCards: 20, MaxCopies: 4},
+		},
+	}
+}
+
+func TestGameDefinition_Format(t *testing.T) {
+	game := testGame()
+	tests := []struct {
+		name   string
+		want   Format
+		wantOK bool
+	}{
+		{"", Format{MinCards: 4, MaxCards: 8, MaxCopies: 2}, true},
+		{"open", Format{Name: "open", MinCards: 4, MaxCards: 8, MaxCopies: 2}, true},
+		{"singleton", Format{Name: "singleton", MinCards: 4, MaxCards: 8, MaxCopies: 1, MaxSideboard: 2}, true},
+		{"wide", Format{Name: "wide", MinCards: 2, MaxCards: 20, MaxCopies: 4}, true},
+		{"unknown", Format{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := game.Format(tt.name)
+		if ok != tt.wantOK || got.Name != tt.want.Name || got.MinCards != tt.want.MinCards ||
+			got.MaxCards != tt.want.MaxCards || got.MaxCopies != tt.want.MaxCopies || got.MaxSideboard != tt.want.MaxSideboard {
+			t.Errorf("Format(%q): expected %+v %v, got %+v %v", tt.name, tt.want, tt.wantOK, got, ok)
+		}
+	}
+
+	// Falling back does not change the stored format
+	if game.Formats[0].MinCards != 0 || game.Formats[0].MaxCopies != 0 {
+		t.Errorf("Expected the stored format to keep its zero limits, got %+v", game.Formats[0])
+	}
+}
+
+func TestGameDefinition_CheckDeck(t *testing.T) {
+	game := testGame()
+	otherGame := uuid.New()
+	land, bolt, ward, wyrm, stray, missing := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	cards := map[uuid.UUID]*GameCard{
+		land:  {ID: land, Name: "Mountain", IsResource: true, GameID: &game.ID, Colors: []string{"Red"}},
+		bolt:  {ID: bolt, Name: "Fire Bolt", GameID: &game.ID, Colors: []string{"Red"}},
+		ward:  {ID: ward, Name: "Frost Ward", GameID: &game.ID, Colors: []string{"Blue"}},
+		wyrm:  {ID: wyrm, Name: "Ancient Wyrm", GameID: &game.ID, Colors: []string{"Red"}},
+		stray: {ID: stray, Name: "Stray", GameID: &otherGame},
+	}
+
+	type entry struct {
+		cardID   uuid.UUID
+		section  string
+		quantity int
+	}
+	type want struct {
+		rule    string
+		cardIDs []uuid.UUID
+	}
+	tests := []struct {
+		name    string
+		format  Format
+		entries []entry
+		want    []want
+	}{
+		{"legal under the deck rules", Format{Name: "open"}, []entry{{bolt, SectionMain, 2}, {ward, SectionMain, 2}}, nil},
+		{"zero min_cards falls back", Format{Name: "open"}, []entry{{bolt, SectionMain, 2}, {ward, SectionMain, 1}},
+			[]want{{RuleMinCards, nil}}},
+		{"zero max_cards falls back", Format{Name: "open"},
+			[]entry{{land, SectionMain, 5}, {bolt, SectionMain, 2}, {ward, SectionMain, 2}},
+			[]want{{RuleMaxCards, nil}}},
+		{"zero max_copies falls back", Format{Name: "open"}, []entry{{bolt, SectionMain, 3}, {ward, SectionMain, 1}},
+			[]want{{RuleMaxCopies, []uuid.UUID{bolt}}}},
+		{"own limits win", Format{Name: "wide"}, []entry{{bolt, SectionMain, 4}, {ward, SectionMain, 4}, {wyrm, SectionMain, 4}}, nil},
+		{"sideboard copies count", Format{Name: "singleton"}, []entry{{bolt, SectionMain, 1}, {ward, SectionMain, 3}, {bolt, SectionSideboard, 1}},
+			[]want{{RuleMaxCopies, []uuid.UUID{bolt, ward}}}},
+		{"maybeboard is not checked", Format{Name: "singleton"},
+			[]entry{{land, SectionMain, 3}, {bolt, SectionMain, 1}, {bolt, SectionMaybeboard, 9}, {stray, SectionMaybeboard, 1}}, nil},
+		{"max_sideboard", Format{Name: "singleton"}, []entry{{land, SectionMain, 4}, {land, SectionSideboard, 3}},
+			[]want{{RuleMaxSideboard, nil}}},
+		{"resources are exempt from max_copies", Format{Name: "singleton"}, []entry{{land, SectionMain, 6}, {bolt, SectionMain, 1}}, nil},
+		{"non-resources are not exempt", Format{Name: "singleton"}, []entry{{land, SectionMain, 2}, {bolt, SectionMain, 2}},
+			[]want{{RuleMaxCopies, []uuid.UUID{bolt}}}},
+		{"resources are exempt in the sideboard", Format{Name: "singleton"}, []entry{{land, SectionMain, 4}, {land, SectionSideboard, 2}}, nil},
+		{"resources are exempt from fallen back max_copies", Format{Name: "open"}, []entry{{land, SectionMain, 6}, {bolt, SectionMain, 2}}, nil},
+		{"restricted card with one copy", Format{Restricted: []uuid.UUID{bolt}}, []entry{{bolt, SectionMain, 1}, {ward, SectionMain, 2}, {land, SectionMain, 2}}, nil},
+		{"restricted card over the limit", Format{Restricted: []uuid.UUID{bolt}}, []entry{{bolt, SectionMain, 2}, {ward, SectionMain, 2}},
+			[]want{{RuleRestricted, []uuid.UUID{bolt}}}},
+		{"restricted cards skip max_copies", Format{Restricted: []uuid.UUID{bolt}}, []entry{{bolt, SectionMain, 3}, {ward, SectionMain, 3}},
+			[]want{{RuleMaxCopies, []uuid.UUID{ward}}, {RuleRestricted, []uuid.UUID{bolt}}}},
+		{"restricted in the sideboard", Format{Restricted: []uuid.UUID{bolt}}, []entry{{bolt, SectionMain, 1}, {ward, SectionMain, 3}, {bolt, SectionSideboard, 1}},
+			[]want{{RuleMaxCopies, []uuid.UUID{ward}}, {RuleRestricted, []uuid.UUID{bolt}}}},
+		{"banned card", Format{Banned: []uuid.UUID{ward}}, []entry{{bolt, SectionMain, 2}, {ward, SectionSideboard, 1}, {land, SectionMain, 2}},
+			[]want{{RuleBanned, []uuid.UUID{ward}}}},
+		{"banned cards still count towards max_copies", Format{Banned: []uuid.UUID{ward}}, []entry{{bolt, SectionMain, 1}, {ward, SectionMain, 3}},
+			[]want{{RuleMaxCopies, []uuid.UUID{ward}}, {RuleBanned, []uuid.UUID{ward}}}},
+		{"banned and restricted with one copy", Format{Banned: []uuid.UUID{ward}, Restricted: []uuid.UUID{ward}},
+			[]entry{{bolt, SectionMain, 2}, {ward, SectionMain, 1}, {land, SectionMain, 1}},
+			[]want{{RuleBanned, []uuid.UUID{ward}}}},
+		{"banned and restricted over the limit", Format{Banned: []uuid.UUID{ward}, Restricted: []uuid.UUID{ward}},
+			[]entry{{bolt, SectionMain, 1}, {ward, SectionMain, 3}},
+			[]want{{RuleBanned, []uuid.UUID{ward}}, {RuleRestricted, []uuid.UUID{ward}}}},
+		{"cards outside the game", Format{}, []entry{{bolt, SectionMain, 2}, {stray, SectionMain, 1}, {missing, SectionSideboard, 1}, {ward, SectionMain, 1}},
+			[]want{{RuleGame, []uuid.UUID{stray, missing}}}},
+		{"colors", Format{Colors: []string{"Red"}}, []entry{{bolt, SectionMain, 2}, {ward, SectionMain, 2}},
+			[]want{{RuleColors, []uuid.UUID{ward}}}},
+		{"resource ratios", Format{MinResourceRatio: 0.5}, []entry{{land, SectionMain, 1}, {bolt, SectionMain, 2}, {ward, SectionMain, 1}},
+			[]want{{RuleMinResourceRatio, nil}}},
+	}
+
+	for _, tt := range tests {
+		// Unnamed formats are added to the game, so that Format fills in
+		// their limits like those of the game's own formats
+		checked := *game
+		if tt.format.Name == "" {
+			tt.format.Name = "test"
+			checked.Formats = append(slices.Clone(game.Formats), tt.format)
+		}
+		format, ok := checked.Format(tt.format.Name)
+		if !ok {
+			t.Fatalf("%s: unknown format %q", tt.name, tt.format.Name)
+		}
+
+		var deck Deck
+		for _, e := range tt.entries {
+			deck.AddCard(e.cardID, CardTypeGameCard, e.section, e.quantity)
+		}
+		violations := game.CheckDeck(&deck, format, cards)
+
+		var got []want
+		for _, v := range violations {
+			if v.Message == "" {
+				t.Errorf("%s: violation of %s has no message", tt.name, v.Rule)
+			}
+			got = append(got, want{v.Rule, v.CardIDs})
+		}
+		if !slices.EqualFunc(got, tt.want, func(a, b want) bool { return a.rule == b.rule && slices.Equal(a.cardIDs, b.cardIDs) }) {
+			t.Errorf("%s: expected violations %v, got %v", tt.name, tt.want, violations)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,27 +9,53 @@ import (
 
 // GameCard represents a TCG-specific card with game mechanics
 type GameCard struct {
-	ID            uuid.UUID
-	Name          string    `json:"name"`
-	Subtitle      string    `json:"subtitle"`
-	Cost          int       `json:"cost"`
-	Type          string    `json:"type"`
-	Offense       int       `json:"offense"`
-	Defense       int       `json:"defense"`
-	Keywords      []string  `json:"keywords"`
-	Colors        []string  `json:"colors"`
-	IsResource    bool      `json:"is_resource"`
-	FrontImageURL string    `json:"front_image_url"`
-	BackImageURL  string    `json:"back_image_url"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            uuid.UUID  `json:"id"`
+	Name          string     `json:"name"`
+	Subtitle      string     `json:"subtitle"`
+	Cost          int        `json:"cost"`
+	Type          string     `json:"type"`
+	Offense       int        `json:"offense"`
+	Defense       int        `json:"defense"`
+	Keywords      []string   `json:"keywords"`
+	Colors        []string   `json:"colors"`
+	IsResource    bool       `json:"is_resource"`
+	FrontImageURL string     `json:"front_image_url"`
+	BackImageURL  string     `json:"back_image_url"`
+	Printings     []Printing `json:"printings"`
+	// GameID names the GameDefinition whose rules the card follows, if any
+	GameID *uuid.UUID `json:"game_id,omitempty"`
+	// Stats holds the values of the game's stats other than BuiltinStats
+	Stats map[string]any `json:"stats,omitempty"`
+	// Version counts the writes to the card, starting at 1. Storage sets it
+	// and the timestamps; see storage.Storage.
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
-const typeGameCard = "game-card"
-
 // Implement CardInterface
 func (c *GameCard) GetID() uuid.UUID         { return c.ID }
 func (c *GameCard) GetName() string          { return c.Name }
 func (c *GameCard) GetFrontImageURL() string { return c.FrontImageURL }
 func (c *GameCard) GetBackImageURL() string  { return c.BackImageURL }
-func (c *GameCard) GetCardType() string      { return typeGameCard }
+func (c *GameCard) GetCardType() string      { return CardTypeGameCard }
+
+// Validate checks the fields of the card. Rules that depend on the card's
+// game are checked by GameDefinition.ValidateCard.
+func (c *GameCard) Validate() []FieldError {
+	var errs fieldErrors
+	errs.required("name", c.Name, MaxNameLength)
+	errs.maxLength("subtitle", c.Subtitle, MaxNameLength)
+	errs.maxLength("type", c.Type, MaxTagLength)
+	errs.intRange("cost", c.Cost, 0, MaxStatValue)
+	errs.intRange("offense", c.Offense, 0, MaxStatValue)
+	errs.intRange("defense", c.Defense, 0, MaxStatValue)
+	errs.values("keywords", c.Keywords)
+	errs.values("colors", c.Colors)
+	errs.url("front_image_url", c.FrontImageURL)
+	errs.url("back_image_url", c.BackImageURL)
+	for i, printing := range c.Printings {
+		errs.url(fmt.Sprintf("printings[%d].front_image_url", i), printing.FrontImageURL)
+	}
+	return errs
+}
@@ -0,0 +1,246 @@
+package models
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GameDefinition declares the rules game cards of one game must follow
+type GameDefinition struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// CardTypes lists the allowed GameCard.Type values; empty allows any
+	CardTypes []string `json:"card_types"`
+	// Stats declares the stats cards of the game carry
+	Stats []StatDefinition `json:"stats"`
+	// Colors and Keywords list the allowed values; empty allows any
+	Colors    []string  `json:"colors"`
+	Keywords  []string  `json:"keywords"`
+	DeckRules DeckRules `json:"deck_rules"`
+	// Formats are named variations on DeckRules decks can be checked against
+	Formats []Format `json:"formats,omitempty"`
+	// Storage sets the timestamps; see storage.Storage
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Stat value types
+const (
+	StatTypeInteger = "integer"
+	StatTypeString  = "string"
+	StatTypeBoolean = "boolean"
+)
+
+// BuiltinStats are the stats with their own GameCard fields. They are always
+// integers, and must be zero on cards of games that do not declare them.
+var BuiltinStats = []string{"cost", "offense", "defense"}
+
+// StatDefinition declares one stat of a game's cards. Stats other than
+// BuiltinStats are held in GameCard.Stats.
+type StatDefinition struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Min and Max bound integer stats when set
+	Min *int `json:"min,omitempty"`
+	Max *int `json:"max,omitempty"`
+	// Required stats must be present on every card. Built-in stats are
+	// always present.
+	Required bool `json:"required,omitempty"`
+}
+
+// DeckRules are a game's deck construction limits. Zero means no limit.
+type DeckRules struct {
+	MinCards int `json:"min_cards"`
+	MaxCards int `json:"max_cards"`
+	// MaxCopies caps the copies of any one card, except resource cards
+	MaxCopies int `json:"max_copies"`
+}
+
+// Validate checks that the definition is self-consistent, returning a
+// FieldError for each problem
+func (d *GameDefinition) Validate() []FieldError {
+	var errs fieldErrors
+	errs.required("name", d.Name, MaxNameLength)
+
+	seen := make(map[string]bool, len(d.Stats))
+	for i, stat := range d.Stats {
+		field := fmt.Sprintf("stats[%d]", i)
+		switch {
+		case stat.Name == "":
+			errs.add(field+".name", "is required")
+			continue
+		case seen[stat.Name]:
+			errs.add(field+".name", "%q is declared twice", stat.Name)
+		}
+		seen[stat.Name] = true
+
+		switch stat.Type {
+		case StatTypeInteger:
+			if stat.Min != nil && stat.Max != nil && *stat.Min > *stat.Max {
+				errs.add(field+".min", "is above max")
+			}
+		case StatTypeString, StatTypeBoolean:
+			if stat.Min != nil {
+				errs.add(field+".min", "is only allowed on integer stats")
+			}
+			if stat.Max != nil {
+				errs.add(field+".max", "is only allowed on integer stats")
+			}
+			if slices.Contains(BuiltinStats, stat.Name) {
+				errs.add(field+".type", "must be integer for %q", stat.Name)
+			}
+		default:
+			errs.add(field+".type", "must be one of: %s, %s, %s", StatTypeInteger, StatTypeString, StatTypeBoolean)
+		}
+	}
+
+	rules := d.DeckRules
+	if errs.nonNegative(
+		limit{"deck_rules.min_cards", rules.MinCards},
+		limit{"deck_rules.max_cards", rules.MaxCards},
+		limit{"deck_rules.max_copies", rules.MaxCopies},
+	) && rules.MaxCards > 0 && rules.MinCards > rules.MaxCards {
+		errs.add("deck_rules.min_cards", "is above max_cards")
+	}
+
+	formats := make(map[string]bool, len(d.Formats))
+	for i, format := range d.Formats {
+		field := fmt.Sprintf("formats[%d]", i)
+		switch {
+		case format.Name == "":
+			errs.add(field+".name", "is required")
+			continue
+		case formats[format.Name]:
+			errs.add(field+".name", "%q is declared twice", format.Name)
+		}
+		formats[format.Name] = true
+		format.validate(&errs, field, d)
+	}
+	return errs
+}
+
+// stat returns the definition of the named stat, if declared
+func (d *GameDefinition) stat(name string) (StatDefinition, bool) {
+	for _, stat := range d.Stats {
+		if stat.Name == name {
+			return stat, true
+		}
+	}
+	return StatDefinition{}, false
+}
+
+// ValidateCard checks a card against the game's rules, returning a
+// FieldError for each problem
+func (d *GameDefinition) ValidateCard(card *GameCard) []FieldError {
+	var errs fieldErrors
+
+	if len(d.CardTypes) > 0 && !slices.Contains(d.CardTypes, card.Type) {
+		errs.add("type", "%q is not a card type of %s", card.Type, d.Name)
+	}
+	for _, list := range []struct {
+		field, what     string
+		allowed, values []string
+	}{
+		{"colors", "color", d.Colors, card.Colors},
+		{"keywords", "keyword", d.Keywords, card.Keywords},
+	} {
+		if len(list.allowed) == 0 {
+			continue
+		}
+		for i, value := range list.values {
+			if !slices.Contains(list.allowed, value) {
+				errs.add(fmt.Sprintf("%s[%d]", list.field, i), "%q is not a %s of %s", value, list.what, d.Name)
+			}
+		}
+	}
+
+	builtins := map[string]int{"cost": card.Cost, "offense": card.Offense, "defense": card.Defense}
+	for _, name := range BuiltinStats {
+		value := builtins[name]
+		stat, ok := d.stat(name)
+		if !ok {
+			if value != 0 {
+				errs.add(name, "is not a stat of %s", d.Name)
+			}
+			continue
+		}
+		stat.check(&errs, name, value)
+	}
+
+	for _, stat := range d.Stats {
+		if slices.Contains(BuiltinStats, stat.Name) {
+			continue
+		}
+		field := "stats." + stat.Name
+		value, ok := card.Stats[stat.Name]
+		if !ok {
+			if stat.Required {
+				errs.add(field, "is required")
+			}
+			continue
+		}
+		stat.check(&errs, field, value)
+	}
+	for _, name := range slices.Sorted(maps.Keys(card.Stats)) {
+		if _, ok := d.stat(name); !ok || slices.Contains(BuiltinStats, name) {
+			errs.add("stats."+name, "is not a stat of %s", d.Name)
+		}
+	}
+
+	return errs
+}
+
+// check validates one value of the stat, reported as field
+func (s StatDefinition) check(errs *fieldErrors, field string, value any) {
+	switch s.Type {
+	case StatTypeInteger:
+		n, ok := asInteger(value)
+		if !ok {
+			errs.add(field, "must be a whole number")
+			return
+		}
+		if (s.Min != nil && n < *s.Min) || (s.Max != nil && n > *s.Max) {
+			errs.add(field, "is %d, outside %s", n, s.describeRange())
+		}
+	case StatTypeString:
+		if _, ok := value.(string); !ok {
+			errs.add(field, "must be a string")
+		}
+	case StatTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			errs.add(field, "must be true or false")
+		}
+	}
+}
+
+// describeRange formats the bounds of an integer stat
+func (s StatDefinition) describeRange() string {
+	switch {
+	case s.Min != nil && s.Max != nil:
+		return fmt.Sprintf("%d to %d", *s.Min, *s.Max)
+	case s.Min != nil:
+		return fmt.Sprintf("%d or more", *s.Min)
+	default:
+		return fmt.Sprintf("%d or less", *s.Max)
+	}
+}
+
+// asInteger converts a stat value to an int. Numbers decoded from JSON are
+// float64, so whole floats count as integers.
+func asInteger(value any) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > math.MaxInt32 {
+			return 0, false
+		}
+		return int(v), true
+	}
+	return 0, false
+}
@@ -13,14 +13,25 @@ type ImageCard struct {
 	Description   string    `json:"description"`
 	FrontImageURL string    `json:"front_image_url"`
 	BackImageURL  string    `json:"back_image_url"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	// Version counts the writes to the card, starting at 1. Storage sets it
+	// and the timestamps; see storage.Storage.
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
-const typeImageCard = "imagecard"
-
 func (c *ImageCard) GetID() uuid.UUID         { return c.ID }
 func (c *ImageCard) GetName() string          { return c.Name }
 func (c *ImageCard) GetFrontImageURL() string { return c.FrontImageURL }
 func (c *ImageCard) GetBackImageURL() string  { return c.BackImageURL }
-func (c *ImageCard) GetCardType() string      { return typeImageCard }
+func (c *ImageCard) GetCardType() string      { return CardTypeImageCard }
+
+// Validate checks the fields of the card
+func (c *ImageCard) Validate() []FieldError {
+	var errs fieldErrors
+	errs.required("name", c.Name, MaxNameLength)
+	errs.maxLength("description", c.Description, MaxDescriptionLength)
+	errs.url("front_image_url", c.FrontImageURL)
+	errs.url("back_image_url", c.BackImageURL)
+	return errs
+}
@@ -14,17 +14,68 @@ type PlayingCard struct {
 	Value         int       `json:"value"` // 1-13 (Ace through King)
 	FrontImageURL string    `json:"front_image_url"`
 	BackImageURL  string    `json:"back_image_url"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	// Storage sets the timestamps; see storage.Storage
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
-const typePlayingCard = "playing-card"
+// Playing card suites. Jokers use SuiteJoker with a Value of 0.
+const (
+	SuiteHearts   = "Hearts"
+	SuiteDiamonds = "Diamonds"
+	SuiteClubs    = "Clubs"
+	SuiteSpades   = "Spades"
+	SuiteJoker    = "Joker"
+)
+
+// StandardSuites lists the four suites of a standard 52-card deck
+var StandardSuites = []string{SuiteHearts, SuiteDiamonds, SuiteClubs, SuiteSpades}
+
+// Suites lists every valid suite, including SuiteJoker
+var Suites = []string{SuiteHearts, SuiteDiamonds, SuiteClubs, SuiteSpades, SuiteJoker}
+
+// NewStandardPlayingCards returns the 52 cards of a standard deck, ordered
+// by suite and then Ace through King, followed by the requested number of
+// jokers. IDs are left unset.
+func NewStandardPlayingCards(jokers int) []PlayingCard {
+	cards := make([]PlayingCard, 0, 52+jokers)
+	for _, suite := range StandardSuites {
+		for value := 1; value <= 13; value++ {
+			cards = append(cards, PlayingCard{Suite: suite, Value: value})
+		}
+	}
+	for i := 0; i < jokers; i++ {
+		cards = append(cards, PlayingCard{Suite: SuiteJoker})
+	}
+	return cards
+}
+
+// Validate checks the fields of the card. Jokers must have a Value of 0.
+func (c *PlayingCard) Validate() []FieldError {
+	var errs fieldErrors
+	errs.oneOf("suite", c.Suite, Suites)
+	if c.Suite == SuiteJoker {
+		if c.Value != 0 {
+			errs.add("value", "must be 0 for jokers")
+		}
+	} else {
+		errs.intRange("value", c.Value, 1, 13)
+	}
+	errs.url("front_image_url", c.FrontImageURL)
+	errs.url("back_image_url", c.BackImageURL)
+	return errs
+}
 
-func (c *PlayingCard) GetID() uuid.UUID         { return c.ID }
-func (c *PlayingCard) GetName() string          { return fmt.Sprintf("%s of %s", c.getValueName(), c.Suite) }
+func (c *PlayingCard) GetID() uuid.UUID { return c.ID }
+func (c *PlayingCard) GetName() string {
+	if c.Suite == SuiteJoker {
+		return SuiteJoker
+	}
+	return fmt.Sprintf("%s of %s", c.getValueName(), c.Suite)
+}
 func (c *PlayingCard) GetFrontImageURL() string { return c.FrontImageURL }
 func (c *PlayingCard) GetBackImageURL() string  { return c.BackImageURL }
-func (c *PlayingCard) GetCardType() string      { return typePlayingCard }
+func (c *PlayingCard) GetCardType() string      { return CardTypePlayingCard }
 
 func (c *PlayingCard) getValueName() string {
 	switch c.Value {
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Registries of canonical card attribute values
+const (
+	RegistryKeywords = "keywords"
+	RegistryColors   = "colors"
+)
+
+// Registries lists the valid registries
+var Registries = []string{RegistryKeywords, RegistryColors}
+
+// RegistryEntry is the canonical form of a keyword or color. Cards naming it
+// by an alias, or in a different case, are saved with the canonical Name.
+type RegistryEntry struct {
+	Name         string   `json:"name"`
+	Aliases      []string `json:"aliases"`
+	ReminderText string   `json:"reminder_text,omitempty"` // e.g. "This can't be blocked except by flyers."
+	IconURL      string   `json:"icon_url,omitempty"`
+	// Storage sets the timestamps; see storage.Storage
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the fields of the entry. Names and aliases are tags, so
+// they fit the same columns as card keywords and colors, and a name cannot
+// contain "/" since it is part of the entry's URL. An alias may not repeat
+// the name or another alias, ignoring case.
+func (e *RegistryEntry) Validate() []FieldError {
+	var errs fieldErrors
+	errs.required("name", e.Name, MaxTagLength)
+	if strings.Contains(e.Name, "/") {
+		errs.add("name", "cannot contain /")
+	}
+	seen := map[string]bool{strings.ToLower(e.Name): true}
+	for i, alias := range e.Aliases {
+		field := fmt.Sprintf("aliases[%d]", i)
+		errs.required(field, alias, MaxTagLength)
+		if alias != "" && seen[strings.ToLower(alias)] {
+			errs.add(field, "repeats the name or another alias")
+		}
+		seen[strings.ToLower(alias)] = true
+	}
+	errs.maxLength("reminder_text", e.ReminderText, MaxDescriptionLength)
+	errs.url("icon_url", e.IconURL)
+	return errs
+}
+
+// Resolver maps the names and aliases of registry entries to canonical
+// names, ignoring case
+type Resolver map[string]*RegistryEntry
+
+// NewResolver builds a Resolver over entries. A name takes precedence over an
+// alias spelled the same way.
+func NewResolver(entries []*RegistryEntry) Resolver {
+	r := make(Resolver, len(entries))
+	for _, entry := range entries {
+		for _, alias := range entry.Aliases {
+			r[strings.ToLower(alias)] = entry
+		}
+	}
+	for _, entry := range entries {
+		r[strings.ToLower(entry.Name)] = entry
+	}
+	return r
+}
+
+// Resolve returns the entry value names or is an alias of, if any
+func (r Resolver) Resolve(value string) (*RegistryEntry, bool) {
+	entry, ok := r[strings.ToLower(value)]
+	return entry, ok
+}
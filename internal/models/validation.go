@@ -0,0 +1,117 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Field limits shared by the card models. They match the columns of the SQL
+// stores: names are VARCHAR(255), and card types, keywords and colors are
+// VARCHAR(64).
+const (
+	MaxNameLength        = 255
+	MaxTagLength         = 64
+	MaxDescriptionLength = 4000
+	MaxURLLength         = 2048
+	MaxStatValue         = 999
+)
+
+// FieldError is a problem with one field of a model. Field is the JSON path
+// of the field, e.g. "printings[1].rarity".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Validator is implemented by models that check their own fields
+type Validator interface {
+	// Validate returns a FieldError for each invalid field, or none
+	Validate() []FieldError
+}
+
+// fieldErrors collects the FieldErrors of one model
+type fieldErrors []FieldError
+
+func (e *fieldErrors) add(field, format string, args ...any) {
+	*e = append(*e, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// required checks that a text field is not blank and fits in max characters
+func (e *fieldErrors) required(field, value string, max int) {
+	if strings.TrimSpace(value) == "" {
+		e.add(field, "is required")
+		return
+	}
+	e.maxLength(field, value, max)
+}
+
+// maxLength checks that an optional text field fits in max characters
+func (e *fieldErrors) maxLength(field, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		e.add(field, "must be at most %d characters", max)
+	}
+}
+
+// intRange checks that a number lies between lo and hi inclusive
+func (e *fieldErrors) intRange(field string, value, lo, hi int) {
+	if value < lo || value > hi {
+		e.add(field, "must be between %d and %d", lo, hi)
+	}
+}
+
+// limit is a count checked by nonNegative
+type limit struct {
+	field string
+	value int
+}
+
+// nonNegative checks that no limit is below zero, reporting whether all are
+// valid
+func (e *fieldErrors) nonNegative(limits ...limit) bool {
+	ok := true
+	for _, l := range limits {
+		if l.value < 0 {
+			e.add(l.field, "cannot be negative")
+			ok = false
+		}
+	}
+	return ok
+}
+
+// oneOf checks that a value is one of allowed
+func (e *fieldErrors) oneOf(field, value string, allowed []string) {
+	if !slices.Contains(allowed, value) {
+		e.add(field, "must be one of: %s", strings.Join(allowed, ", "))
+	}
+}
+
+// url checks that an optional field holds an absolute http or https URL
+func (e *fieldErrors) url(field, value string) {
+	if value == "" {
+		return
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		e.add(field, "must be an http or https URL")
+		return
+	}
+	e.maxLength(field, value, MaxURLLength)
+}
+
+// ValidateURL checks an optional field the way the card models check their
+// image URLs, for requests that copy URLs onto cards they create
+func ValidateURL(field, value string) []FieldError {
+	var errs fieldErrors
+	errs.url(field, value)
+	return errs
+}
+
+// values checks that every entry of a list is a non-blank tag
+func (e *fieldErrors) values(field string, values []string) {
+	for i, value := range values {
+		e.required(fmt.Sprintf("%s[%d]", field, i), value, MaxTagLength)
+	}
+}
@@ -0,0 +1,284 @@
+// Package patch applies partial updates to JSON documents, in either of the
+// two standard formats:
+//
+//   - JSON Merge Patch (RFC 7396), a document holding just the members to
+//     change, where null removes a member and arrays are replaced whole
+//   - JSON Patch (RFC 6902), a list of add, remove, replace, move, copy and
+//     test operations addressed by JSON Pointers (RFC 6901), which can
+//     insert into and remove from arrays
+//
+// Both work on encoded documents so callers can patch any model by
+// marshalling it, patching the result and unmarshalling it again.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Media types of the two patch formats, as sent in Content-Type
+const (
+	MediaTypeMergePatch = "application/merge-patch+json"
+	MediaTypeJSONPatch  = "application/json-patch+json"
+)
+
+// MaxOperations is the most operations a single JSON Patch may hold
+const MaxOperations = 1000
+
+var (
+	// ErrInvalidPatch reports a patch document that is malformed, whatever
+	// it is applied to
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrConflict reports a patch that does not fit the document, e.g. a path
+	// that does not exist or a failed test operation
+	ErrConflict = errors.New("patch does not apply")
+)
+
+// Error reports a patch that could not be applied. Index is the position of
+// the failing JSON Patch operation, or -1 for problems with the patch as a
+// whole. Err is ErrInvalidPatch or ErrConflict.
+type Error struct {
+	Index   int
+	Op      string
+	Path    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Index < 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("operation %d (%s %s): %s", e.Index, e.Op, e.Path, e.Message)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Merge applies an RFC 7396 merge patch to doc and returns the result
+func Merge(doc, mergePatch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := decode(mergePatch)
+	if err != nil {
+		return nil, &Error{Index: -1, Message: "patch is not valid JSON", Err: ErrInvalidPatch}
+	}
+	return json.Marshal(merge(target, patch))
+}
+
+func merge(target, patch any) any {
+	members, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	object, ok := target.(map[string]any)
+	if !ok {
+		object = map[string]any{}
+	}
+	for name, value := range members {
+		if value == nil {
+			delete(object, name)
+		} else {
+			object[name] = merge(object[name], value)
+		}
+	}
+	return object
+}
+
+// operation is one step of a JSON Patch. Value is nil when the member is
+// absent and "null" when it is null.
+type operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Apply applies an RFC 6902 JSON Patch to doc and returns the result. The
+// operations are applied in order, and no result is returned unless all of
+// them succeed.
+func Apply(doc, jsonPatch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []operation
+	if err := json.Unmarshal(jsonPatch, &ops); err != nil {
+		return nil, &Error{Index: -1, Message: "patch must be a JSON array of operations", Err: ErrInvalidPatch}
+	}
+	if len(ops) > MaxOperations {
+		return nil, &Error{Index: -1, Message: fmt.Sprintf("patch has more than %d operations", MaxOperations), Err: ErrInvalidPatch}
+	}
+
+	for i, op := range ops {
+		target, err = op.apply(target)
+		if err != nil {
+			path := ""
+			if op.Path != nil {
+				path = *op.Path
+			}
+			var applyErr *Error
+			if errors.As(err, &applyErr) {
+				applyErr.Index, applyErr.Op, applyErr.Path = i, op.Op, path
+				return nil, applyErr
+			}
+			return nil, err
+		}
+	}
+	return json.Marshal(target)
+}
+
+// apply applies one operation to doc and returns the new document
+func (op operation) apply(doc any) (any, error) {
+	if op.Path == nil {
+		return nil, invalid("missing path")
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, invalid("missing value")
+		}
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, invalid("value is not valid JSON")
+		}
+		switch op.Op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			return replace(doc, path, value)
+		}
+		current, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(current, value) {
+			return nil, conflict("value does not match")
+		}
+		return doc, nil
+
+	case "remove":
+		return remove(doc, path)
+
+	case "move", "copy":
+		if op.From == nil {
+			return nil, invalid("missing from")
+		}
+		from, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			return add(doc, path, deepCopy(value))
+		}
+		if from.isPrefixOf(path) {
+			if len(from) == len(path) {
+				return doc, nil
+			}
+			return nil, invalid("cannot move a value into itself")
+		}
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	}
+	return nil, invalid(fmt.Sprintf("unknown op %q", op.Op))
+}
+
+// decode decodes a JSON value, keeping numbers exact
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return value, nil
+}
+
+// equal compares two decoded JSON values. Numbers are equal if they have the
+// same value however they are written, and object members may be in any
+// order.
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, xok := new(big.Rat).SetString(a.String())
+		y, yok := new(big.Rat).SetString(b.String())
+		return xok && yok && x.Cmp(y) == 0
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for name, value := range a {
+			other, ok := b[name]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// deepCopy copies a decoded JSON value so a copy operation does not alias
+// the original
+func deepCopy(value any) any {
+	switch value := value.(type) {
+	case []any:
+		elems := make([]any, len(value))
+		for i, elem := range value {
+			elems[i] = deepCopy(elem)
+		}
+		return elems
+	case map[string]any:
+		members := make(map[string]any, len(value))
+		for name, member := range value {
+			members[name] = deepCopy(member)
+		}
+		return members
+	default:
+		return value
+	}
+}
+
+func invalid(message string) error {
+	return &Error{Message: message, Err: ErrInvalidPatch}
+}
+
+func conflict(message string) error {
+	return &Error{Message: message, Err: ErrConflict}
+}
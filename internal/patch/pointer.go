@@ -0,0 +1,206 @@
+package patch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pointer is a parsed RFC 6901 JSON Pointer, one unescaped token per level.
+// The empty pointer refers to the whole document.
+type pointer []string
+
+// unescaper decodes the ~1 and ~0 escapes of pointer tokens, in that order
+var unescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func parsePointer(s string) (pointer, error) {
+	if s == "" {
+		return pointer{}, nil
+	}
+	if !strings.HasPrefix(s, "/") {
+		return nil, invalid(fmt.Sprintf("pointer %q must be empty or start with /", s))
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = unescaper.Replace(token)
+	}
+	return tokens, nil
+}
+
+// isPrefixOf reports whether p refers to other or one of its ancestors
+func (p pointer) isPrefixOf(other pointer) bool {
+	if len(p) > len(other) {
+		return false
+	}
+	for i := range p {
+		if p[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// get returns the value p refers to
+func get(doc any, p pointer) (any, error) {
+	for _, token := range p {
+		var err error
+		if doc, err = child(doc, token); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// add inserts value at p, appending to an array for the token "-"
+func add(doc any, p pointer, value any) (any, error) {
+	if len(p) == 0 {
+		return value, nil
+	}
+	return update(doc, p, func(container any, token string) (any, error) {
+		switch container := container.(type) {
+		case map[string]any:
+			container[token] = value
+			return container, nil
+		case []any:
+			if token == "-" {
+				return append(container, value), nil
+			}
+			i, err := index(token, len(container)+1)
+			if err != nil {
+				return nil, err
+			}
+			container = append(container, nil)
+			copy(container[i+1:], container[i:])
+			container[i] = value
+			return container, nil
+		}
+		return nil, notContainer(container)
+	})
+}
+
+// remove deletes the value at p
+func remove(doc any, p pointer) (any, error) {
+	if len(p) == 0 {
+		return nil, invalid("cannot remove the whole document")
+	}
+	return update(doc, p, func(container any, token string) (any, error) {
+		switch container := container.(type) {
+		case map[string]any:
+			if _, ok := container[token]; !ok {
+				return nil, conflict(fmt.Sprintf("member %q does not exist", token))
+			}
+			delete(container, token)
+			return container, nil
+		case []any:
+			i, err := index(token, len(container))
+			if err != nil {
+				return nil, err
+			}
+			return append(container[:i], container[i+1:]...), nil
+		}
+		return nil, notContainer(container)
+	})
+}
+
+// replace sets the existing value at p to value
+func replace(doc any, p pointer, value any) (any, error) {
+	if len(p) == 0 {
+		return value, nil
+	}
+	return update(doc, p, func(container any, token string) (any, error) {
+		switch container := container.(type) {
+		case map[string]any:
+			if _, ok := container[token]; !ok {
+				return nil, conflict(fmt.Sprintf("member %q does not exist", token))
+			}
+			container[token] = value
+			return container, nil
+		case []any:
+			i, err := index(token, len(container))
+			if err != nil {
+				return nil, err
+			}
+			container[i] = value
+			return container, nil
+		}
+		return nil, notContainer(container)
+	})
+}
+
+// update walks doc to the container of the last token of p and replaces
+// that container with the result of fn, which may be a new slice
+func update(doc any, p pointer, fn func(container any, token string) (any, error)) (any, error) {
+	if len(p) == 1 {
+		return fn(doc, p[0])
+	}
+	next, err := child(doc, p[0])
+	if err != nil {
+		return nil, err
+	}
+	next, err = update(next, p[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	switch container := doc.(type) {
+	case map[string]any:
+		container[p[0]] = next
+	case []any:
+		// child has already checked the index
+		i, _ := strconv.Atoi(p[0])
+		container[i] = next
+	}
+	return doc, nil
+}
+
+// child returns the member or element of container named by token
+func child(container any, token string) (any, error) {
+	switch container := container.(type) {
+	case map[string]any:
+		value, ok := container[token]
+		if !ok {
+			return nil, conflict(fmt.Sprintf("member %q does not exist", token))
+		}
+		return value, nil
+	case []any:
+		i, err := index(token, len(container))
+		if err != nil {
+			return nil, err
+		}
+		return container[i], nil
+	}
+	return nil, notContainer(container)
+}
+
+// index parses an array index token, which must be below limit
+func index(token string, limit int) (int, error) {
+	if token == "" || strings.Trim(token, "0123456789") != "" || (len(token) > 1 && token[0] == '0') {
+		return 0, conflict(fmt.Sprintf("%q is not an array index", token))
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i >= limit {
+		return 0, conflict(fmt.Sprintf("index %s is out of range", token))
+	}
+	return i, nil
+}
+
+func notContainer(value any) error {
+	return conflict(fmt.Sprintf("cannot address a member of %s", typeName(value)))
+}
+
+// typeName describes the type of a decoded JSON value
+func typeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case string:
+		return "a string"
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	default:
+		return "a number"
+	}
+}
@@ -0,0 +1,412 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Limits that keep a single query cheap to parse and run
+const (
+	MaxLength = 512
+	maxDepth  = 16
+)
+
+// SyntaxError reports a query that cannot be parsed. Offset is the byte
+// offset of Token, the part of the query at fault.
+type SyntaxError struct {
+	Offset  int
+	Token   string
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("%s at column %d", e.Message, e.Offset+1)
+	}
+	return fmt.Sprintf("%s at column %d (%q)", e.Message, e.Offset+1, e.Token)
+}
+
+// fieldKind groups fields that accept the same operators and values
+type fieldKind int
+
+const (
+	kindText fieldKind = iota
+	kindNumber
+	kindList
+	kindIs
+	kindBool
+)
+
+// fieldNames maps every field name and alias to its field
+var fieldNames = map[string]struct {
+	field Field
+	kind  fieldKind
+}{
+	"name":     {FieldName, kindText},
+	"n":        {FieldName, kindText},
+	"subtitle": {FieldSubtitle, kindText},
+	"sub":      {FieldSubtitle, kindText},
+	"type":     {FieldType, kindText},
+	"t":        {FieldType, kindText},
+	"cost":     {FieldCost, kindNumber},
+	"offense":  {FieldOffense, kindNumber},
+	"off":      {FieldOffense, kindNumber},
+	"o":        {FieldOffense, kindNumber},
+	"defense":  {FieldDefense, kindNumber},
+	"def":      {FieldDefense, kindNumber},
+	"d":        {FieldDefense, kindNumber},
+	"keyword":  {FieldKeywords, kindList},
+	"keywords": {FieldKeywords, kindList},
+	"kw":       {FieldKeywords, kindList},
+	"color":    {FieldColors, kindList},
+	"colors":   {FieldColors, kindList},
+	"c":        {FieldColors, kindList},
+	"is":       {FieldIsResource, kindIs},
+	"resource": {FieldIsResource, kindBool},
+}
+
+// operators in the order they are tried, so "<=" wins over "<"
+var operators = []string{"<=", ">=", "!=", ":", "=", "<", ">"}
+
+type tokenKind int
+
+const (
+	tokTerm tokenKind = iota
+	tokLParen
+	tokRParen
+	tokMinus
+	tokOr
+	tokAnd
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+// Parse parses a search query
+func Parse(input string) (Expr, error) {
+	if len(input) > MaxLength {
+		return nil, &SyntaxError{Offset: MaxLength, Message: fmt.Sprintf("query is longer than %d characters", MaxLength)}
+	}
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, &SyntaxError{Message: "query is empty"}
+	}
+
+	p := &parser{tokens: tokens, end: len(input)}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok, ok := p.peek(); ok {
+		// parseOr only stops early at a closing parenthesis
+		return nil, &SyntaxError{Offset: tok.offset, Token: tok.text, Message: "unexpected closing parenthesis"}
+	}
+	return expr, nil
+}
+
+// lex splits a query into tokens
+func lex(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		switch c := input[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case c == '-':
+			// Terms are consumed whole below, so a "-" here starts a term
+			if i+1 == len(input) || strings.ContainsRune(" \t\n\r)", rune(input[i+1])) {
+				return nil, &SyntaxError{Offset: i, Token: "-", Message: "\"-\" must be followed by the term it negates"}
+			}
+			tokens = append(tokens, token{tokMinus, "-", i})
+			i++
+		default:
+			start := i
+			for i < len(input) && !strings.ContainsRune(" \t\n\r()", rune(input[i])) {
+				if input[i] == '"' {
+					end := strings.IndexByte(input[i+1:], '"')
+					if end < 0 {
+						return nil, &SyntaxError{Offset: i, Token: input[i:], Message: "unterminated quoted string"}
+					}
+					i += end + 1
+				}
+				i++
+			}
+			text := input[start:i]
+			kind := tokTerm
+			switch strings.ToLower(text) {
+			case "or":
+				kind = tokOr
+			case "and":
+				kind = tokAnd
+			}
+			tokens = append(tokens, token{kind, text, start})
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+	// end is the length of the query, where errors about a missing term
+	// point
+	end int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// errMissingTerm reports that a term was expected at the current token
+func (p *parser) errMissingTerm(after token) error {
+	tok, ok := p.peek()
+	if !ok {
+		return &SyntaxError{Offset: p.end, Message: fmt.Sprintf("expected a term after %q", after.text)}
+	}
+	return &SyntaxError{Offset: tok.offset, Token: tok.text, Message: fmt.Sprintf("expected a term after %q", after.text)}
+}
+
+// parseOr parses terms separated by "or"
+func (p *parser) parseOr() (Expr, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []Expr{first}
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind != tokOr {
+			break
+		}
+		p.pos++
+		if next, ok := p.peek(); !ok || next.kind == tokRParen || next.kind == tokOr {
+			return nil, p.errMissingTerm(tok)
+		}
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return Or{Exprs: exprs}, nil
+}
+
+// parseAnd parses a run of terms up to "or", a closing parenthesis or the
+// end of the query
+func (p *parser) parseAnd() (Expr, error) {
+	var exprs []Expr
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind == tokRParen || tok.kind == tokOr {
+			break
+		}
+		if tok.kind == tokAnd {
+			if len(exprs) == 0 {
+				return nil, &SyntaxError{Offset: tok.offset, Token: tok.text, Message: "\"and\" must follow a term"}
+			}
+			p.pos++
+			if next, ok := p.peek(); !ok || next.kind == tokRParen || next.kind == tokOr || next.kind == tokAnd {
+				return nil, p.errMissingTerm(tok)
+			}
+			continue
+		}
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	switch len(exprs) {
+	case 0:
+		tok, ok := p.peek()
+		switch {
+		case !ok:
+			return nil, &SyntaxError{Offset: p.end, Message: "expected a term"}
+		case tok.kind == tokOr:
+			return nil, &SyntaxError{Offset: tok.offset, Token: tok.text, Message: "\"or\" must follow a term"}
+		default:
+			return nil, &SyntaxError{Offset: tok.offset, Token: tok.text, Message: "expected a term before \")\""}
+		}
+	case 1:
+		return exprs[0], nil
+	}
+	return And{Exprs: exprs}, nil
+}
+
+// parseUnary parses a possibly negated term or group
+func (p *parser) parseUnary() (Expr, error) {
+	tok, _ := p.peek()
+	switch tok.kind {
+	case tokMinus:
+		p.pos++
+		if next, ok := p.peek(); !ok || next.kind != tokTerm && next.kind != tokLParen && next.kind != tokMinus {
+			return nil, p.errMissingTerm(tok)
+		}
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: expr}, nil
+
+	case tokLParen:
+		if p.depth == maxDepth {
+			return nil, &SyntaxError{Offset: tok.offset, Token: tok.text, Message: fmt.Sprintf("parentheses are nested more than %d deep", maxDepth)}
+		}
+		p.pos++
+		p.depth++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.depth--
+		if closing, ok := p.peek(); !ok || closing.kind != tokRParen {
+			return nil, &SyntaxError{Offset: tok.offset, Token: tok.text, Message: "missing closing parenthesis"}
+		}
+		p.pos++
+		return expr, nil
+	}
+
+	p.pos++
+	return parseTerm(tok)
+}
+
+// parseTerm parses a single field comparison or bare word
+func parseTerm(tok token) (Expr, error) {
+	text := tok.text
+	nameEnd := 0
+	for nameEnd < len(text) && (text[nameEnd] >= 'a' && text[nameEnd] <= 'z' || text[nameEnd] >= 'A' && text[nameEnd] <= 'Z' || text[nameEnd] == '_') {
+		nameEnd++
+	}
+	var op string
+	for _, candidate := range operators {
+		if nameEnd > 0 && strings.HasPrefix(text[nameEnd:], candidate) {
+			op = candidate
+			break
+		}
+	}
+
+	if op == "" {
+		// A bare word matches names
+		value, err := unquote(tok, text, tok.offset)
+		if err != nil {
+			return nil, err
+		}
+		if value == "" {
+			return nil, &SyntaxError{Offset: tok.offset, Token: text, Message: "empty quoted string"}
+		}
+		return Text{Field: FieldName, Value: value}, nil
+	}
+
+	name := strings.ToLower(text[:nameEnd])
+	field, ok := fieldNames[name]
+	if !ok {
+		return nil, &SyntaxError{Offset: tok.offset, Token: text, Message: fmt.Sprintf("unknown field %q", text[:nameEnd])}
+	}
+	valueOffset := tok.offset + nameEnd + len(op)
+	value, err := unquote(tok, text[nameEnd+len(op):], valueOffset)
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return nil, &SyntaxError{Offset: valueOffset, Token: text, Message: fmt.Sprintf("missing value after %s%s", name, op)}
+	}
+	badOp := func(allowed string) error {
+		return &SyntaxError{Offset: tok.offset + nameEnd, Token: text,
+			Message: fmt.Sprintf("%s does not support %q; use %s", name, op, allowed)}
+	}
+
+	var expr Expr
+	switch field.kind {
+	case kindText:
+		switch op {
+		case ":":
+			expr = Text{Field: field.field, Value: value}
+		case "=", "!=":
+			expr = Text{Field: field.field, Value: value, Exact: true}
+		default:
+			return nil, badOp(`":", "=" or "!="`)
+		}
+
+	case kindNumber:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, &SyntaxError{Offset: valueOffset, Token: text, Message: fmt.Sprintf("%s needs a whole number, not %q", name, value)}
+		}
+		number := Number{Field: field.field, Value: n}
+		switch op {
+		case ":", "=", "!=":
+			number.Op = OpEqual
+		case "<":
+			number.Op = OpLess
+		case "<=":
+			number.Op = OpLessEqual
+		case ">":
+			number.Op = OpGreater
+		case ">=":
+			number.Op = OpGreaterEqual
+		}
+		expr = number
+
+	case kindList:
+		if op != ":" && op != "=" && op != "!=" {
+			return nil, badOp(`":" or "!="`)
+		}
+		expr = Has{Field: field.field, Value: value}
+
+	case kindIs:
+		if op != ":" && op != "=" && op != "!=" {
+			return nil, badOp(`":"`)
+		}
+		if !strings.EqualFold(value, "resource") {
+			return nil, &SyntaxError{Offset: valueOffset, Token: text, Message: fmt.Sprintf("unknown property %q; expected is:resource", value)}
+		}
+		expr = Resource{Value: true}
+
+	case kindBool:
+		if op != ":" && op != "=" && op != "!=" {
+			return nil, badOp(`":"`)
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, &SyntaxError{Offset: valueOffset, Token: text, Message: fmt.Sprintf("%s needs true or false, not %q", name, value)}
+		}
+		expr = Resource{Value: b}
+	}
+
+	if op == "!=" {
+		return Not{Expr: expr}, nil
+	}
+	return expr, nil
+}
+
+// unquote strips the quotes from a value that is entirely quoted. Quotes
+// may only surround a whole value.
+func unquote(tok token, value string, offset int) (string, error) {
+	if !strings.Contains(value, `"`) {
+		return value, nil
+	}
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' || strings.Count(value, `"`) != 2 {
+		return "", &SyntaxError{Offset: offset, Token: tok.text, Message: "quotes must surround the whole value"}
+	}
+	return value[1 : len(value)-1], nil
+}
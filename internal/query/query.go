@@ -0,0 +1,215 @@
+// Package query parses the game card search language, for example
+//
+//	c:red cost<=3 kw:flying o>=2 type:creature
+//
+// into an Expr tree that can be matched against cards in memory or
+// translated into a storage backend's own query language.
+//
+// Terms are separated by spaces and must all match. "or" between terms
+// matches either side, a leading "-" negates a term and parentheses group
+// terms. A term is either a field comparison such as cost>=2 or a bare word,
+// which matches card names. Values containing spaces are quoted:
+// name:"goblin king".
+package query
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/jwebster45206/tcg-api/internal/models"
+)
+
+// Field is a searchable GameCard field
+type Field int
+
+const (
+	FieldName Field = iota
+	FieldSubtitle
+	FieldType
+	FieldCost
+	FieldOffense
+	FieldDefense
+	FieldKeywords
+	FieldColors
+	FieldIsResource
+)
+
+func (f Field) String() string {
+	switch f {
+	case FieldName:
+		return "name"
+	case FieldSubtitle:
+		return "subtitle"
+	case FieldType:
+		return "type"
+	case FieldCost:
+		return "cost"
+	case FieldOffense:
+		return "offense"
+	case FieldDefense:
+		return "defense"
+	case FieldKeywords:
+		return "keyword"
+	case FieldColors:
+		return "color"
+	case FieldIsResource:
+		return "is"
+	}
+	return "unknown"
+}
+
+// Op compares a numeric field with a value
+type Op int
+
+const (
+	OpEqual Op = iota
+	OpLess
+	OpLessEqual
+	OpGreater
+	OpGreaterEqual
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpLess:
+		return "<"
+	case OpLessEqual:
+		return "<="
+	case OpGreater:
+		return ">"
+	case OpGreaterEqual:
+		return ">="
+	}
+	return "="
+}
+
+// Expr is a node of a parsed query
+type Expr interface {
+	// Match reports whether card satisfies the expression
+	Match(card *models.GameCard) bool
+}
+
+// And matches cards that match every expression
+type And struct {
+	Exprs []Expr
+}
+
+// Or matches cards that match at least one expression
+type Or struct {
+	Exprs []Expr
+}
+
+// Not matches cards that do not match Expr
+type Not struct {
+	Expr Expr
+}
+
+// Text matches a text field (name, subtitle or type), ignoring case. Without
+// Exact the field only has to contain Value.
+type Text struct {
+	Field Field
+	Value string
+	Exact bool
+}
+
+// Number compares a numeric field (cost, offense or defense) with Value
+type Number struct {
+	Field Field
+	Op    Op
+	Value int
+}
+
+// Has matches cards whose keywords or colors include Value, ignoring case
+type Has struct {
+	Field Field
+	Value string
+}
+
+// Resource matches cards whose IsResource equals Value
+type Resource struct {
+	Value bool
+}
+
+func (e And) Match(card *models.GameCard) bool {
+	for _, expr := range e.Exprs {
+		if !expr.Match(card) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e Or) Match(card *models.GameCard) bool {
+	for _, expr := range e.Exprs {
+		if expr.Match(card) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Not) Match(card *models.GameCard) bool {
+	return !e.Expr.Match(card)
+}
+
+func (e Text) Match(card *models.GameCard) bool {
+	var field string
+	switch e.Field {
+	case FieldName:
+		field = card.Name
+	case FieldSubtitle:
+		field = card.Subtitle
+	case FieldType:
+		field = card.Type
+	default:
+		return false
+	}
+	if e.Exact {
+		return strings.EqualFold(field, e.Value)
+	}
+	return strings.Contains(strings.ToLower(field), strings.ToLower(e.Value))
+}
+
+func (e Number) Match(card *models.GameCard) bool {
+	var field int
+	switch e.Field {
+	case FieldCost:
+		field = card.Cost
+	case FieldOffense:
+		field = card.Offense
+	case FieldDefense:
+		field = card.Defense
+	default:
+		return false
+	}
+	switch e.Op {
+	case OpLess:
+		return field < e.Value
+	case OpLessEqual:
+		return field <= e.Value
+	case OpGreater:
+		return field > e.Value
+	case OpGreaterEqual:
+		return field >= e.Value
+	}
+	return field == e.Value
+}
+
+func (e Has) Match(card *models.GameCard) bool {
+	var values []string
+	switch e.Field {
+	case FieldKeywords:
+		values = card.Keywords
+	case FieldColors:
+		values = card.Colors
+	default:
+		return false
+	}
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, e.Value)
+	})
+}
+
+func (e Resource) Match(card *models.GameCard) bool {
+	return card.IsResource == e.Value
+}
@@ -0,0 +1,176 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jwebster45206/tcg-api/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Expr
+	}{
+		{"goblin", Text{Field: FieldName, Value: "goblin"}},
+		{`"goblin king"`, Text{Field: FieldName, Value: "goblin king"}},
+		{"c:red cost<=3 kw:flying o>=2 type:creature", And{Exprs: []Expr{
+			Has{Field: FieldColors, Value: "red"},
+			Number{Field: FieldCost, Op: OpLessEqual, Value: 3},
+			Has{Field: FieldKeywords, Value: "flying"},
+			Number{Field: FieldOffense, Op: OpGreaterEqual, Value: 2},
+			Text{Field: FieldType, Value: "creature"},
+		}}},
+		{`name="Goblin King" sub:"of the hill"`, And{Exprs: []Expr{
+			Text{Field: FieldName, Value: "Goblin King", Exact: true},
+			Text{Field: FieldSubtitle, Value: "of the hill"},
+		}}},
+		{"cost:2 d<1 def>-1 offense!=0", And{Exprs: []Expr{
+			Number{Field: FieldCost, Op: OpEqual, Value: 2},
+			Number{Field: FieldDefense, Op: OpLess, Value: 1},
+			Number{Field: FieldDefense, Op: OpGreater, Value: -1},
+			Not{Expr: Number{Field: FieldOffense, Op: OpEqual, Value: 0}},
+		}}},
+		{"c:red or c:blue", Or{Exprs: []Expr{
+			Has{Field: FieldColors, Value: "red"},
+			Has{Field: FieldColors, Value: "blue"},
+		}}},
+		{"t:creature (c:red OR c:blue) -kw:flying", And{Exprs: []Expr{
+			Text{Field: FieldType, Value: "creature"},
+			Or{Exprs: []Expr{
+				Has{Field: FieldColors, Value: "red"},
+				Has{Field: FieldColors, Value: "blue"},
+			}},
+			Not{Expr: Has{Field: FieldKeywords, Value: "flying"}},
+		}}},
+		{"a and b or c", Or{Exprs: []Expr{
+			And{Exprs: []Expr{Text{Field: FieldName, Value: "a"}, Text{Field: FieldName, Value: "b"}}},
+			Text{Field: FieldName, Value: "c"},
+		}}},
+		{"-(is:resource or resource:false)", Not{Expr: Or{Exprs: []Expr{
+			Resource{Value: true},
+			Resource{Value: false},
+		}}}},
+		{"goblin-king", Text{Field: FieldName, Value: "goblin-king"}},
+		{"COST>=1", Number{Field: FieldCost, Op: OpGreaterEqual, Value: 1}},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q):\n got %#v\nwant %#v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantOffset int
+		wantToken  string
+		wantMsg    string
+	}{
+		{"", 0, "", "query is empty"},
+		{"   ", 0, "", "query is empty"},
+		{"c:red foo:bar", 6, "foo:bar", `unknown field "foo"`},
+		{"cost<=x", 6, "cost<=x", `cost needs a whole number, not "x"`},
+		{"kw<3", 2, "kw<3", `kw does not support "<"`},
+		{"type>creature", 4, "type>creature", `type does not support ">"`},
+		{"c:", 2, "c:", "missing value after c:"},
+		{"is:land", 3, "is:land", `unknown property "land"`},
+		{"resource:maybe", 9, "resource:maybe", "resource needs true or false"},
+		{`name:"goblin`, 5, `"goblin`, "unterminated quoted string"},
+		{`name:go"bl"in`, 5, `name:go"bl"in`, "quotes must surround the whole value"},
+		{`""`, 0, `""`, "empty quoted string"},
+		{"(c:red", 0, "(", "missing closing parenthesis"},
+		{"c:red)", 5, ")", "unexpected closing parenthesis"},
+		{"()", 1, ")", `expected a term before ")"`},
+		{"or c:red", 0, "or", `"or" must follow a term`},
+		{"c:red or", 8, "", `expected a term after "or"`},
+		{"c:red or or c:blue", 9, "or", `expected a term after "or"`},
+		{"and c:red", 0, "and", `"and" must follow a term`},
+		{"- c:red", 0, "-", `"-" must be followed by the term it negates`},
+		{"c:red -", 6, "-", `"-" must be followed by the term it negates`},
+		{strings.Repeat("(", maxDepth+1) + "a" + strings.Repeat(")", maxDepth+1), maxDepth, "(", "nested more than"},
+		{strings.Repeat("a ", MaxLength), MaxLength, "", "query is longer than"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.input)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Parse(%q): expected a SyntaxError, got %v", tt.input, err)
+			continue
+		}
+		if syntaxErr.Offset != tt.wantOffset || syntaxErr.Token != tt.wantToken || !strings.Contains(syntaxErr.Message, tt.wantMsg) {
+			t.Errorf("Parse(%q): expected %q at %d (%q), got %q at %d (%q)", tt.input,
+				tt.wantMsg, tt.wantOffset, tt.wantToken, syntaxErr.Message, syntaxErr.Offset, syntaxErr.Token)
+		}
+	}
+}
+
+func TestSyntaxError_Error(t *testing.T) {
+	_, err := Parse("c:red cost<=x")
+	want := `cost needs a whole number, not "x" at column 13 ("cost<=x")`
+	if err == nil || err.Error() != want {
+		t.Errorf("Expected %q, got %v", want, err)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	goblin := &models.GameCard{
+		Name:     "Goblin King",
+		Subtitle: "Lord of the Hill",
+		Type:     "Creature",
+		Cost:     3,
+		Offense:  2,
+		Defense:  2,
+		Keywords: []string{"Haste"},
+		Colors:   []string{"Red"},
+	}
+	land := &models.GameCard{
+		Name:       "Mountain",
+		Type:       "Land",
+		Colors:     []string{"Red"},
+		IsResource: true,
+	}
+
+	tests := []struct {
+		query     string
+		wantMatch []bool // goblin, land
+	}{
+		{"c:red", []bool{true, true}},
+		{"c:RED cost<=3 kw:haste o>=2 type:creature", []bool{true, false}},
+		{"goblin", []bool{true, false}},
+		{"name=goblin", []bool{false, false}},
+		{`name="goblin king"`, []bool{true, false}},
+		{"sub:hill", []bool{true, false}},
+		{"is:resource", []bool{false, true}},
+		{"-is:resource", []bool{true, false}},
+		{"resource:false", []bool{true, false}},
+		{"cost>0 or t:land", []bool{true, true}},
+		{"cost<3", []bool{false, true}},
+		{"cost>3", []bool{false, false}},
+		{"d=2 off:2", []bool{true, false}},
+		{"kw!=haste", []bool{false, true}},
+		{"t:creature (kw:flying or kw:haste)", []bool{true, false}},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		for i, card := range []*models.GameCard{goblin, land} {
+			if got := expr.Match(card); got != tt.wantMatch[i] {
+				t.Errorf("%q on %s: expected %v, got %v", tt.query, card.Name, tt.wantMatch[i], got)
+			}
+		}
+	}
+}
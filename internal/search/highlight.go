@@ -0,0 +1,96 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// Fragments longer than maxFragment bytes are cut to a window starting up to
+// fragmentLead bytes before the first match
+const (
+	maxFragment  = 160
+	fragmentLead = 40
+)
+
+// highlight returns a fragment for each field of doc containing a marked
+// word. marks maps indexed words to how many of their leading runes matched.
+func highlight(doc *Document, marks map[string]int) []Highlight {
+	var highlights []Highlight
+	for _, f := range doc.fields() {
+		var spans [][2]int
+		for _, tok := range tokenize(f.text) {
+			n, ok := marks[tok.term]
+			if !ok {
+				continue
+			}
+			spans = append(spans, [2]int{tok.start, matchEnd(f.text, tok, n)})
+		}
+		if len(spans) > 0 {
+			highlights = append(highlights, Highlight{Field: f.name, Fragment: fragment(f.text, spans)})
+		}
+	}
+	return highlights
+}
+
+// matchEnd returns the offset in text of the end of the first n runes of the
+// lower-cased term of tok. The term can differ from the text in length, in
+// bytes and in runes, so the text is lower-cased a rune at a time until n
+// runes of the term are covered.
+func matchEnd(text string, tok token, n int) int {
+	end := tok.start
+	for n > 0 && end < tok.end {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		n -= utf8.RuneCountInString(strings.ToLower(string(r)))
+		end += size
+	}
+	return end
+}
+
+// fragment marks spans of text, trimming long text to a window around the
+// first span. Spans must be in order and not overlap.
+func fragment(text string, spans [][2]int) string {
+	start, end := 0, len(text)
+	if len(text) > maxFragment {
+		start = max(0, spans[0][0]-fragmentLead)
+		for start > 0 && !utf8.RuneStart(text[start]) {
+			start++
+		}
+		// Start at a word if there is a space before the match
+		if i := strings.IndexByte(text[start:spans[0][0]], ' '); start > 0 && i >= 0 {
+			start += i + 1
+		}
+
+		end = min(len(text), start+maxFragment)
+		for end < len(text) && !utf8.RuneStart(text[end]) {
+			end++
+		}
+		// End at a word, unless that would drop the first match
+		if end < len(text) {
+			if i := strings.LastIndexByte(text[start:end], ' '); i >= 0 && start+i >= spans[0][1] {
+				end = start + i
+			}
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, span := range spans {
+		if span[1] > end {
+			break
+		}
+		b.WriteString(html.EscapeString(text[pos:span[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[span[0]:span[1]]))
+		b.WriteString("</mark>")
+		pos = span[1]
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
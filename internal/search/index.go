@@ -0,0 +1,301 @@
+// Package search keeps an in-process full-text index over card names,
+// subtitles and descriptions.
+//
+// Text is split into lower-case words of letters and digits. A query matches
+// a card when every query word matches some word of the card, either exactly,
+// as a prefix ("gob" finds "goblin") or within a small edit distance ("gobiln"
+// finds "goblin"). Cards are ranked by how well and where each query word
+// matched: name beats subtitle beats description, exact beats prefix beats
+// typo, and rare words count for more than common ones.
+//
+// The index lives in memory and only sees writes made through the Storage
+// returned by WithIndex, so every API instance keeps its own copy, built by
+// Load at startup.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Fields reported in highlights
+const (
+	FieldName        = "name"
+	FieldSubtitle    = "subtitle"
+	FieldDescription = "description"
+)
+
+// fieldWeights is how much a match in each field counts
+var fieldWeights = map[string]float64{
+	FieldName:        3,
+	FieldSubtitle:    2,
+	FieldDescription: 1,
+}
+
+// Match quality by kind. Prefix matches score between prefixQuality and
+// exactQuality depending on how much of the word the prefix covers.
+const (
+	exactQuality  = 1.0
+	prefixQuality = 0.5
+)
+
+// fuzzyQuality is the match quality by number of edits
+var fuzzyQuality = []float64{1: 0.4, 2: 0.2}
+
+// Document is the searchable text of one card
+type Document struct {
+	ID          uuid.UUID
+	CardType    string
+	Name        string
+	Subtitle    string
+	Description string
+}
+
+// field is one piece of a document's text
+type field struct {
+	name, text string
+}
+
+func (d *Document) fields() []field {
+	return []field{
+		{FieldName, d.Name},
+		{FieldSubtitle, d.Subtitle},
+		{FieldDescription, d.Description},
+	}
+}
+
+// Options narrow a search
+type Options struct {
+	// CardType restricts results to one card type if set
+	CardType string
+	// Limit caps the number of results if positive
+	Limit int
+}
+
+// Result is one card found by a search, best first
+type Result struct {
+	ID         uuid.UUID
+	CardType   string
+	Score      float64
+	Highlights []Highlight
+}
+
+// Highlight is a fragment of a matching field with the matched text wrapped
+// in <mark> tags. The rest of the fragment is HTML-escaped, so it is safe to
+// render as-is.
+type Highlight struct {
+	Field    string `json:"field"`
+	Fragment string `json:"fragment"`
+}
+
+// Index is an inverted index from words to the cards containing them. It is
+// safe for concurrent use.
+type Index struct {
+	mu   sync.RWMutex
+	docs map[uuid.UUID]*Document
+	// postings maps each word to the cards containing it, weighted by the
+	// best field it appears in
+	postings map[string]map[uuid.UUID]float64
+	// terms holds the keys of postings in order, for prefix lookups
+	terms []string
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[uuid.UUID]*Document),
+		postings: make(map[string]map[uuid.UUID]float64),
+	}
+}
+
+// Len returns the number of indexed cards
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Put adds a card to the index, replacing any earlier version of it
+func (idx *Index) Put(doc Document) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(doc.ID)
+	idx.docs[doc.ID] = &doc
+	for _, f := range doc.fields() {
+		weight := fieldWeights[f.name]
+		for _, tok := range tokenize(f.text) {
+			posting, ok := idx.postings[tok.term]
+			if !ok {
+				posting = make(map[uuid.UUID]float64)
+				idx.postings[tok.term] = posting
+				i := sort.SearchStrings(idx.terms, tok.term)
+				idx.terms = append(idx.terms, "")
+				copy(idx.terms[i+1:], idx.terms[i:])
+				idx.terms[i] = tok.term
+			}
+			posting[doc.ID] = max(posting[doc.ID], weight)
+		}
+	}
+}
+
+// Remove drops a card from the index. Removing a card that is not indexed
+// does nothing.
+func (idx *Index) Remove(id uuid.UUID) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+}
+
+func (idx *Index) remove(id uuid.UUID) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	delete(idx.docs, id)
+	for _, f := range doc.fields() {
+		for _, tok := range tokenize(f.text) {
+			posting, ok := idx.postings[tok.term]
+			if !ok {
+				continue
+			}
+			delete(posting, id)
+			if len(posting) == 0 {
+				delete(idx.postings, tok.term)
+				i := sort.SearchStrings(idx.terms, tok.term)
+				idx.terms = append(idx.terms[:i], idx.terms[i+1:]...)
+			}
+		}
+	}
+}
+
+// termMatch is an indexed word matched by a query word
+type termMatch struct {
+	term    string
+	quality float64
+	// marked is how many leading runes of the word to highlight
+	marked int
+}
+
+// expand finds the indexed words a query word matches. Callers hold idx.mu.
+func (idx *Index) expand(word string) []termMatch {
+	var matches []termMatch
+	wordLen := utf8.RuneCountInString(word)
+
+	// Exact and prefix matches are adjacent in terms
+	for i := sort.SearchStrings(idx.terms, word); i < len(idx.terms) && strings.HasPrefix(idx.terms[i], word); i++ {
+		term := idx.terms[i]
+		termLen := utf8.RuneCountInString(term)
+		quality := exactQuality
+		if term != word {
+			quality = prefixQuality * (1 + float64(wordLen)/float64(termLen))
+		}
+		matches = append(matches, termMatch{term: term, quality: quality, marked: wordLen})
+	}
+
+	edits := maxEdits(word)
+	if edits == 0 {
+		return matches
+	}
+	for _, term := range idx.terms {
+		if strings.HasPrefix(term, word) {
+			continue
+		}
+		if d := editDistance(word, term, edits); d <= edits {
+			matches = append(matches, termMatch{term: term, quality: fuzzyQuality[d], marked: utf8.RuneCountInString(term)})
+		}
+	}
+	return matches
+}
+
+// Search returns the cards matching every word of text, best first. Text
+// without any words matches nothing.
+func (idx *Index) Search(text string, opts Options) []Result {
+	var words []string
+	seen := make(map[string]bool)
+	for _, tok := range tokenize(text) {
+		if !seen[tok.term] {
+			seen[tok.term] = true
+			words = append(words, tok.term)
+		}
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	total := float64(len(idx.docs))
+	var scores map[uuid.UUID]float64
+	// marks records, per card, which indexed words to highlight and how much
+	// of each
+	marks := make(map[uuid.UUID]map[string]int)
+	for i, word := range words {
+		best := make(map[uuid.UUID]float64)
+		for _, m := range idx.expand(word) {
+			posting := idx.postings[m.term]
+			idf := 1 + math.Log(total/float64(len(posting)))
+			for id, weight := range posting {
+				if i > 0 {
+					if _, ok := scores[id]; !ok {
+						continue
+					}
+				}
+				if opts.CardType != "" && idx.docs[id].CardType != opts.CardType {
+					continue
+				}
+				best[id] = max(best[id], m.quality*weight*idf)
+				if marks[id] == nil {
+					marks[id] = make(map[string]int)
+				}
+				marks[id][m.term] = max(marks[id][m.term], m.marked)
+			}
+		}
+
+		// A card must match every word
+		if i == 0 {
+			scores = best
+		} else {
+			for id := range scores {
+				if score, ok := best[id]; ok {
+					scores[id] += score
+				} else {
+					delete(scores, id)
+				}
+			}
+		}
+		if len(scores) == 0 {
+			return nil
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for id, score := range scores {
+		results = append(results, Result{ID: id, CardType: idx.docs[id].CardType, Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		nameA, nameB := strings.ToLower(idx.docs[a.ID].Name), strings.ToLower(idx.docs[b.ID].Name)
+		if nameA != nameB {
+			return nameA < nameB
+		}
+		return a.ID.String() < b.ID.String()
+	})
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+
+	for i := range results {
+		results[i].Highlights = highlight(idx.docs[results[i].ID], marks[results[i].ID])
+	}
+	return results
+}
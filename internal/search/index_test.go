@@ -0,0 +1,209 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+)
+
+func TestTokenize(t *testing.T) {
+	var got []string
+	for _, tok := range tokenize("Goblin's Hill-Fort, ÉLAN 2") {
+		got = append(got, tok.term)
+	}
+	want := []string{"goblin", "s", "hill", "fort", "élan", "2"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		max  int
+		want int
+	}{
+		{"goblin", "goblin", 1, 0},
+		{"gobiln", "goblin", 2, 2},
+		{"goblon", "goblin", 1, 1},
+		{"gobin", "goblin", 1, 1},
+		{"goblin", "dragon", 2, 3},
+		{"go", "goblin", 2, 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.max); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d): expected %d, got %d", tt.a, tt.b, tt.max, tt.want, got)
+		}
+	}
+}
+
+// newTestIndex indexes a handful of cards and returns their IDs by name
+func newTestIndex() (*Index, map[string]uuid.UUID) {
+	idx := NewIndex()
+	ids := make(map[string]uuid.UUID)
+	for _, doc := range []Document{
+		{CardType: models.CardTypeGameCard, Name: "Goblin King", Subtitle: "Lord of the Hill"},
+		{CardType: models.CardTypeGameCard, Name: "Goblin Raider"},
+		{CardType: models.CardTypeGameCard, Name: "Hill Giant", Subtitle: "Goblin Hunter"},
+		{CardType: models.CardTypeGameCard, Name: "Sky Angel"},
+		{CardType: models.CardTypeImageCard, Name: "Mountain Pass", Description: "A narrow trail where goblins <ambush> travellers"},
+	} {
+		doc.ID = uuid.New()
+		ids[doc.Name] = doc.ID
+		idx.Put(doc)
+	}
+	return idx, ids
+}
+
+func resultNames(results []Result, ids map[string]uuid.UUID) []string {
+	names := make(map[uuid.UUID]string)
+	for name, id := range ids {
+		names[id] = name
+	}
+	var got []string
+	for _, r := range results {
+		got = append(got, names[r.ID])
+	}
+	return got
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx, ids := newTestIndex()
+
+	tests := []struct {
+		text string
+		opts Options
+		want []string
+	}{
+		// Name matches rank above subtitle and description matches, and ties
+		// go by name
+		{"goblin", Options{}, []string{"Goblin King", "Goblin Raider", "Hill Giant", "Mountain Pass"}},
+		// Prefixes match, and cover descriptions
+		{"gob", Options{}, []string{"Goblin King", "Goblin Raider", "Hill Giant", "Mountain Pass"}},
+		// Every word must match, and rarer words count for more
+		{"goblin hill", Options{}, []string{"Hill Giant", "Goblin King"}},
+		{"GOBLIN raid", Options{}, []string{"Goblin Raider"}},
+		// Typos are tolerated in longer words
+		{"gobln", Options{}, []string{"Goblin King", "Goblin Raider", "Hill Giant"}},
+		{"mountian", Options{}, []string{"Mountain Pass"}},
+		{"skz", Options{}, nil},
+		{"angle", Options{}, nil},
+		{"gob", Options{CardType: models.CardTypeImageCard}, []string{"Mountain Pass"}},
+		{"goblin", Options{Limit: 1}, []string{"Goblin King"}},
+		{"dragon", Options{}, nil},
+		{"  --  ", Options{}, nil},
+	}
+	for _, tt := range tests {
+		got := resultNames(idx.Search(tt.text, tt.opts), ids)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Search(%q, %+v): expected %v, got %v", tt.text, tt.opts, tt.want, got)
+		}
+	}
+}
+
+func TestIndex_SearchHighlights(t *testing.T) {
+	idx, ids := newTestIndex()
+
+	results := idx.Search("gob lord", Options{})
+	if len(results) != 1 || results[0].ID != ids["Goblin King"] {
+		t.Fatalf("Expected only Goblin King, got %+v", results)
+	}
+	want := []Highlight{
+		{Field: FieldName, Fragment: "<mark>Gob</mark>lin King"},
+		{Field: FieldSubtitle, Fragment: "<mark>Lord</mark> of the Hill"},
+	}
+	if !slices.Equal(results[0].Highlights, want) {
+		t.Errorf("Expected %v, got %v", want, results[0].Highlights)
+	}
+
+	// Descriptions are HTML-escaped around the marks
+	results = idx.Search("goblins", Options{CardType: models.CardTypeImageCard})
+	if len(results) != 1 {
+		t.Fatalf("Expected one result, got %+v", results)
+	}
+	wantFragment := "A narrow trail where <mark>goblins</mark> &lt;ambush&gt; travellers"
+	if h := results[0].Highlights; len(h) != 1 || h[0].Field != FieldDescription || h[0].Fragment != wantFragment {
+		t.Errorf("Expected description fragment %q, got %v", wantFragment, h)
+	}
+
+	// Fuzzy matches highlight the whole word
+	results = idx.Search("raidr", Options{})
+	if len(results) != 1 || results[0].Highlights[0].Fragment != "Goblin <mark>Raider</mark>" {
+		t.Errorf("Expected Raider highlighted, got %+v", results)
+	}
+}
+
+func TestIndex_SearchHighlights_NonASCII(t *testing.T) {
+	idx := NewIndex()
+	for _, name := range []string{"İstanbul Wyrm", "Ærøskøbing Ålfar", "Straße der Drachen"} {
+		idx.Put(Document{ID: uuid.New(), CardType: models.CardTypeGameCard, Name: name})
+	}
+
+	// Lower-casing changes the length of İ, Æ and Å in bytes, so the marks
+	// must be placed on the original text
+	tests := []struct {
+		text, want string
+	}{
+		{"ista", "<mark>İsta</mark>nbul Wyrm"},
+		{"ÆRØ", "<mark>Ærø</mark>skøbing Ålfar"},
+		{"ålfar", "Ærøskøbing <mark>Ålfar</mark>"},
+		{"strase", "<mark>Straße</mark> der Drachen"},
+	}
+	for _, tt := range tests {
+		results := idx.Search(tt.text, Options{})
+		if len(results) != 1 || len(results[0].Highlights) != 1 || results[0].Highlights[0].Fragment != tt.want {
+			t.Errorf("Search(%q): expected %q, got %+v", tt.text, tt.want, results)
+		}
+	}
+}
+
+func TestFragment_LongText(t *testing.T) {
+	text := strings.Repeat("lorem ipsum ", 20) + "dragon " + strings.Repeat("dolor sit ", 20)
+	start := strings.Index(text, "dragon")
+	got := fragment(text, [][2]int{{start, start + len("dragon")}})
+
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("Expected ellipses on both ends, got %q", got)
+	}
+	if !strings.Contains(got, " <mark>dragon</mark> ") {
+		t.Errorf("Expected the match to be marked, got %q", got)
+	}
+	if trimmed := strings.Trim(got, "…"); strings.HasPrefix(trimmed, " ") || strings.HasSuffix(trimmed, " ") ||
+		len(trimmed) > maxFragment+len("<mark></mark>") {
+		t.Errorf("Expected a word-aligned window of at most %d bytes, got %q", maxFragment, got)
+	}
+}
+
+func TestIndex_PutAndRemove(t *testing.T) {
+	idx, ids := newTestIndex()
+	king := ids["Goblin King"]
+
+	// Re-putting a card replaces its old text
+	idx.Put(Document{ID: king, CardType: models.CardTypeGameCard, Name: "Orc Warlord"})
+	if got := resultNames(idx.Search("king", Options{}), ids); got != nil {
+		t.Errorf("Expected the old name to be gone, got %v", got)
+	}
+	if got := idx.Search("warlord", Options{}); len(got) != 1 || got[0].ID != king {
+		t.Errorf("Expected the new name to be found, got %+v", got)
+	}
+
+	idx.Remove(king)
+	idx.Remove(uuid.New())
+	if got := idx.Search("warlord", Options{}); got != nil {
+		t.Errorf("Expected no results after Remove, got %+v", got)
+	}
+	if idx.Len() != len(ids)-1 {
+		t.Errorf("Expected %d cards, got %d", len(ids)-1, idx.Len())
+	}
+	for _, term := range idx.terms {
+		if term == "warlord" || term == "king" {
+			t.Errorf("Expected %q to be dropped from the term list", term)
+		}
+	}
+	if !slices.IsSorted(idx.terms) || len(idx.terms) != len(idx.postings) {
+		t.Errorf("Expected terms to mirror postings in order, got %v", idx.terms)
+	}
+}
@@ -0,0 +1,132 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// CardTypes returns the card types the index covers
+func CardTypes() []string {
+	return []string{models.CardTypeGameCard, models.CardTypeImageCard}
+}
+
+// GameCardDocument returns the searchable text of a game card
+func GameCardDocument(card *models.GameCard) Document {
+	return Document{
+		ID:       card.ID,
+		CardType: models.CardTypeGameCard,
+		Name:     card.Name,
+		Subtitle: card.Subtitle,
+	}
+}
+
+// ImageCardDocument returns the searchable text of an image card
+func ImageCardDocument(card *models.ImageCard) Document {
+	return Document{
+		ID:          card.ID,
+		CardType:    models.CardTypeImageCard,
+		Name:        card.Name,
+		Description: card.Description,
+	}
+}
+
+// Load indexes every game and image card already in s
+func (idx *Index) Load(ctx context.Context, s storage.Storage) error {
+	gameCards, err := s.ListGameCards(ctx, "gamecard")
+	if err != nil {
+		return fmt.Errorf("list game cards: %w", err)
+	}
+	imageCards, err := s.ListImageCards(ctx)
+	if err != nil {
+		return fmt.Errorf("list image cards: %w", err)
+	}
+
+	for _, card := range gameCards {
+		idx.Put(GameCardDocument(card))
+	}
+	for _, card := range imageCards {
+		idx.Put(ImageCardDocument(card))
+	}
+	return nil
+}
+
+// WithIndex returns a Storage that keeps idx in step with the game and image
+// cards written through it, and passes everything else to base. Closing it
+// closes base.
+func WithIndex(base storage.Storage, idx *Index) storage.Storage {
+	return &indexedStorage{
+		Storage: base,
+		index:   idx,
+	}
+}
+
+// indexedStorage updates an Index after successful card writes
+type indexedStorage struct {
+	storage.Storage
+	index *Index
+}
+
+func (s *indexedStorage) CreateGameCard(ctx context.Context, card models.GameCard) (*models.GameCard, error) {
+	created, err := s.Storage.CreateGameCard(ctx, card)
+	if err != nil {
+		return nil, err
+	}
+	s.index.Put(GameCardDocument(created))
+	return created, nil
+}
+
+func (s *indexedStorage) UpdateGameCard(ctx context.Context, card models.GameCard) (*models.GameCard, error) {
+	updated, err := s.Storage.UpdateGameCard(ctx, card)
+	if err != nil {
+		return nil, err
+	}
+	s.index.Put(GameCardDocument(updated))
+	return updated, nil
+}
+
+func (s *indexedStorage) DeleteGameCard(ctx context.Context, id uuid.UUID, version int) error {
+	if err := s.Storage.DeleteGameCard(ctx, id, version); err != nil {
+		return err
+	}
+	s.index.Remove(id)
+	return nil
+}
+
+func (s *indexedStorage) CreateImageCard(ctx context.Context, card models.ImageCard) (*models.ImageCard, error) {
+	created, err := s.Storage.CreateImageCard(ctx, card)
+	if err != nil {
+		return nil, err
+	}
+	s.index.Put(ImageCardDocument(created))
+	return created, nil
+}
+
+func (s *indexedStorage) UpdateImageCard(ctx context.Context, card models.ImageCard) (*models.ImageCard, error) {
+	updated, err := s.Storage.UpdateImageCard(ctx, card)
+	if err != nil {
+		return nil, err
+	}
+	s.index.Put(ImageCardDocument(updated))
+	return updated, nil
+}
+
+func (s *indexedStorage) DeleteImageCard(ctx context.Context, id uuid.UUID, version int) error {
+	if err := s.Storage.DeleteImageCard(ctx, id, version); err != nil {
+		return err
+	}
+	s.index.Remove(id)
+	return nil
+}
+
+// Close closes the underlying store if it holds resources
+func (s *indexedStorage) Close() error {
+	if closer, ok := s.Storage.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+	"github.com/jwebster45206/tcg-api/internal/storage/storagetest"
+)
+
+func TestWithIndex_Conformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		return WithIndex(storage.NewMockStorage(), NewIndex())
+	})
+}
+
+func TestWithIndex_KeepsIndexInSync(t *testing.T) {
+	ctx := context.Background()
+	idx := NewIndex()
+	sto := WithIndex(storage.NewMockStorage(), idx)
+
+	card, err := sto.CreateGameCard(ctx, models.GameCard{Name: "Goblin King", Type: "Creature"})
+	if err != nil {
+		t.Fatalf("CreateGameCard: %v", err)
+	}
+	image, err := sto.CreateImageCard(ctx, models.ImageCard{Name: "Mountain Pass", Description: "Goblin country"})
+	if err != nil {
+		t.Fatalf("CreateImageCard: %v", err)
+	}
+	if got := idx.Search("goblin", Options{}); len(got) != 2 || got[0].ID != card.ID || got[1].ID != image.ID {
+		t.Fatalf("Expected both new cards, got %+v", got)
+	}
+
+	card.Name = "Orc Warlord"
+	if _, err := sto.UpdateGameCard(ctx, *card); err != nil {
+		t.Fatalf("UpdateGameCard: %v", err)
+	}
+	image.Description = "Quiet country"
+	if _, err := sto.UpdateImageCard(ctx, *image); err != nil {
+		t.Fatalf("UpdateImageCard: %v", err)
+	}
+	if got := idx.Search("goblin", Options{}); got != nil {
+		t.Errorf("Expected updates to replace the indexed text, got %+v", got)
+	}
+	if got := idx.Search("warlord", Options{}); len(got) != 1 || got[0].ID != card.ID {
+		t.Errorf("Expected the updated name to be found, got %+v", got)
+	}
+
+	if err := sto.DeleteGameCard(ctx, card.ID, 0); err != nil {
+		t.Fatalf("DeleteGameCard: %v", err)
+	}
+	if err := sto.DeleteImageCard(ctx, image.ID, 0); err != nil {
+		t.Fatalf("DeleteImageCard: %v", err)
+	}
+	if idx.Len() != 0 {
+		t.Errorf("Expected deletes to empty the index, got %d cards", idx.Len())
+	}
+
+	// Failed writes leave the index alone
+	if _, err := sto.UpdateGameCard(ctx, models.GameCard{ID: card.ID, Name: "Ghost"}); err == nil {
+		t.Fatal("Expected updating a deleted card to fail")
+	}
+	if idx.Len() != 0 {
+		t.Errorf("Expected a failed update not to index anything, got %d cards", idx.Len())
+	}
+}
+
+func TestIndex_Load(t *testing.T) {
+	ctx := context.Background()
+	base := storage.NewMockStorage()
+	if _, err := base.CreateGameCard(ctx, models.GameCard{Name: "Goblin King"}); err != nil {
+		t.Fatalf("CreateGameCard: %v", err)
+	}
+	if _, err := base.CreateImageCard(ctx, models.ImageCard{Name: "Goblin Camp"}); err != nil {
+		t.Fatalf("CreateImageCard: %v", err)
+	}
+	if _, err := base.CreatePlayingCard(ctx, models.PlayingCard{Suite: models.SuiteHearts, Value: 13}); err != nil {
+		t.Fatalf("CreatePlayingCard: %v", err)
+	}
+
+	idx := NewIndex()
+	if err := idx.Load(ctx, base); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if idx.Len() != 2 {
+		t.Errorf("Expected the game and image card to be indexed, got %d cards", idx.Len())
+	}
+	if got := idx.Search("goblin", Options{CardType: models.CardTypeGameCard}); len(got) != 1 {
+		t.Errorf("Expected one game card, got %+v", got)
+	}
+}
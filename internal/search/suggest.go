@@ -0,0 +1,83 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Suggest returns up to limit of names that look like name, closest first,
+// for "did you mean" hints. Names are compared by their lower-case words, so
+// case and punctuation do not matter. A name is suggested if it is within a
+// few typos of name, one for every three letters, or if every word of name
+// starts one of its words ("wyrm" suggests "Ancient Wyrm").
+func Suggest(name string, names []string, limit int) []string {
+	query := tokenize(name)
+	if len(query) == 0 {
+		return nil
+	}
+	key := joinTerms(query)
+	edits := utf8.RuneCountInString(key) / 3
+
+	type suggestion struct {
+		name     string
+		distance int
+	}
+	var found []suggestion
+	seen := make(map[string]bool)
+	for _, candidate := range names {
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+		words := tokenize(candidate)
+		// A distance of edits+1 ranks word matches after every typo
+		distance := editDistance(key, joinTerms(words), edits)
+		if distance > edits && !prefixesWords(query, words) {
+			continue
+		}
+		found = append(found, suggestion{candidate, distance})
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		a, b := found[i], found[j]
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		return strings.ToLower(a.name) < strings.ToLower(b.name)
+	})
+	if limit > 0 && len(found) > limit {
+		found = found[:limit]
+	}
+	suggestions := make([]string, len(found))
+	for i, s := range found {
+		suggestions[i] = s.name
+	}
+	return suggestions
+}
+
+// joinTerms joins the terms of tokens with single spaces
+func joinTerms(tokens []token) string {
+	terms := make([]string, len(tokens))
+	for i, tok := range tokens {
+		terms[i] = tok.term
+	}
+	return strings.Join(terms, " ")
+}
+
+// prefixesWords reports whether every query token is a prefix of some word
+func prefixesWords(query, words []token) bool {
+	for _, q := range query {
+		found := false
+		for _, word := range words {
+			if strings.HasPrefix(word.term, q.term) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
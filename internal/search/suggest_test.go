@@ -0,0 +1,29 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	names := []string{"Fire Bolt", "Fire Ball", "Ancient Wyrm", "Goblin Guide", "Wyrmling", "Fire Bolt"}
+	tests := []struct {
+		name  string
+		limit int
+		want  []string
+	}{
+		{"Fire Blot", 0, []string{"Fire Bolt", "Fire Ball"}},
+		{"fire-bolt", 0, []string{"Fire Bolt", "Fire Ball"}},
+		{"Fire Bolt", 1, []string{"Fire Bolt"}},
+		{"wyrm", 0, []string{"Ancient Wyrm", "Wyrmling"}},
+		{"Goblin Gide", 0, []string{"Goblin Guide"}},
+		{"Dragon", 0, []string{}},
+		{"", 0, nil},
+	}
+	for _, tt := range tests {
+		got := Suggest(tt.name, names, tt.limit)
+		if !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+			t.Errorf("Suggest(%q, %d): expected %q, got %q", tt.name, tt.limit, tt.want, got)
+		}
+	}
+}
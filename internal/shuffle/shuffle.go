@@ -0,0 +1,72 @@
+// Package shuffle implements reproducible, verifiable deck shuffles.
+//
+// A shuffle is a Fisher–Yates pass driven by a ChaCha8 stream keyed from a
+// seed string, so the same seed and input order always produce the same
+// result. The algorithm is pinned by the Algorithm identifier, which is
+// recorded alongside every shuffle so old results can be replayed after the
+// engine changes.
+package shuffle
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	mathrand "math/rand/v2"
+)
+
+// Algorithm identifies the current shuffle implementation. Bump the version
+// whenever the output for a given seed would change.
+const Algorithm = "fisher-yates-chacha8-v1"
+
+// NewSeed returns a fresh random seed suitable for a single shuffle
+func NewSeed() string {
+	var b [32]byte
+	// crypto/rand.Read never returns an error
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Shuffle reorders items in place using the given seed
+func Shuffle[T any](seed string, items []T) {
+	src := mathrand.NewChaCha8(sha256.Sum256([]byte(seed)))
+	for i := len(items) - 1; i > 0; i-- {
+		j := bounded(src, uint64(i+1))
+		items[i], items[j] = items[j], items[i]
+	}
+}
+
+// Commit returns the public commitment for a seed: the hex encoded SHA-256
+// of the seed. Publishing it before dealing binds the server to the seed
+// without revealing it.
+func Commit(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify reports whether a revealed seed matches a published commitment
+func Verify(seed, commitment string) bool {
+	return subtle.ConstantTimeCompare([]byte(Commit(seed)), []byte(commitment)) == 1
+}
+
+// Combine mixes a committed server seed with a seed chosen by the players, so
+// neither side alone controls the final order
+func Combine(serverSeed, clientSeed string) string {
+	if clientSeed == "" {
+		return serverSeed
+	}
+	return serverSeed + ":" + clientSeed
+}
+
+// bounded returns a uniformly distributed value in [0, n) by rejection
+// sampling. It is implemented here rather than using rand.IntN so that the
+// output is independent of the Go standard library version.
+func bounded(src *mathrand.ChaCha8, n uint64) uint64 {
+	limit := ^uint64(0) - (^uint64(0) % n)
+	for {
+		v := src.Uint64()
+		if v < limit {
+			return v % n
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package shuffle
+
+import (
+	"slices"
+	"testing"
+)
+
+func deck(n int) []int {
+	items := make([]int, n)
+	for i := range items {
+		items[i] = i
+	}
+	return items
+}
+
+func TestShuffle_Deterministic(t *testing.T) {
+	a := deck(52)
+	b := deck(52)
+	Shuffle("bug-report-1234", a)
+	Shuffle("bug-report-1234", b)
+
+	if !slices.Equal(a, b) {
+		t.Errorf("Expected identical order for the same seed, got %v and %v", a, b)
+	}
+	if slices.Equal(a, deck(52)) {
+		t.Error("Expected shuffle to change the order")
+	}
+
+	c := deck(52)
+	Shuffle("bug-report-1235", c)
+	if slices.Equal(a, c) {
+		t.Error("Expected different seeds to produce different orders")
+	}
+}
+
+func TestShuffle_KnownVector(t *testing.T) {
+	// Pins the output of the current Algorithm. If this fails the shuffle
+	// output has changed and Algorithm must get a new version.
+	items := deck(10)
+	Shuffle("tcg-api", items)
+
+	want := []int{0, 4, 6, 1, 2, 5, 3, 9, 8, 7}
+	if !slices.Equal(items, want) {
+		t.Errorf("Shuffle output changed for %s: got %v want %v", Algorithm, items, want)
+	}
+}
+
+func TestShuffle_Permutation(t *testing.T) {
+	items := deck(100)
+	Shuffle(NewSeed(), items)
+
+	sorted := slices.Clone(items)
+	slices.Sort(sorted)
+	if !slices.Equal(sorted, deck(100)) {
+		t.Errorf("Shuffle lost or duplicated items: %v", items)
+	}
+}
+
+func TestCommitVerify(t *testing.T) {
+	seed := NewSeed()
+	commitment := Commit(seed)
+
+	if !Verify(seed, commitment) {
+		t.Error("Expected seed to verify against its own commitment")
+	}
+	if Verify(NewSeed(), commitment) {
+		t.Error("Expected a different seed to fail verification")
+	}
+	if Combine(seed, "") != seed {
+		t.Error("Expected an empty client seed to leave the server seed unchanged")
+	}
+	if Combine(seed, "players") == seed {
+		t.Error("Expected a client seed to change the combined seed")
+	}
+}
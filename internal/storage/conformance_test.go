@@ -0,0 +1,58 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jwebster45206/tcg-api/internal/config"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+	"github.com/jwebster45206/tcg-api/internal/storage/storagetest"
+)
+
+func TestMockStorage_Conformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		return storage.NewMockStorage()
+	})
+}
+
+func TestSQLiteStorage_Conformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		sto, err := storage.NewSQLiteStorage(context.Background(), config.SQLiteConfig{
+			Path: filepath.Join(t.TempDir(), "tcg.db"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = closeStorage(sto) })
+		return sto
+	})
+}
+
+// TestMySQLStorage_Conformance runs against the database named by
+// TCG_TEST_MYSQL_DSN and is skipped when it is not set
+func TestMySQLStorage_Conformance(t *testing.T) {
+	storagetest.Run(t, storage.NewTestMySQLStorage)
+}
+
+func TestRedisDeckStates_Conformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		server := miniredis.RunT(t)
+		states, err := storage.NewRedisDeckStateStore(context.Background(), config.RedisConfig{Addr: server.Addr()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sto := storage.WithDeckStateStore(storage.NewMockStorage(), states)
+		t.Cleanup(func() { _ = closeStorage(sto) })
+		return sto
+	})
+}
+
+func closeStorage(sto storage.Storage) error {
+	if closer, ok := sto.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
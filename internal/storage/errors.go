@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+)
+
+// Kinds of storage failure. Every backend reports failures so that
+// errors.Is matches one of these, letting callers react without knowing
+// which backend is in use.
+var (
+	// ErrNotFound means no record has the requested ID
+	ErrNotFound = errors.New("not found")
+	// ErrConflict means the change clashes with existing data, such as a
+	// duplicate ID, or with a concurrent change
+	ErrConflict = errors.New("conflict")
+	// ErrValidation means the request itself is invalid and retrying it
+	// unchanged will fail again
+	ErrValidation = errors.New("validation failed")
+	// ErrUnavailable means the backend could not be reached; the same request
+	// may succeed later
+	ErrUnavailable = errors.New("storage unavailable")
+)
+
+// Error is a storage failure of a particular kind with a message suitable
+// for clients. errors.Is reports true for its Kind and for anything its
+// wrapped Err matches.
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Is reports whether target is the kind of this error
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// alreadyExists reports a create with an ID that is already taken
+func alreadyExists(what string) error {
+	return &Error{Kind: ErrConflict, Message: what + " already exists"}
+}
+
+// translateError converts a failure to reach the backend into ErrUnavailable.
+// Errors that already have a kind, and other errors, are returned unchanged.
+// Backends call it through a deferred call at the top of each method.
+func translateError(err *error, isUnavailable func(err error) bool) {
+	if *err == nil {
+		return
+	}
+	for _, kind := range []error{ErrNotFound, ErrConflict, ErrValidation, ErrUnavailable} {
+		if errors.Is(*err, kind) {
+			return
+		}
+	}
+	if isConnectionError(*err) || (isUnavailable != nil && isUnavailable(*err)) {
+		*err = &Error{Kind: ErrUnavailable, Message: "storage unavailable", Err: *err}
+	}
+}
+
+// isConnectionError reports errors that mean a backend could not be reached,
+// whatever the backend
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
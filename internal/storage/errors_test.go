@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestErrorKinds(t *testing.T) {
+	err := fmt.Errorf("list widgets: %w", alreadyExists("widget"))
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected %v to be ErrConflict", err)
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected %v not to be ErrNotFound", err)
+	}
+	if err.Error() != "list widgets: widget already exists" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+
+	if !errors.Is(ErrUnsupportedCardType, ErrValidation) {
+		t.Error("Expected ErrUnsupportedCardType to be ErrValidation")
+	}
+	if !errors.Is(ErrDeckStateContention, ErrConflict) {
+		t.Error("Expected ErrDeckStateContention to be ErrConflict")
+	}
+}
+
+func TestTranslateError(t *testing.T) {
+	errOther := errors.New("syntax error")
+	tests := []struct {
+		name            string
+		err             error
+		wantUnavailable bool
+	}{
+		{"nil", nil, false},
+		{"bad connection", driver.ErrBadConn, true},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), true},
+		{"redis closed", redis.ErrClosed, true},
+		{"not found", ErrNotFound, false},
+		{"other", errOther, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.err
+			translateError(&err, isRedisUnavailable)
+			if got := errors.Is(err, ErrUnavailable); got != tt.wantUnavailable {
+				t.Errorf("Expected unavailable %v, got %v", tt.wantUnavailable, err)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Expected the original error to stay wrapped, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRedisDeckStateStore_Unavailable(t *testing.T) {
+	store, server := newTestRedisStore(t, 0)
+	server.Close()
+
+	if _, err := store.GetDeckState(context.Background(), uuid.New()); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable once Redis is down, got %v", err)
+	}
+}
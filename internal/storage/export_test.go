@@ -0,0 +1,9 @@
+package storage
+
+import "testing"
+
+// NewTestMySQLStorage exposes newTestMySQLStorage to the conformance tests in
+// package storage_test
+func NewTestMySQLStorage(t *testing.T) Storage {
+	return newTestMySQLStorage(t)
+}
@@ -2,15 +2,14 @@ package storage
 
 import (
 	"context"
-	"errors"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/google/uuid"
 	"github.com/jwebster45206/tcg-api/internal/models"
-)
-
-var (
-	ErrNotFound = errors.New("not found")
+	"github.com/jwebster45206/tcg-api/internal/query"
 )
 
 // MockStorage implements Storage interface for testing and development
@@ -19,6 +18,12 @@ type MockStorage struct {
 	gameCards  map[uuid.UUID]*models.GameCard
 	decks      map[uuid.UUID]*models.Deck
 	imageCards map[uuid.UUID]*models.ImageCard
+	deckStates map[uuid.UUID]*models.DeckState
+	cardSets   map[string]*models.CardSet
+	games      map[uuid.UUID]*models.GameDefinition
+	registries map[string]map[string]*models.RegistryEntry
+
+	playingCards map[uuid.UUID]*models.PlayingCard
 }
 
 // NewMockStorage creates a new MockStorage instance with some sample data
@@ -27,6 +32,15 @@ func NewMockStorage() Storage {
 		gameCards:  make(map[uuid.UUID]*models.GameCard),
 		decks:      make(map[uuid.UUID]*models.Deck),
 		imageCards: make(map[uuid.UUID]*models.ImageCard),
+		deckStates: make(map[uuid.UUID]*models.DeckState),
+		cardSets:   make(map[string]*models.CardSet),
+		games:      make(map[uuid.UUID]*models.GameDefinition),
+		registries: make(map[string]map[string]*models.RegistryEntry),
+
+		playingCards: make(map[uuid.UUID]*models.PlayingCard),
+	}
+	for _, registry := range models.Registries {
+		storage.registries[registry] = make(map[string]*models.RegistryEntry)
 	}
 
 	// Add some sample cards for development
@@ -50,13 +64,54 @@ func (m *MockStorage) ListGameCards(ctx context.Context, cardType string) ([]*mo
 		cards := make([]*models.GameCard, 0, len(m.gameCards))
 		for _, card := range m.gameCards {
 			// Create a copy to avoid modifying the original
-			cardCopy := *card
-			cards = append(cards, &cardCopy)
+			cards = append(cards, copyGameCard(card))
 		}
 		return cards, nil
 	default:
-		return nil, errors.New("unsupported card type")
+		return nil, ErrUnsupportedCardType
+	}
+}
+
+// ListGameCardsPage returns one page of the game cards matching filter
+func (m *MockStorage) ListGameCardsPage(ctx context.Context, filter GameCardFilter, page PageRequest) (*Page[*models.GameCard], error) {
+	q, err := parsePageRequest(page, SortByName, SortByCost, SortByCreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cards := []*models.GameCard{}
+	for _, card := range m.gameCards {
+		if filter.Matches(card) {
+			cards = append(cards, copyGameCard(card))
+		}
+	}
+	return paginate(cards, q, func(card *models.GameCard) pagePosition {
+		return gameCardPosition(card, q.sort)
+	}), nil
+}
+
+// SearchGameCards returns one page of the game cards matching expr
+func (m *MockStorage) SearchGameCards(ctx context.Context, expr query.Expr, page PageRequest) (*Page[*models.GameCard], error) {
+	q, err := parsePageRequest(page, SortByName, SortByCost, SortByCreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cards := []*models.GameCard{}
+	for _, card := range m.gameCards {
+		if expr.Match(card) {
+			cards = append(cards, copyGameCard(card))
+		}
 	}
+	return paginate(cards, q, func(card *models.GameCard) pagePosition {
+		return gameCardPosition(card, q.sort)
+	}), nil
 }
 
 // GetGameCard returns a specific card by ID and type
@@ -69,8 +124,7 @@ func (m *MockStorage) GetGameCard(ctx context.Context, id uuid.UUID) (*models.Ga
 		return nil, ErrNotFound
 	}
 	// Return a copy to avoid modifying the original
-	cardCopy := *card
-	return &cardCopy, nil
+	return copyGameCard(card), nil
 }
 
 // CreateGameCard adds a new card to storage
@@ -85,14 +139,20 @@ func (m *MockStorage) CreateGameCard(ctx context.Context, card models.GameCard)
 
 	// Check if card already exists
 	if _, exists := m.gameCards[card.ID]; exists {
-		return nil, errors.New("card already exists")
+		return nil, alreadyExists("card")
+	}
+	if err := m.checkPrintingSets(card); err != nil {
+		return nil, err
 	}
 
+	card.Version = 1
+	card.CreatedAt = now()
+	card.UpdatedAt = card.CreatedAt
+
 	// Store a copy to avoid external modifications
-	cardCopy := card
-	m.gameCards[card.ID] = &cardCopy
+	m.gameCards[card.ID] = copyGameCard(&card)
 
-	return &cardCopy, nil
+	return copyGameCard(&card), nil
 }
 
 // UpdateGameCard updates an existing card in storage
@@ -101,25 +161,50 @@ func (m *MockStorage) UpdateGameCard(ctx context.Context, card models.GameCard)
 	defer m.mu.Unlock()
 
 	// Check if card exists
-	if _, exists := m.gameCards[card.ID]; !exists {
+	stored, exists := m.gameCards[card.ID]
+	if !exists {
 		return nil, ErrNotFound
 	}
+	if err := checkVersion(stored.Version, card.Version); err != nil {
+		return nil, err
+	}
+	if err := m.checkPrintingSets(card); err != nil {
+		return nil, err
+	}
+
+	card.Version = stored.Version + 1
+	card.CreatedAt = stored.CreatedAt
+	card.UpdatedAt = now()
 
 	// Store a copy to avoid external modifications
-	cardCopy := card
-	m.gameCards[card.ID] = &cardCopy
+	m.gameCards[card.ID] = copyGameCard(&card)
 
-	return &cardCopy, nil
+	return copyGameCard(&card), nil
+}
+
+// checkPrintingSets returns ErrUnknownCardSet unless every printing of card
+// is in a stored set, as the foreign key on printings does in SQL storage
+func (m *MockStorage) checkPrintingSets(card models.GameCard) error {
+	for _, printing := range card.Printings {
+		if _, exists := m.cardSets[printing.SetCode]; !exists {
+			return ErrUnknownCardSet
+		}
+	}
+	return nil
 }
 
 // DeleteGameCard removes a card from storage
-func (m *MockStorage) DeleteGameCard(ctx context.Context, id uuid.UUID) error {
+func (m *MockStorage) DeleteGameCard(ctx context.Context, id uuid.UUID, version int) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	// Check if card exists
-	if _, exists := m.gameCards[id]; !exists {
+	card, exists := m.gameCards[id]
+	if !exists {
 		return ErrNotFound
 	}
+	if err := checkVersion(card.Version, version); err != nil {
+		return err
+	}
 	delete(m.gameCards, id)
 	return nil
 }
@@ -135,8 +220,7 @@ func (m *MockStorage) ListDecks(ctx context.Context, ownerID *uuid.UUID) ([]*mod
 	for _, deck := range m.decks {
 		if ownerID == nil || (deck.OwnerID != nil && *deck.OwnerID == *ownerID) {
 			// Create a copy to avoid modifying the original
-			deckCopy := *deck
-			decks = append(decks, &deckCopy)
+			decks = append(decks, copyDeck(deck))
 		}
 	}
 
@@ -154,8 +238,7 @@ func (m *MockStorage) GetDeck(ctx context.Context, id uuid.UUID) (*models.Deck,
 	}
 
 	// Return a copy to avoid modifying the original
-	deckCopy := *deck
-	return &deckCopy, nil
+	return copyDeck(deck), nil
 }
 
 // CreateDeck adds a new deck to storage
@@ -170,14 +253,18 @@ func (m *MockStorage) CreateDeck(ctx context.Context, deck models.Deck) (*models
 
 	// Check if deck already exists
 	if _, exists := m.decks[deck.ID]; exists {
-		return nil, errors.New("deck already exists")
+		return nil, alreadyExists("deck")
 	}
 
+	normalizeDeck(&deck)
+	deck.Version = 1
+	deck.CreatedAt = now()
+	deck.UpdatedAt = deck.CreatedAt
+
 	// Store a copy to avoid external modifications
-	deckCopy := deck
-	m.decks[deck.ID] = &deckCopy
+	m.decks[deck.ID] = copyDeck(&deck)
 
-	return &deckCopy, nil
+	return copyDeck(&deck), nil
 }
 
 // UpdateDeck updates an existing deck in storage
@@ -186,26 +273,38 @@ func (m *MockStorage) UpdateDeck(ctx context.Context, deck models.Deck) (*models
 	defer m.mu.Unlock()
 
 	// Check if deck exists
-	if _, exists := m.decks[deck.ID]; !exists {
+	stored, exists := m.decks[deck.ID]
+	if !exists {
 		return nil, ErrNotFound
 	}
+	if err := checkVersion(stored.Version, deck.Version); err != nil {
+		return nil, err
+	}
+
+	normalizeDeck(&deck)
+	deck.Version = stored.Version + 1
+	deck.CreatedAt = stored.CreatedAt
+	deck.UpdatedAt = now()
 
 	// Store a copy to avoid external modifications
-	deckCopy := deck
-	m.decks[deck.ID] = &deckCopy
+	m.decks[deck.ID] = copyDeck(&deck)
 
-	return &deckCopy, nil
+	return copyDeck(&deck), nil
 }
 
 // DeleteDeck removes a deck from storage
-func (m *MockStorage) DeleteDeck(ctx context.Context, id uuid.UUID) error {
+func (m *MockStorage) DeleteDeck(ctx context.Context, id uuid.UUID, version int) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	// Check if deck exists
-	if _, exists := m.decks[id]; !exists {
+	deck, exists := m.decks[id]
+	if !exists {
 		return ErrNotFound
 	}
+	if err := checkVersion(deck.Version, version); err != nil {
+		return err
+	}
 
 	delete(m.decks, id)
 	return nil
@@ -222,14 +321,18 @@ func (m *MockStorage) CreateImageCard(ctx context.Context, imageCard models.Imag
 
 	// Check if image card already exists
 	if _, exists := m.imageCards[imageCard.ID]; exists {
-		return nil, errors.New("image card already exists")
+		return nil, alreadyExists("image card")
 	}
 
+	imageCard.Version = 1
+	imageCard.CreatedAt = now()
+	imageCard.UpdatedAt = imageCard.CreatedAt
+
 	// Store a copy to avoid external modifications
 	imageCopy := imageCard
 	m.imageCards[imageCard.ID] = &imageCopy
 
-	return &imageCopy, nil
+	return &imageCard, nil
 }
 
 func (m *MockStorage) GetImageCard(ctx context.Context, id uuid.UUID) (*models.ImageCard, error) {
@@ -251,25 +354,37 @@ func (m *MockStorage) UpdateImageCard(ctx context.Context, imageCard models.Imag
 	defer m.mu.Unlock()
 
 	// Check if image card exists
-	if _, exists := m.imageCards[imageCard.ID]; !exists {
+	stored, exists := m.imageCards[imageCard.ID]
+	if !exists {
 		return nil, ErrNotFound
 	}
+	if err := checkVersion(stored.Version, imageCard.Version); err != nil {
+		return nil, err
+	}
+
+	imageCard.Version = stored.Version + 1
+	imageCard.CreatedAt = stored.CreatedAt
+	imageCard.UpdatedAt = now()
 
 	// Store a copy to avoid external modifications
 	imageCopy := imageCard
 	m.imageCards[imageCard.ID] = &imageCopy
 
-	return &imageCopy, nil
+	return &imageCard, nil
 }
 
-func (m *MockStorage) DeleteImageCard(ctx context.Context, id uuid.UUID) error {
+func (m *MockStorage) DeleteImageCard(ctx context.Context, id uuid.UUID, version int) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	// Check if image card exists
-	if _, exists := m.imageCards[id]; !exists {
+	imageCard, exists := m.imageCards[id]
+	if !exists {
 		return ErrNotFound
 	}
+	if err := checkVersion(imageCard.Version, version); err != nil {
+		return err
+	}
 
 	delete(m.imageCards, id)
 	return nil
@@ -287,3 +402,593 @@ func (m *MockStorage) ListImageCards(ctx context.Context) ([]*models.ImageCard,
 	}
 	return imageCards, nil
 }
+
+// ListImageCardsPage returns one page of image cards
+func (m *MockStorage) ListImageCardsPage(ctx context.Context, page PageRequest) (*Page[*models.ImageCard], error) {
+	q, err := parsePageRequest(page, SortByName, SortByCreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	imageCards := make([]*models.ImageCard, 0, len(m.imageCards))
+	for _, imageCard := range m.imageCards {
+		imageCopy := *imageCard
+		imageCards = append(imageCards, &imageCopy)
+	}
+	return paginate(imageCards, q, func(card *models.ImageCard) pagePosition {
+		return imageCardPosition(card, q.sort)
+	}), nil
+}
+
+// CardSet operations
+
+// ListCardSets returns all card sets ordered by code
+func (m *MockStorage) ListCardSets(ctx context.Context) ([]*models.CardSet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sets := make([]*models.CardSet, 0, len(m.cardSets))
+	for _, set := range m.cardSets {
+		// Create a copy to avoid modifying the original
+		setCopy := *set
+		sets = append(sets, &setCopy)
+	}
+	slices.SortFunc(sets, func(a, b *models.CardSet) int { return strings.Compare(a.Code, b.Code) })
+	return sets, nil
+}
+
+// GetCardSet returns a specific card set by code
+func (m *MockStorage) GetCardSet(ctx context.Context, code string) (*models.CardSet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set, exists := m.cardSets[code]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	// Return a copy to avoid modifying the original
+	setCopy := *set
+	return &setCopy, nil
+}
+
+// CreateCardSet adds a new card set to storage
+func (m *MockStorage) CreateCardSet(ctx context.Context, set models.CardSet) (*models.CardSet, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check if card set already exists
+	if _, exists := m.cardSets[set.Code]; exists {
+		return nil, alreadyExists("card set")
+	}
+
+	set.CreatedAt = now()
+	set.UpdatedAt = set.CreatedAt
+
+	// Store a copy to avoid external modifications
+	setCopy := set
+	m.cardSets[set.Code] = &setCopy
+
+	return &set, nil
+}
+
+// UpdateCardSet updates an existing card set in storage
+func (m *MockStorage) UpdateCardSet(ctx context.Context, set models.CardSet) (*models.CardSet, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check if card set exists
+	stored, exists := m.cardSets[set.Code]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	set.CreatedAt = stored.CreatedAt
+	set.UpdatedAt = now()
+
+	// Store a copy to avoid external modifications
+	setCopy := set
+	m.cardSets[set.Code] = &setCopy
+
+	return &set, nil
+}
+
+// DeleteCardSet removes a card set that no card is printed in
+func (m *MockStorage) DeleteCardSet(ctx context.Context, code string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check if card set exists
+	if _, exists := m.cardSets[code]; !exists {
+		return ErrNotFound
+	}
+
+	filter := GameCardFilter{Set: code}
+	for _, card := range m.gameCards {
+		if filter.Matches(card) {
+			return ErrCardSetInUse
+		}
+	}
+
+	delete(m.cardSets, code)
+	return nil
+}
+
+// GameDefinition operations
+
+// ListGameDefinitions returns all game definitions ordered by name
+func (m *MockStorage) ListGameDefinitions(ctx context.Context) ([]*models.GameDefinition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	games := make([]*models.GameDefinition, 0, len(m.games))
+	for _, game := range m.games {
+		// Create a copy to avoid modifying the original
+		games = append(games, copyGameDefinition(game))
+	}
+	slices.SortFunc(games, func(a, b *models.GameDefinition) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+	return games, nil
+}
+
+// GetGameDefinition returns a specific game definition by ID
+func (m *MockStorage) GetGameDefinition(ctx context.Context, id uuid.UUID) (*models.GameDefinition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	game, exists := m.games[id]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	// Return a copy to avoid modifying the original
+	return copyGameDefinition(game), nil
+}
+
+// CreateGameDefinition adds a new game definition to storage
+func (m *MockStorage) CreateGameDefinition(ctx context.Context, game models.GameDefinition) (*models.GameDefinition, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Generate a new ID if not provided
+	if game.ID == uuid.Nil {
+		game.ID = uuid.New()
+	}
+
+	// Check if game definition already exists
+	if _, exists := m.games[game.ID]; exists {
+		return nil, alreadyExists("game definition")
+	}
+
+	game.CreatedAt = now()
+	game.UpdatedAt = game.CreatedAt
+
+	// Store a copy to avoid external modifications
+	m.games[game.ID] = copyGameDefinition(&game)
+
+	return copyGameDefinition(&game), nil
+}
+
+// UpdateGameDefinition updates an existing game definition in storage
+func (m *MockStorage) UpdateGameDefinition(ctx context.Context, game models.GameDefinition) (*models.GameDefinition, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check if game definition exists
+	stored, exists := m.games[game.ID]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	game.CreatedAt = stored.CreatedAt
+	game.UpdatedAt = now()
+
+	// Store a copy to avoid external modifications
+	m.games[game.ID] = copyGameDefinition(&game)
+
+	return copyGameDefinition(&game), nil
+}
+
+// DeleteGameDefinition removes a game definition that no card belongs to
+func (m *MockStorage) DeleteGameDefinition(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check if game definition exists
+	if _, exists := m.games[id]; !exists {
+		return ErrNotFound
+	}
+
+	filter := GameCardFilter{GameID: &id}
+	for _, card := range m.gameCards {
+		if filter.Matches(card) {
+			return ErrGameDefinitionInUse
+		}
+	}
+
+	delete(m.games, id)
+	return nil
+}
+
+// Registry operations
+
+// ListRegistryEntries returns all entries of a registry ordered by name
+func (m *MockStorage) ListRegistryEntries(ctx context.Context, registry string) ([]*models.RegistryEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries, ok := m.registries[registry]
+	if !ok {
+		return nil, ErrUnknownRegistry
+	}
+	list := make([]*models.RegistryEntry, 0, len(entries))
+	for _, entry := range entries {
+		// Create a copy to avoid modifying the original
+		list = append(list, copyRegistryEntry(entry))
+	}
+	slices.SortFunc(list, func(a, b *models.RegistryEntry) int { return strings.Compare(a.Name, b.Name) })
+	return list, nil
+}
+
+// GetRegistryEntry returns a specific registry entry by name
+func (m *MockStorage) GetRegistryEntry(ctx context.Context, registry, name string) (*models.RegistryEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries, ok := m.registries[registry]
+	if !ok {
+		return nil, ErrUnknownRegistry
+	}
+	entry, exists := entries[name]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	// Return a copy to avoid modifying the original
+	return copyRegistryEntry(entry), nil
+}
+
+// CreateRegistryEntry adds a new entry to a registry
+func (m *MockStorage) CreateRegistryEntry(ctx context.Context, registry string, entry models.RegistryEntry) (*models.RegistryEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries, ok := m.registries[registry]
+	if !ok {
+		return nil, ErrUnknownRegistry
+	}
+
+	// Check if entry already exists
+	if _, exists := entries[entry.Name]; exists {
+		return nil, alreadyExists("registry entry")
+	}
+
+	entry.CreatedAt = now()
+	entry.UpdatedAt = entry.CreatedAt
+
+	// Store a copy to avoid external modifications
+	entries[entry.Name] = copyRegistryEntry(&entry)
+
+	return copyRegistryEntry(&entry), nil
+}
+
+// UpdateRegistryEntry updates an existing registry entry
+func (m *MockStorage) UpdateRegistryEntry(ctx context.Context, registry string, entry models.RegistryEntry) (*models.RegistryEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries, ok := m.registries[registry]
+	if !ok {
+		return nil, ErrUnknownRegistry
+	}
+
+	// Check if entry exists
+	stored, exists := entries[entry.Name]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	entry.CreatedAt = stored.CreatedAt
+	entry.UpdatedAt = now()
+
+	// Store a copy to avoid external modifications
+	entries[entry.Name] = copyRegistryEntry(&entry)
+
+	return copyRegistryEntry(&entry), nil
+}
+
+// DeleteRegistryEntry removes an entry from a registry
+func (m *MockStorage) DeleteRegistryEntry(ctx context.Context, registry, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries, ok := m.registries[registry]
+	if !ok {
+		return ErrUnknownRegistry
+	}
+
+	// Check if entry exists
+	if _, exists := entries[name]; !exists {
+		return ErrNotFound
+	}
+
+	delete(entries, name)
+	return nil
+}
+
+// PlayingCard operations
+
+// ListPlayingCards returns all playing cards
+func (m *MockStorage) ListPlayingCards(ctx context.Context) ([]*models.PlayingCard, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cards := make([]*models.PlayingCard, 0, len(m.playingCards))
+	for _, card := range m.playingCards {
+		// Create a copy to avoid modifying the original
+		cardCopy := *card
+		cards = append(cards, &cardCopy)
+	}
+	return cards, nil
+}
+
+// GetPlayingCard returns a specific playing card by ID
+func (m *MockStorage) GetPlayingCard(ctx context.Context, id uuid.UUID) (*models.PlayingCard, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	card, exists := m.playingCards[id]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	// Return a copy to avoid modifying the original
+	cardCopy := *card
+	return &cardCopy, nil
+}
+
+// CreatePlayingCard adds a new playing card to storage
+func (m *MockStorage) CreatePlayingCard(ctx context.Context, card models.PlayingCard) (*models.PlayingCard, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Generate a new ID if not provided
+	if card.ID == uuid.Nil {
+		card.ID = uuid.New()
+	}
+
+	// Check if playing card already exists
+	if _, exists := m.playingCards[card.ID]; exists {
+		return nil, alreadyExists("playing card")
+	}
+
+	card.CreatedAt = now()
+	card.UpdatedAt = card.CreatedAt
+
+	// Store a copy to avoid external modifications
+	cardCopy := card
+	m.playingCards[card.ID] = &cardCopy
+
+	return &card, nil
+}
+
+// UpdatePlayingCard updates an existing playing card in storage
+func (m *MockStorage) UpdatePlayingCard(ctx context.Context, card models.PlayingCard) (*models.PlayingCard, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check if playing card exists
+	stored, exists := m.playingCards[card.ID]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	card.CreatedAt = stored.CreatedAt
+	card.UpdatedAt = now()
+
+	// Store a copy to avoid external modifications
+	cardCopy := card
+	m.playingCards[card.ID] = &cardCopy
+
+	return &card, nil
+}
+
+// DeletePlayingCard removes a playing card from storage
+func (m *MockStorage) DeletePlayingCard(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check if playing card exists
+	if _, exists := m.playingCards[id]; !exists {
+		return ErrNotFound
+	}
+
+	delete(m.playingCards, id)
+	return nil
+}
+
+// DeckState operations
+
+// GetDeckState returns a specific deck state by ID
+func (m *MockStorage) GetDeckState(ctx context.Context, id uuid.UUID) (*models.DeckState, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	state, exists := m.deckStates[id]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	// Return a copy to avoid modifying the original
+	return copyDeckState(state), nil
+}
+
+// CreateDeckState adds a new deck state to storage
+func (m *MockStorage) CreateDeckState(ctx context.Context, state models.DeckState) (*models.DeckState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Generate a new ID if not provided
+	if state.ID == uuid.Nil {
+		state.ID = uuid.New()
+	}
+
+	// Check if deck state already exists
+	if _, exists := m.deckStates[state.ID]; exists {
+		return nil, alreadyExists("deck state")
+	}
+
+	// Store a copy to avoid external modifications
+	m.deckStates[state.ID] = copyDeckState(&state)
+
+	return copyDeckState(&state), nil
+}
+
+// UpdateDeckState updates an existing deck state in storage
+func (m *MockStorage) UpdateDeckState(ctx context.Context, state models.DeckState) (*models.DeckState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check if deck state exists
+	if _, exists := m.deckStates[state.ID]; !exists {
+		return nil, ErrNotFound
+	}
+
+	// Store a copy to avoid external modifications
+	m.deckStates[state.ID] = copyDeckState(&state)
+
+	return copyDeckState(&state), nil
+}
+
+// ModifyDeckState applies fn to a deck state while holding the storage lock
+func (m *MockStorage) ModifyDeckState(ctx context.Context, id uuid.UUID, fn func(state *models.DeckState) error) (*models.DeckState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, exists := m.deckStates[id]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	// Work on a copy so a failed fn leaves the stored state untouched
+	modified := copyDeckState(state)
+	if err := fn(modified); err != nil {
+		return nil, err
+	}
+	modified.ID = id
+	m.deckStates[id] = copyDeckState(modified)
+
+	return modified, nil
+}
+
+// DeleteDeckState removes a deck state from storage
+func (m *MockStorage) DeleteDeckState(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check if deck state exists
+	if _, exists := m.deckStates[id]; !exists {
+		return ErrNotFound
+	}
+
+	delete(m.deckStates, id)
+	return nil
+}
+
+// copyGameCard returns a deep copy of a game card
+func copyGameCard(card *models.GameCard) *models.GameCard {
+	cardCopy := *card
+	cardCopy.Keywords = append([]string{}, card.Keywords...)
+	cardCopy.Colors = append([]string{}, card.Colors...)
+	cardCopy.Printings = append([]models.Printing{}, card.Printings...)
+	if card.GameID != nil {
+		gameID := *card.GameID
+		cardCopy.GameID = &gameID
+	}
+	cardCopy.Stats = maps.Clone(card.Stats)
+	return &cardCopy
+}
+
+// copyGameDefinition returns a deep copy of a game definition
+func copyGameDefinition(game *models.GameDefinition) *models.GameDefinition {
+	gameCopy := *game
+	gameCopy.CardTypes = append([]string{}, game.CardTypes...)
+	gameCopy.Colors = append([]string{}, game.Colors...)
+	gameCopy.Keywords = append([]string{}, game.Keywords...)
+	gameCopy.Stats = make([]models.StatDefinition, len(game.Stats))
+	for i, stat := range game.Stats {
+		if stat.Min != nil {
+			lo := *stat.Min
+			stat.Min = &lo
+		}
+		if stat.Max != nil {
+			hi := *stat.Max
+			stat.Max = &hi
+		}
+		gameCopy.Stats[i] = stat
+	}
+	if game.Formats != nil {
+		gameCopy.Formats = make([]models.Format, len(game.Formats))
+		for i, format := range game.Formats {
+			format.Banned = slices.Clone(format.Banned)
+			format.Restricted = slices.Clone(format.Restricted)
+			format.Colors = slices.Clone(format.Colors)
+			format.Sets = slices.Clone(format.Sets)
+			gameCopy.Formats[i] = format
+		}
+	}
+	return &gameCopy
+}
+
+// copyRegistryEntry returns a deep copy of a registry entry
+func copyRegistryEntry(entry *models.RegistryEntry) *models.RegistryEntry {
+	entryCopy := *entry
+	entryCopy.Aliases = append([]string{}, entry.Aliases...)
+	return &entryCopy
+}
+
+// copyDeck returns a deep copy of a deck
+func copyDeck(deck *models.Deck) *models.Deck {
+	deckCopy := *deck
+	deckCopy.Entries = append([]models.DeckEntry{}, deck.Entries...)
+	deckCopy.Cards = append([]uuid.UUID{}, deck.Cards...)
+	if deck.OwnerID != nil {
+		ownerID := *deck.OwnerID
+		deckCopy.OwnerID = &ownerID
+	}
+	if deck.SleeveImageURL != nil {
+		sleeveImageURL := *deck.SleeveImageURL
+		deckCopy.SleeveImageURL = &sleeveImageURL
+	}
+	if deck.BackImageURL != nil {
+		backImageURL := *deck.BackImageURL
+		deckCopy.BackImageURL = &backImageURL
+	}
+	return &deckCopy
+}
+
+// copyDeckState returns a deep copy of a deck state. The piles are reordered
+// in place by shuffles, so they must never be shared with callers.
+func copyDeckState(state *models.DeckState) *models.DeckState {
+	stateCopy := *state
+	stateCopy.DrawPile = append([]uuid.UUID{}, state.DrawPile...)
+	stateCopy.Drawn = append([]uuid.UUID{}, state.Drawn...)
+	stateCopy.Discard = append([]uuid.UUID{}, state.Discard...)
+	if state.LastShuffle != nil {
+		record := *state.LastShuffle
+		record.Input = append([]uuid.UUID{}, state.LastShuffle.Input...)
+		stateCopy.LastShuffle = &record
+	}
+	if state.Commitment != nil {
+		commitment := *state.Commitment
+		stateCopy.Commitment = &commitment
+	}
+	return &stateCopy
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jwebster45206/tcg-api/internal/config"
+)
+
+// MySQL error numbers the storage layer reacts to
+const (
+	mysqlErrTooManyConnections = 1040
+	mysqlErrDuplicateEntry     = 1062
+	mysqlErrRowIsReferenced    = 1451
+	mysqlErrNoReferencedRow    = 1452
+)
+
+// NewMySQLStorage connects to the MySQL database described by cfg. The
+// migrations in internal/migrations must already be applied.
+func NewMySQLStorage(ctx context.Context, cfg config.MySQLConfig) (Storage, error) {
+	db, err := sql.Open("mysql", MySQLDSN(cfg))
+	if err != nil {
+		return nil, err
+	}
+	db.SetConnMaxLifetime(5 * time.Minute)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return newMySQLStorage(db), nil
+}
+
+// MySQLDSN builds a go-sql-driver/mysql data source name from cfg
+func MySQLDSN(cfg config.MySQLConfig) string {
+	driverCfg := mysql.NewConfig()
+	driverCfg.User = cfg.User
+	driverCfg.Passwd = cfg.Password
+	driverCfg.Net = "tcp"
+	driverCfg.Addr = net.JoinHostPort(cfg.Host, cfg.Port)
+	driverCfg.DBName = cfg.DBName
+	driverCfg.ParseTime = true
+	driverCfg.Loc = time.UTC
+	// Report matched rather than changed rows, so an UPDATE that leaves a
+	// row unchanged is not mistaken for a missing row
+	driverCfg.ClientFoundRows = true
+	return driverCfg.FormatDSN()
+}
+
+// newMySQLStorage wraps an open MySQL connection pool
+func newMySQLStorage(db *sql.DB) *sqlStorage {
+	return &sqlStorage{
+		db: db,
+		isDuplicateKey: func(err error) bool {
+			var mysqlErr *mysql.MySQLError
+			return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+		},
+		isForeignKey: func(err error) bool {
+			var mysqlErr *mysql.MySQLError
+			return errors.As(err, &mysqlErr) &&
+				(mysqlErr.Number == mysqlErrRowIsReferenced || mysqlErr.Number == mysqlErrNoReferencedRow)
+		},
+		isUnavailable: func(err error) bool {
+			var mysqlErr *mysql.MySQLError
+			if errors.As(err, &mysqlErr) {
+				return mysqlErr.Number == mysqlErrTooManyConnections
+			}
+			return errors.Is(err, mysql.ErrInvalidConn)
+		},
+		lockForUpdate: " FOR UPDATE",
+	}
+}
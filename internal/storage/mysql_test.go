@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/jwebster45206/tcg-api/internal/migrations"
+)
+
+// newTestMySQLStorage connects to the database named by TCG_TEST_MYSQL_DSN,
+// applies any pending migrations and empties every table. Tests are skipped
+// when the variable is not set, e.g.
+//
+//	docker compose up -d mysql
+//	TCG_TEST_MYSQL_DSN='root:password@tcp(localhost:3306)/tcg_db?parseTime=true&clientFoundRows=true' go test ./internal/storage/
+func newTestMySQLStorage(t *testing.T) *sqlStorage {
+	t.Helper()
+
+	dsn := os.Getenv("TCG_TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TCG_TEST_MYSQL_DSN not set")
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	m, err := migrations.NewForDriver(db, "mysql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+	for _, table := range []string{"game_card_keywords", "game_card_colors", "game_cards",
+		"image_cards", "playing_cards", "deck_cards", "decks", "deck_states", "card_sets", "game_definitions",
+		"keywords", "colors"} {
+		if _, err := db.Exec("DELETE FROM " + table); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return newMySQLStorage(db)
+}
+
+func TestMySQLStorage_GameCards(t *testing.T) {
+	testSQLStorageGameCards(t, newTestMySQLStorage(t))
+}
+
+func TestMySQLStorage_ImageAndPlayingCards(t *testing.T) {
+	testSQLStorageImageAndPlayingCards(t, newTestMySQLStorage(t))
+}
+
+func TestMySQLStorage_DecksAndStates(t *testing.T) {
+	testSQLStorageDecksAndStates(t, newTestMySQLStorage(t))
+}
@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+)
+
+// Page sizes used when a PageRequest does not set one, and the largest
+// allowed
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// Fields a page of cards can be sorted by. Ties are broken by ID, so every
+// sort is stable.
+const (
+	SortByName      = "name"
+	SortByCost      = "cost"
+	SortByCreatedAt = "created_at"
+)
+
+// PageRequest selects one page of a sorted list
+type PageRequest struct {
+	// Limit is the page size; zero means DefaultPageLimit and larger values
+	// are capped at MaxPageLimit
+	Limit int
+	// Cursor is the NextCursor of the previous page, or empty for the first
+	// page. A cursor only works with the Sort and Descending it was issued for.
+	Cursor string
+	// Sort is one of the SortBy constants; empty means SortByName
+	Sort       string
+	Descending bool
+}
+
+// Page is one page of a sorted list. NextCursor is empty on the last page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// GameCardFilter narrows a list of game cards. Zero values match every card.
+type GameCardFilter struct {
+	Type                   string
+	MinCost, MaxCost       *int
+	MinOffense, MaxOffense *int
+	MinDefense, MaxDefense *int
+	// Colors and Keywords match cards that have all of the given values
+	Colors     []string
+	Keywords   []string
+	IsResource *bool
+	// Set and Rarity match cards with a printing in the set and of the
+	// rarity; when both are given a single printing must match both
+	Set    string
+	Rarity string
+	// GameID matches cards that belong to the game
+	GameID *uuid.UUID
+}
+
+// Matches reports whether card passes the filter
+func (f GameCardFilter) Matches(card *models.GameCard) bool {
+	inRange := func(v int, lo, hi *int) bool {
+		return (lo == nil || v >= *lo) && (hi == nil || v <= *hi)
+	}
+	hasAll := func(have, want []string) bool {
+		for _, w := range want {
+			if !slices.Contains(have, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return (f.Type == "" || card.Type == f.Type) &&
+		inRange(card.Cost, f.MinCost, f.MaxCost) &&
+		inRange(card.Offense, f.MinOffense, f.MaxOffense) &&
+		inRange(card.Defense, f.MinDefense, f.MaxDefense) &&
+		hasAll(card.Colors, f.Colors) &&
+		hasAll(card.Keywords, f.Keywords) &&
+		(f.IsResource == nil || card.IsResource == *f.IsResource) &&
+		((f.Set == "" && f.Rarity == "") || slices.ContainsFunc(card.Printings, f.matchesPrinting)) &&
+		(f.GameID == nil || (card.GameID != nil && *card.GameID == *f.GameID))
+}
+
+// matchesPrinting reports whether p passes the Set and Rarity filters
+func (f GameCardFilter) matchesPrinting(p models.Printing) bool {
+	return (f.Set == "" || p.SetCode == f.Set) && (f.Rarity == "" || p.Rarity == f.Rarity)
+}
+
+// pageCursor is the decoded form of a cursor: the sort key and ID of the
+// last item on a page
+type pageCursor struct {
+	Sort string          `json:"s"`
+	Desc bool            `json:"d,omitempty"`
+	Key  json.RawMessage `json:"k"`
+	ID   uuid.UUID       `json:"id"`
+}
+
+// pageQuery is a validated PageRequest
+type pageQuery struct {
+	limit int
+	sort  string
+	desc  bool
+	// after is the position of the previous page's last item, if any
+	after *pagePosition
+}
+
+// pagePosition is a place in a sorted list. key is a string, int or
+// time.Time depending on the sort field; the zero time stands for a missing
+// timestamp and sorts first.
+type pagePosition struct {
+	key any
+	id  uuid.UUID
+}
+
+// parsePageRequest validates req for a list that can be sorted by the given
+// fields
+func parsePageRequest(req PageRequest, sorts ...string) (pageQuery, error) {
+	q := pageQuery{limit: req.Limit, sort: req.Sort, desc: req.Descending}
+	if q.sort == "" {
+		q.sort = SortByName
+	}
+	if !slices.Contains(sorts, q.sort) {
+		return q, &Error{Kind: ErrValidation, Message: "unsupported sort " + q.sort + "; expected one of: " + strings.Join(sorts, ", ")}
+	}
+	switch {
+	case q.limit < 0:
+		return q, &Error{Kind: ErrValidation, Message: "limit must not be negative"}
+	case q.limit == 0:
+		q.limit = DefaultPageLimit
+	case q.limit > MaxPageLimit:
+		q.limit = MaxPageLimit
+	}
+
+	if req.Cursor == "" {
+		return q, nil
+	}
+	errCursor := &Error{Kind: ErrValidation, Message: "invalid cursor"}
+	data, err := base64.RawURLEncoding.DecodeString(req.Cursor)
+	if err != nil {
+		return q, errCursor
+	}
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return q, errCursor
+	}
+	if c.Sort != q.sort || c.Desc != q.desc {
+		return q, &Error{Kind: ErrValidation, Message: "cursor was issued for a different sort order"}
+	}
+
+	var key any
+	switch q.sort {
+	case SortByCost:
+		var n int
+		err = json.Unmarshal(c.Key, &n)
+		key = n
+	case SortByCreatedAt:
+		var t time.Time
+		err = json.Unmarshal(c.Key, &t)
+		key = t.UTC()
+	default:
+		var s string
+		err = json.Unmarshal(c.Key, &s)
+		key = s
+	}
+	if err != nil {
+		return q, errCursor
+	}
+	q.after = &pagePosition{key: key, id: c.ID}
+	return q, nil
+}
+
+// cursorAfter encodes the position of the last item on a page
+func (q pageQuery) cursorAfter(p pagePosition) string {
+	key, _ := json.Marshal(p.key)
+	data, _ := json.Marshal(pageCursor{Sort: q.sort, Desc: q.desc, Key: key, ID: p.id})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// comparePositions orders two positions by key, then ID
+func comparePositions(a, b pagePosition) int {
+	var c int
+	switch ak := a.key.(type) {
+	case int:
+		c = cmp.Compare(ak, b.key.(int))
+	case time.Time:
+		c = ak.Compare(b.key.(time.Time))
+	default:
+		c = strings.Compare(a.key.(string), b.key.(string))
+	}
+	if c != 0 {
+		return c
+	}
+	return strings.Compare(a.id.String(), b.id.String())
+}
+
+// paginate sorts items in memory and returns the page q asks for
+func paginate[T any](items []T, q pageQuery, position func(T) pagePosition) *Page[T] {
+	compare := func(a, b pagePosition) int {
+		if q.desc {
+			return comparePositions(b, a)
+		}
+		return comparePositions(a, b)
+	}
+	slices.SortFunc(items, func(a, b T) int {
+		return compare(position(a), position(b))
+	})
+
+	if q.after != nil {
+		start, _ := slices.BinarySearchFunc(items, *q.after, func(item T, after pagePosition) int {
+			return compare(position(item), after)
+		})
+		// Skip the cursor's own item, which is still present unless deleted
+		if start < len(items) && compare(position(items[start]), *q.after) == 0 {
+			start++
+		}
+		items = items[start:]
+	}
+
+	page := &Page[T]{Items: items}
+	if len(items) > q.limit {
+		page.Items = items[:q.limit]
+		page.NextCursor = q.cursorAfter(position(page.Items[q.limit-1]))
+	}
+	return page
+}
+
+// gameCardPosition is where card falls when sorting by field
+func gameCardPosition(card *models.GameCard, field string) pagePosition {
+	switch field {
+	case SortByCost:
+		return pagePosition{key: card.Cost, id: card.ID}
+	case SortByCreatedAt:
+		return pagePosition{key: card.CreatedAt.UTC(), id: card.ID}
+	default:
+		return pagePosition{key: card.Name, id: card.ID}
+	}
+}
+
+// imageCardPosition is where card falls when sorting by field
+func imageCardPosition(card *models.ImageCard, field string) pagePosition {
+	if field == SortByCreatedAt {
+		return pagePosition{key: card.CreatedAt.UTC(), id: card.ID}
+	}
+	return pagePosition{key: card.Name, id: card.ID}
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/config"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// maxModifyAttempts bounds the optimistic retries of ModifyDeckState
+const maxModifyAttempts = 50
+
+// ErrDeckStateContention is returned when a deck state is modified so often
+// that ModifyDeckState cannot complete
+var ErrDeckStateContention error = &Error{Kind: ErrConflict, Message: "deck state is being modified concurrently; try again"}
+
+// RedisDeckStateStore keeps deck states in Redis as JSON documents. Every
+// write refreshes the key's TTL, so states of abandoned games expire.
+type RedisDeckStateStore struct {
+	client    *redis.Client
+	keyPrefix string
+	ttl       time.Duration
+}
+
+// NewRedisDeckStateStore connects to the Redis server described by cfg
+func NewRedisDeckStateStore(ctx context.Context, cfg config.RedisConfig) (*RedisDeckStateStore, error) {
+	var ttl time.Duration
+	if cfg.TTL != "" {
+		var err error
+		ttl, err = time.ParseDuration(cfg.TTL)
+		if err != nil || ttl < 0 {
+			return nil, fmt.Errorf("invalid redis ttl %q", cfg.TTL)
+		}
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	return newRedisDeckStateStore(client, cfg.KeyPrefix, ttl), nil
+}
+
+func newRedisDeckStateStore(client *redis.Client, keyPrefix string, ttl time.Duration) *RedisDeckStateStore {
+	return &RedisDeckStateStore{
+		client:    client,
+		keyPrefix: keyPrefix,
+		ttl:       ttl,
+	}
+}
+
+// Close closes the Redis client
+func (s *RedisDeckStateStore) Close() error {
+	return s.client.Close()
+}
+
+func (s *RedisDeckStateStore) key(id uuid.UUID) string {
+	return s.keyPrefix + "deck-state:" + id.String()
+}
+
+// GetDeckState returns a specific deck state by ID
+func (s *RedisDeckStateStore) GetDeckState(ctx context.Context, id uuid.UUID) (_ *models.DeckState, err error) {
+	defer translateError(&err, isRedisUnavailable)
+	return s.get(ctx, s.client, id)
+}
+
+// CreateDeckState adds a new deck state to storage
+func (s *RedisDeckStateStore) CreateDeckState(ctx context.Context, state models.DeckState) (_ *models.DeckState, err error) {
+	defer translateError(&err, isRedisUnavailable)
+
+	// Generate a new ID if not provided
+	if state.ID == uuid.Nil {
+		state.ID = uuid.New()
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.client.SetNX(ctx, s.key(state.ID), data, s.ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, alreadyExists("deck state")
+	}
+
+	return &state, nil
+}
+
+// UpdateDeckState updates an existing deck state in storage
+func (s *RedisDeckStateStore) UpdateDeckState(ctx context.Context, state models.DeckState) (_ *models.DeckState, err error) {
+	defer translateError(&err, isRedisUnavailable)
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.client.SetArgs(ctx, s.key(state.ID), data, redis.SetArgs{Mode: "XX", TTL: s.ttl}).Err()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// ModifyDeckState applies fn to a deck state using WATCH/MULTI, retrying if
+// another client writes the state in between
+func (s *RedisDeckStateStore) ModifyDeckState(ctx context.Context, id uuid.UUID, fn func(state *models.DeckState) error) (_ *models.DeckState, err error) {
+	defer translateError(&err, isRedisUnavailable)
+	key := s.key(id)
+
+	for attempt := 0; attempt < maxModifyAttempts; attempt++ {
+		var modified *models.DeckState
+		err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+			state, err := s.get(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			if err := fn(state); err != nil {
+				return err
+			}
+			state.ID = id
+
+			data, err := json.Marshal(state)
+			if err != nil {
+				return err
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, key, data, s.ttl)
+				return nil
+			})
+			modified = state
+			return err
+		}, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return modified, nil
+	}
+
+	return nil, ErrDeckStateContention
+}
+
+// DeleteDeckState removes a deck state from storage
+func (s *RedisDeckStateStore) DeleteDeckState(ctx context.Context, id uuid.UUID) (err error) {
+	defer translateError(&err, isRedisUnavailable)
+	deleted, err := s.client.Del(ctx, s.key(id)).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// get reads and decodes a deck state with c, which may be a transaction
+func (s *RedisDeckStateStore) get(ctx context.Context, c redis.Cmdable, id uuid.UUID) (*models.DeckState, error) {
+	data, err := c.Get(ctx, s.key(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state models.DeckState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("decode deck state %s: %w", id, err)
+	}
+	return &state, nil
+}
+
+// isRedisUnavailable reports go-redis errors that mean the server cannot be
+// reached
+func isRedisUnavailable(err error) bool {
+	return errors.Is(err, redis.ErrClosed) || errors.Is(err, redis.ErrPoolTimeout)
+}
@@ -0,0 +1,229 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/config"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedisStore(t *testing.T, ttl time.Duration) (*RedisDeckStateStore, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	store := newRedisDeckStateStore(client, "test:", ttl)
+	t.Cleanup(func() { _ = store.Close() })
+	return store, server
+}
+
+func newTestDeck(size int) models.Deck {
+	deck := models.Deck{ID: uuid.New(), Name: "Redis Deck"}
+	for i := 0; i < size; i++ {
+		deck.Cards = append(deck.Cards, uuid.New())
+	}
+	return deck
+}
+
+func TestRedisDeckStateStore_CRUD(t *testing.T) {
+	store, server := newTestRedisStore(t, 0)
+	ctx := context.Background()
+
+	state, err := store.CreateDeckState(ctx, *models.NewDeckState(newTestDeck(5)))
+	if err != nil {
+		t.Fatalf("CreateDeckState: %v", err)
+	}
+	if !server.Exists("test:deck-state:" + state.ID.String()) {
+		t.Error("Expected the deck state under the configured key prefix")
+	}
+	if _, err := store.CreateDeckState(ctx, *state); err == nil {
+		t.Error("Expected duplicate create to fail")
+	}
+
+	state.Draw(2)
+	if _, err := store.UpdateDeckState(ctx, *state); err != nil {
+		t.Fatalf("UpdateDeckState: %v", err)
+	}
+	got, err := store.GetDeckState(ctx, state.ID)
+	if err != nil {
+		t.Fatalf("GetDeckState: %v", err)
+	}
+	if len(got.DrawPile) != 3 || len(got.Drawn) != 2 {
+		t.Errorf("Unexpected deck state read back: %+v", got)
+	}
+
+	if _, err := store.UpdateDeckState(ctx, models.DeckState{ID: uuid.New()}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound updating a missing state, got %v", err)
+	}
+	if err := store.DeleteDeckState(ctx, state.ID); err != nil {
+		t.Fatalf("DeleteDeckState: %v", err)
+	}
+	if _, err := store.GetDeckState(ctx, state.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+	if err := store.DeleteDeckState(ctx, state.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+	}
+}
+
+func TestRedisDeckStateStore_TTL(t *testing.T) {
+	store, server := newTestRedisStore(t, time.Hour)
+	ctx := context.Background()
+
+	state, err := store.CreateDeckState(ctx, *models.NewDeckState(newTestDeck(3)))
+	if err != nil {
+		t.Fatalf("CreateDeckState: %v", err)
+	}
+	key := "test:deck-state:" + state.ID.String()
+	if ttl := server.TTL(key); ttl != time.Hour {
+		t.Errorf("Expected a TTL of 1h, got %v", ttl)
+	}
+
+	// Writes push the expiry back
+	server.FastForward(45 * time.Minute)
+	if _, err := store.ModifyDeckState(ctx, state.ID, func(s *models.DeckState) error {
+		s.Draw(1)
+		return nil
+	}); err != nil {
+		t.Fatalf("ModifyDeckState: %v", err)
+	}
+	if ttl := server.TTL(key); ttl != time.Hour {
+		t.Errorf("Expected the TTL to be refreshed to 1h, got %v", ttl)
+	}
+
+	server.FastForward(2 * time.Hour)
+	if _, err := store.GetDeckState(ctx, state.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the deck state to expire, got %v", err)
+	}
+}
+
+func TestRedisDeckStateStore_ModifyRejected(t *testing.T) {
+	store, _ := newTestRedisStore(t, 0)
+	ctx := context.Background()
+
+	state, err := store.CreateDeckState(ctx, *models.NewDeckState(newTestDeck(3)))
+	if err != nil {
+		t.Fatalf("CreateDeckState: %v", err)
+	}
+
+	errRejected := errors.New("rejected")
+	_, err = store.ModifyDeckState(ctx, state.ID, func(s *models.DeckState) error {
+		s.Draw(3)
+		return errRejected
+	})
+	if !errors.Is(err, errRejected) {
+		t.Errorf("Expected the callback's error, got %v", err)
+	}
+	if got, _ := store.GetDeckState(ctx, state.ID); len(got.DrawPile) != 3 {
+		t.Errorf("Expected a rejected change not to be saved, got %+v", got)
+	}
+
+	if _, err := store.ModifyDeckState(ctx, uuid.New(), func(*models.DeckState) error { return nil }); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing state, got %v", err)
+	}
+}
+
+func TestRedisDeckStateStore_ConcurrentDraws(t *testing.T) {
+	store, _ := newTestRedisStore(t, 0)
+	ctx := context.Background()
+
+	const size = 40
+	state, err := store.CreateDeckState(ctx, *models.NewDeckState(newTestDeck(size)))
+	if err != nil {
+		t.Fatalf("CreateDeckState: %v", err)
+	}
+
+	var mu sync.Mutex
+	seen := make(map[uuid.UUID]int)
+	var wg sync.WaitGroup
+	for i := 0; i < size; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var drawn []uuid.UUID
+			_, err := store.ModifyDeckState(ctx, state.ID, func(s *models.DeckState) error {
+				drawn = s.Draw(1)
+				return nil
+			})
+			if err != nil {
+				t.Errorf("ModifyDeckState: %v", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range drawn {
+				seen[id]++
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != size {
+		t.Errorf("Expected %d distinct cards drawn, got %d", size, len(seen))
+	}
+	for id, count := range seen {
+		if count != 1 {
+			t.Errorf("Card %s drawn %d times", id, count)
+		}
+	}
+	got, err := store.GetDeckState(ctx, state.ID)
+	if err != nil {
+		t.Fatalf("GetDeckState: %v", err)
+	}
+	if len(got.DrawPile) != 0 || len(got.Drawn) != size {
+		t.Errorf("Expected every card drawn, got %d left and %d drawn", len(got.DrawPile), len(got.Drawn))
+	}
+}
+
+func TestWithDeckStateStore(t *testing.T) {
+	store, _ := newTestRedisStore(t, 0)
+	base := NewMockStorage()
+	sto := WithDeckStateStore(base, store)
+	ctx := context.Background()
+
+	deck, err := sto.CreateDeck(ctx, newTestDeck(2))
+	if err != nil {
+		t.Fatalf("CreateDeck: %v", err)
+	}
+	state, err := sto.CreateDeckState(ctx, *models.NewDeckState(*deck))
+	if err != nil {
+		t.Fatalf("CreateDeckState: %v", err)
+	}
+
+	if _, err := base.GetDeck(ctx, deck.ID); err != nil {
+		t.Errorf("Expected decks in the base storage, got %v", err)
+	}
+	if _, err := base.GetDeckState(ctx, state.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected deck states not to reach the base storage, got %v", err)
+	}
+	if _, err := store.GetDeckState(ctx, state.ID); err != nil {
+		t.Errorf("Expected deck states in Redis, got %v", err)
+	}
+}
+
+func TestNewRedisDeckStateStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+
+	store, err := NewRedisDeckStateStore(ctx, config.RedisConfig{Addr: server.Addr(), TTL: "30m"})
+	if err != nil {
+		t.Fatalf("NewRedisDeckStateStore: %v", err)
+	}
+	if store.ttl != 30*time.Minute {
+		t.Errorf("Expected a 30m TTL, got %v", store.ttl)
+	}
+	_ = store.Close()
+
+	for _, ttl := range []string{"soon", "-1h"} {
+		if _, err := NewRedisDeckStateStore(ctx, config.RedisConfig{Addr: server.Addr(), TTL: ttl}); err == nil {
+			t.Errorf("Expected an error for TTL %q", ttl)
+		}
+	}
+}
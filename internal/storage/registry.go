@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+)
+
+// CardType describes how to load one kind of card from a Storage. Registering
+// a CardType makes the card available through GetCard and ListCards, and so
+// through every handler built on them.
+type CardType struct {
+	// Name must match the value returned by the card's GetCardType
+	Name string
+	Get  func(ctx context.Context, s Storage, id uuid.UUID) (models.CardInterface, error)
+	List func(ctx context.Context, s Storage) ([]models.CardInterface, error)
+}
+
+// ErrUnsupportedCardType is returned by ListCards for an unregistered type. It
+// is an ErrValidation error.
+var ErrUnsupportedCardType error = &Error{Kind: ErrValidation, Message: "unsupported card type"}
+
+var (
+	cardTypesMu sync.RWMutex
+	cardTypes   []CardType
+)
+
+func init() {
+	RegisterCardType(CardType{
+		Name: models.CardTypeGameCard,
+		Get: func(ctx context.Context, s Storage, id uuid.UUID) (models.CardInterface, error) {
+			return s.GetGameCard(ctx, id)
+		},
+		List: func(ctx context.Context, s Storage) ([]models.CardInterface, error) {
+			cards, err := s.ListGameCards(ctx, "gamecard")
+			return toCardInterfaces(cards), err
+		},
+	})
+	RegisterCardType(CardType{
+		Name: models.CardTypeImageCard,
+		Get: func(ctx context.Context, s Storage, id uuid.UUID) (models.CardInterface, error) {
+			return s.GetImageCard(ctx, id)
+		},
+		List: func(ctx context.Context, s Storage) ([]models.CardInterface, error) {
+			cards, err := s.ListImageCards(ctx)
+			return toCardInterfaces(cards), err
+		},
+	})
+	RegisterCardType(CardType{
+		Name: models.CardTypePlayingCard,
+		Get: func(ctx context.Context, s Storage, id uuid.UUID) (models.CardInterface, error) {
+			return s.GetPlayingCard(ctx, id)
+		},
+		List: func(ctx context.Context, s Storage) ([]models.CardInterface, error) {
+			cards, err := s.ListPlayingCards(ctx)
+			return toCardInterfaces(cards), err
+		},
+	})
+}
+
+// RegisterCardType adds a card type to the registry. It panics if a type with
+// the same name is already registered.
+func RegisterCardType(t CardType) {
+	cardTypesMu.Lock()
+	defer cardTypesMu.Unlock()
+
+	for _, existing := range cardTypes {
+		if existing.Name == t.Name {
+			panic("storage: card type registered twice: " + t.Name)
+		}
+	}
+	cardTypes = append(cardTypes, t)
+}
+
+// CardTypes returns the names of all registered card types in registration
+// order
+func CardTypes() []string {
+	cardTypesMu.RLock()
+	defer cardTypesMu.RUnlock()
+
+	names := make([]string, len(cardTypes))
+	for i, t := range cardTypes {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// GetCard returns the card with the given ID, whatever its type. It returns
+// ErrNotFound if no registered type has a card with that ID.
+func GetCard(ctx context.Context, s Storage, id uuid.UUID) (models.CardInterface, error) {
+	for _, t := range registeredCardTypes() {
+		card, err := t.Get(ctx, s, id)
+		if err == nil {
+			return card, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("get %s: %w", t.Name, err)
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// ListCards returns all cards of the given type, or of every registered type
+// if cardType is empty. Cards are grouped by type in registration order and
+// sorted by name within each type.
+func ListCards(ctx context.Context, s Storage, cardType string) ([]models.CardInterface, error) {
+	var cards []models.CardInterface
+	found := false
+	for _, t := range registeredCardTypes() {
+		if cardType != "" && t.Name != cardType {
+			continue
+		}
+		found = true
+
+		typeCards, err := t.List(ctx, s)
+		if err != nil {
+			return nil, fmt.Errorf("list %s: %w", t.Name, err)
+		}
+		sort.SliceStable(typeCards, func(i, j int) bool {
+			return typeCards[i].GetName() < typeCards[j].GetName()
+		})
+		cards = append(cards, typeCards...)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedCardType, cardType)
+	}
+	if cards == nil {
+		cards = []models.CardInterface{}
+	}
+	return cards, nil
+}
+
+// registeredCardTypes returns a snapshot of the registry
+func registeredCardTypes() []CardType {
+	cardTypesMu.RLock()
+	defer cardTypesMu.RUnlock()
+
+	return append([]CardType{}, cardTypes...)
+}
+
+// toCardInterfaces converts a slice of concrete cards to CardInterface values
+func toCardInterfaces[C models.CardInterface](cards []C) []models.CardInterface {
+	result := make([]models.CardInterface, len(cards))
+	for i, card := range cards {
+		result[i] = card
+	}
+	return result
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/jwebster45206/tcg-api/internal/query"
+)
+
+// searchColumns maps query fields to game_cards columns, or for keywords and
+// colors to the table holding them
+var searchColumns = map[query.Field]string{
+	query.FieldName:       "name",
+	query.FieldSubtitle:   "subtitle",
+	query.FieldType:       "type",
+	query.FieldCost:       "cost",
+	query.FieldOffense:    "offense",
+	query.FieldDefense:    "defense",
+	query.FieldKeywords:   "game_card_keywords",
+	query.FieldColors:     "game_card_colors",
+	query.FieldIsResource: "is_resource",
+}
+
+// likeEscaper escapes LIKE wildcards. "!" is the escape character because,
+// unlike backslash, MySQL and SQLite read it the same way in a literal.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// splitSearch splits the terms of expr into those searchCondition can
+// translate and the rest, which must be matched in Go. SQLite's LOWER only
+// folds ASCII letters, so terms with other text are kept out of SQL.
+func splitSearch(expr query.Expr) (pushed, rest []query.Expr) {
+	terms := []query.Expr{expr}
+	if and, ok := expr.(query.And); ok {
+		terms = and.Exprs
+	}
+	for _, term := range terms {
+		if foldsInSQL(term) {
+			pushed = append(pushed, term)
+		} else {
+			rest = append(rest, term)
+		}
+	}
+	return pushed, rest
+}
+
+// foldsInSQL reports whether every text value in expr is ASCII, so LOWER
+// ignores its case as query.Expr.Match does
+func foldsInSQL(expr query.Expr) bool {
+	switch e := expr.(type) {
+	case query.And:
+		return allFoldInSQL(e.Exprs)
+	case query.Or:
+		return allFoldInSQL(e.Exprs)
+	case query.Not:
+		return foldsInSQL(e.Expr)
+	case query.Text:
+		return isASCII(e.Value)
+	case query.Has:
+		return isASCII(e.Value)
+	}
+	return true
+}
+
+// allFoldInSQL reports whether foldsInSQL holds for every expression
+func allFoldInSQL(exprs []query.Expr) bool {
+	for _, e := range exprs {
+		if !foldsInSQL(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// isASCII reports whether s only holds ASCII characters
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// searchCondition translates a query into a SQL condition on game_cards.
+// Text comparisons ignore case like query.Expr.Match does as long as the
+// text is ASCII; see splitSearch.
+func searchCondition(expr query.Expr) (string, []any, error) {
+	join := func(exprs []query.Expr, op string) (string, []any, error) {
+		parts := make([]string, len(exprs))
+		var args []any
+		for i, e := range exprs {
+			part, partArgs, err := searchCondition(e)
+			if err != nil {
+				return "", nil, err
+			}
+			parts[i] = part
+			args = append(args, partArgs...)
+		}
+		return `(` + strings.Join(parts, op) + `)`, args, nil
+	}
+
+	switch e := expr.(type) {
+	case query.And:
+		return join(e.Exprs, ` AND `)
+	case query.Or:
+		return join(e.Exprs, ` OR `)
+	case query.Not:
+		inner, args, err := searchCondition(e.Expr)
+		if err != nil {
+			return "", nil, err
+		}
+		return `NOT ` + inner, args, nil
+	case query.Text:
+		column := searchColumns[e.Field]
+		value := strings.ToLower(e.Value)
+		if e.Exact {
+			return `(LOWER(` + column + `) = ?)`, []any{value}, nil
+		}
+		return `(LOWER(` + column + `) LIKE ? ESCAPE '!')`, []any{"%" + likeEscaper.Replace(value) + "%"}, nil
+	case query.Number:
+		return `(` + searchColumns[e.Field] + ` ` + e.Op.String() + ` ?)`, []any{e.Value}, nil
+	case query.Has:
+		table := searchColumns[e.Field]
+		return `EXISTS (SELECT 1 FROM ` + table + ` WHERE ` + table + `.card_id = game_cards.id AND LOWER(` +
+			table + `.value) = ?)`, []any{strings.ToLower(e.Value)}, nil
+	case query.Resource:
+		return `(is_resource = ?)`, []any{e.Value}, nil
+	}
+	return "", nil, fmt.Errorf("unsupported query expression %T", expr)
+}
@@ -0,0 +1,1555 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/query"
+)
+
+// sqlStorage implements Storage on top of database/sql. Queries stick to SQL
+// that MySQL and other common databases agree on, so driver specific
+// behavior is limited to the hooks on this struct.
+type sqlStorage struct {
+	db *sql.DB
+
+	// isDuplicateKey reports whether an insert failed on a primary key
+	isDuplicateKey func(err error) bool
+
+	// isForeignKey reports whether a write failed on a foreign key, either
+	// referencing a missing row or deleting a referenced one
+	isForeignKey func(err error) bool
+
+	// isUnavailable reports driver errors that mean the database cannot be
+	// reached, beyond the connection errors every backend shares. It may be
+	// nil.
+	isUnavailable func(err error) bool
+
+	// lockForUpdate is appended to a SELECT that reads a row the transaction
+	// is about to modify, e.g. " FOR UPDATE"
+	lockForUpdate string
+}
+
+// Close closes the underlying database connection pool
+func (s *sqlStorage) Close() error {
+	return s.db.Close()
+}
+
+// withTx runs fn inside a transaction, committing if it returns nil
+func (s *sqlStorage) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// timeColumn scans a nullable timestamp into a time.Time, using the zero
+// time for NULL
+type timeColumn struct {
+	t *time.Time
+}
+
+func (c timeColumn) Scan(src any) error {
+	var nt sql.NullTime
+	if err := nt.Scan(src); err != nil {
+		return err
+	}
+	*c.t = time.Time{}
+	if nt.Valid {
+		*c.t = nt.Time.UTC()
+	}
+	return nil
+}
+
+// dbTime converts a timestamp to a column value. The zero time is stored as
+// NULL because it falls outside the DATETIME range of most databases.
+func dbTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t.UTC(), Valid: !t.IsZero()}
+}
+
+// checkAffected converts an update or delete that touched no rows into
+// ErrNotFound
+func checkAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// lockVersion locks the row with the given ID in a table of versioned
+// records for the rest of tx and returns its version. It fails with
+// ErrNotFound if there is no such row and with ErrVersionMismatch if the
+// version is not the expected one.
+func (s *sqlStorage) lockVersion(ctx context.Context, tx *sql.Tx, table string, id uuid.UUID, expected int) (int, error) {
+	var version int
+	err := tx.QueryRowContext(ctx, `SELECT version FROM `+table+` WHERE id = ?`+s.lockForUpdate, id).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return version, checkVersion(version, expected)
+}
+
+// GameCard operations
+
+const gameCardColumns = `id, name, subtitle, cost, type, offense, defense, is_resource,
+	front_image_url, back_image_url, game_id, stats, version, created_at, updated_at`
+
+func scanGameCard(row rowScanner) (*models.GameCard, error) {
+	card := models.GameCard{
+		Keywords:  []string{},
+		Colors:    []string{},
+		Printings: []models.Printing{},
+	}
+	var gameID uuid.NullUUID
+	var stats []byte
+	err := row.Scan(&card.ID, &card.Name, &card.Subtitle, &card.Cost, &card.Type,
+		&card.Offense, &card.Defense, &card.IsResource, &card.FrontImageURL, &card.BackImageURL,
+		&gameID, &stats, &card.Version, timeColumn{&card.CreatedAt}, timeColumn{&card.UpdatedAt})
+	if err != nil {
+		return nil, err
+	}
+
+	if gameID.Valid {
+		card.GameID = &gameID.UUID
+	}
+	if stats != nil {
+		if err := json.Unmarshal(stats, &card.Stats); err != nil {
+			return nil, fmt.Errorf("decode stats of game card %s: %w", card.ID, err)
+		}
+	}
+	return &card, nil
+}
+
+// gameCardStats converts the stats of a card to a column value, storing no
+// stats as NULL
+func gameCardStats(card models.GameCard) (any, error) {
+	if len(card.Stats) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(card.Stats)
+}
+
+// loadGameCardChildren fills in keywords, colors and printings for the given
+// cards
+func (s *sqlStorage) loadGameCardChildren(ctx context.Context, cards map[uuid.UUID]*models.GameCard, where string, args ...any) error {
+	for _, child := range []struct {
+		table string
+		field func(*models.GameCard) *[]string
+	}{
+		{"game_card_keywords", func(c *models.GameCard) *[]string { return &c.Keywords }},
+		{"game_card_colors", func(c *models.GameCard) *[]string { return &c.Colors }},
+	} {
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT card_id, value FROM `+child.table+where+` ORDER BY card_id, position`, args...)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var cardID uuid.UUID
+			var value string
+			if err := rows.Scan(&cardID, &value); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			if card, ok := cards[cardID]; ok {
+				values := child.field(card)
+				*values = append(*values, value)
+			}
+		}
+		if err := rows.Close(); err != nil {
+			return err
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT card_id, set_code, collector_number, rarity, front_image_url
+		FROM game_card_printings`+where+` ORDER BY card_id, position`, args...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var cardID uuid.UUID
+		var printing models.Printing
+		if err := rows.Scan(&cardID, &printing.SetCode, &printing.CollectorNumber, &printing.Rarity,
+			&printing.FrontImageURL); err != nil {
+			return err
+		}
+		if card, ok := cards[cardID]; ok {
+			card.Printings = append(card.Printings, printing)
+		}
+	}
+	return rows.Err()
+}
+
+// writeGameCardChildren replaces the keywords, colors and printings of a card.
+// It fails with ErrUnknownCardSet if a printing is in a set that does not
+// exist.
+func (s *sqlStorage) writeGameCardChildren(ctx context.Context, tx *sql.Tx, card models.GameCard) error {
+	for _, child := range []struct {
+		table  string
+		values []string
+	}{
+		{"game_card_keywords", card.Keywords},
+		{"game_card_colors", card.Colors},
+	} {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+child.table+` WHERE card_id = ?`, card.ID); err != nil {
+			return err
+		}
+		for i, value := range child.values {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO `+child.table+` (card_id, position, value) VALUES (?, ?, ?)`,
+				card.ID, i, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM game_card_printings WHERE card_id = ?`, card.ID); err != nil {
+		return err
+	}
+	for i, printing := range card.Printings {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO game_card_printings
+			(card_id, position, set_code, collector_number, rarity, front_image_url) VALUES (?, ?, ?, ?, ?, ?)`,
+			card.ID, i, printing.SetCode, printing.CollectorNumber, printing.Rarity, printing.FrontImageURL); err != nil {
+			if s.isForeignKey(err) {
+				return ErrUnknownCardSet
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// sortColumns maps sort fields to the columns holding them
+var sortColumns = map[string]string{
+	SortByName:      "name",
+	SortByCost:      "cost",
+	SortByCreatedAt: "created_at",
+}
+
+// pageConditions returns the WHERE conditions that skip to the position after
+// q's cursor, and the ORDER BY and LIMIT clauses of the page. One row more
+// than the page size is fetched to learn whether another page follows.
+//
+// Missing created_at values are NULL, which both MySQL and SQLite sort
+// first in ascending order and last in descending order, matching the zero
+// time in pagePosition.
+func pageConditions(q pageQuery) (conditions []string, args []any, tail string) {
+	column := sortColumns[q.sort]
+	op, dir := ">", "ASC"
+	if q.desc {
+		op, dir = "<", "DESC"
+	}
+	tail = ` ORDER BY ` + column + ` ` + dir + `, id ` + dir + ` LIMIT ` + strconv.Itoa(q.limit+1)
+
+	if q.after == nil {
+		return nil, nil, tail
+	}
+	key, id := q.after.key, q.after.id
+	if t, ok := key.(time.Time); ok {
+		switch {
+		case t.IsZero() && !q.desc:
+			return []string{`((` + column + ` IS NULL AND id > ?) OR ` + column + ` IS NOT NULL)`}, []any{id}, tail
+		case t.IsZero():
+			return []string{`(` + column + ` IS NULL AND id < ?)`}, []any{id}, tail
+		case q.desc:
+			return []string{`(` + column + ` < ? OR (` + column + ` = ? AND id < ?) OR ` + column + ` IS NULL)`},
+				[]any{t, t, id}, tail
+		}
+	}
+	return []string{`(` + column + ` ` + op + ` ? OR (` + column + ` = ? AND id ` + op + ` ?))`},
+		[]any{key, key, id}, tail
+}
+
+// placeholders returns n comma separated parameter placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// ListGameCards returns all cards of the specified type
+func (s *sqlStorage) ListGameCards(ctx context.Context, cardType string) (_ []*models.GameCard, err error) {
+	defer translateError(&err, s.isUnavailable)
+	if cardType != "gamecard" {
+		return nil, ErrUnsupportedCardType
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+gameCardColumns+` FROM game_cards`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	cards := []*models.GameCard{}
+	byID := make(map[uuid.UUID]*models.GameCard)
+	for rows.Next() {
+		card, err := scanGameCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+		byID[card.ID] = card
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadGameCardChildren(ctx, byID, ""); err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// ListGameCardsPage returns one page of the game cards matching filter
+func (s *sqlStorage) ListGameCardsPage(ctx context.Context, filter GameCardFilter, page PageRequest) (_ *Page[*models.GameCard], err error) {
+	defer translateError(&err, s.isUnavailable)
+	q, err := parsePageRequest(page, SortByName, SortByCost, SortByCreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []string
+	var args []any
+	if filter.Type != "" {
+		conditions = append(conditions, `type = ?`)
+		args = append(args, filter.Type)
+	}
+	for _, r := range []struct {
+		column string
+		bound  *int
+		op     string
+	}{
+		{"cost", filter.MinCost, ">="}, {"cost", filter.MaxCost, "<="},
+		{"offense", filter.MinOffense, ">="}, {"offense", filter.MaxOffense, "<="},
+		{"defense", filter.MinDefense, ">="}, {"defense", filter.MaxDefense, "<="},
+	} {
+		if r.bound != nil {
+			conditions = append(conditions, r.column+` `+r.op+` ?`)
+			args = append(args, *r.bound)
+		}
+	}
+	for _, child := range []struct {
+		table  string
+		values []string
+	}{
+		{"game_card_colors", filter.Colors},
+		{"game_card_keywords", filter.Keywords},
+	} {
+		for _, value := range child.values {
+			conditions = append(conditions, `EXISTS (SELECT 1 FROM `+child.table+` WHERE `+
+				child.table+`.card_id = game_cards.id AND `+child.table+`.value = ?)`)
+			args = append(args, value)
+		}
+	}
+	if filter.IsResource != nil {
+		conditions = append(conditions, `is_resource = ?`)
+		args = append(args, *filter.IsResource)
+	}
+	if filter.Set != "" || filter.Rarity != "" {
+		// One printing must match both
+		printing := `EXISTS (SELECT 1 FROM game_card_printings WHERE game_card_printings.card_id = game_cards.id`
+		if filter.Set != "" {
+			printing += ` AND game_card_printings.set_code = ?`
+			args = append(args, filter.Set)
+		}
+		if filter.Rarity != "" {
+			printing += ` AND game_card_printings.rarity = ?`
+			args = append(args, filter.Rarity)
+		}
+		conditions = append(conditions, printing+`)`)
+	}
+	if filter.GameID != nil {
+		conditions = append(conditions, `game_id = ?`)
+		args = append(args, *filter.GameID)
+	}
+
+	return s.listGameCardsPage(ctx, q, conditions, args)
+}
+
+// SearchGameCards returns one page of the game cards matching expr. Terms
+// that SQL can compare are translated into the WHERE clause, and any others
+// are matched in Go.
+func (s *sqlStorage) SearchGameCards(ctx context.Context, expr query.Expr, page PageRequest) (_ *Page[*models.GameCard], err error) {
+	defer translateError(&err, s.isUnavailable)
+	q, err := parsePageRequest(page, SortByName, SortByCost, SortByCreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	pushed, rest := splitSearch(expr)
+	var conditions []string
+	var args []any
+	for _, term := range pushed {
+		condition, termArgs, err := searchCondition(term)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+		args = append(args, termArgs...)
+	}
+	if len(rest) == 0 {
+		return s.listGameCardsPage(ctx, q, conditions, args)
+	}
+	return s.filterGameCardsPage(ctx, q, conditions, args, query.And{Exprs: rest})
+}
+
+// filterGameCardsPage returns the page q asks for of the game cards matching
+// every condition and expr, reading pages of the former until it has enough
+// of the latter
+func (s *sqlStorage) filterGameCardsPage(ctx context.Context, q pageQuery, conditions []string, args []any, expr query.Expr) (*Page[*models.GameCard], error) {
+	cards := []*models.GameCard{}
+	batch := q
+	for {
+		page, err := s.listGameCardsPage(ctx, batch, conditions, args)
+		if err != nil {
+			return nil, err
+		}
+		for _, card := range page.Items {
+			if expr.Match(card) {
+				cards = append(cards, card)
+			}
+		}
+		if len(cards) > q.limit || page.NextCursor == "" {
+			break
+		}
+		last := gameCardPosition(page.Items[len(page.Items)-1], q.sort)
+		batch.after = &last
+	}
+
+	result := &Page[*models.GameCard]{Items: cards}
+	if len(cards) > q.limit {
+		result.Items = cards[:q.limit]
+		result.NextCursor = q.cursorAfter(gameCardPosition(result.Items[q.limit-1], q.sort))
+	}
+	return result, nil
+}
+
+// listGameCardsPage returns the page q asks for of the game cards matching
+// every condition
+func (s *sqlStorage) listGameCardsPage(ctx context.Context, q pageQuery, conditions []string, args []any) (*Page[*models.GameCard], error) {
+	pageConds, pageArgs, tail := pageConditions(q)
+	conditions = append(conditions, pageConds...)
+	args = append(args, pageArgs...)
+
+	query := `SELECT ` + gameCardColumns + ` FROM game_cards`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	rows, err := s.db.QueryContext(ctx, query+tail, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	cards := []*models.GameCard{}
+	byID := make(map[uuid.UUID]*models.GameCard)
+	for rows.Next() {
+		card, err := scanGameCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+		byID[card.ID] = card
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &Page[*models.GameCard]{Items: cards}
+	if len(cards) > q.limit {
+		result.Items = cards[:q.limit]
+		delete(byID, cards[q.limit].ID)
+		result.NextCursor = q.cursorAfter(gameCardPosition(result.Items[q.limit-1], q.sort))
+	}
+	if len(byID) > 0 {
+		ids := make([]any, 0, len(byID))
+		for id := range byID {
+			ids = append(ids, id)
+		}
+		if err := s.loadGameCardChildren(ctx, byID, ` WHERE card_id IN (`+placeholders(len(ids))+`)`, ids...); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// GetGameCard returns a specific card by ID
+func (s *sqlStorage) GetGameCard(ctx context.Context, id uuid.UUID) (_ *models.GameCard, err error) {
+	defer translateError(&err, s.isUnavailable)
+	row := s.db.QueryRowContext(ctx, `SELECT `+gameCardColumns+` FROM game_cards WHERE id = ?`, id)
+	card, err := scanGameCard(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	byID := map[uuid.UUID]*models.GameCard{card.ID: card}
+	if err := s.loadGameCardChildren(ctx, byID, ` WHERE card_id = ?`, id); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// CreateGameCard adds a new card to storage
+func (s *sqlStorage) CreateGameCard(ctx context.Context, card models.GameCard) (_ *models.GameCard, err error) {
+	defer translateError(&err, s.isUnavailable)
+
+	// Generate a new ID if not provided
+	if card.ID == uuid.Nil {
+		card.ID = uuid.New()
+	}
+	createdAt := now()
+
+	stats, err := gameCardStats(card)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.withTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO game_cards (`+gameCardColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1, ?, ?)`,
+			card.ID, card.Name, card.Subtitle, card.Cost, card.Type, card.Offense, card.Defense,
+			card.IsResource, card.FrontImageURL, card.BackImageURL, nullUUID(card.GameID), stats,
+			dbTime(createdAt), dbTime(createdAt))
+		if err != nil {
+			if s.isDuplicateKey(err) {
+				return alreadyExists("card")
+			}
+			return err
+		}
+		return s.writeGameCardChildren(ctx, tx, card)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetGameCard(ctx, card.ID)
+}
+
+// UpdateGameCard updates an existing card in storage
+func (s *sqlStorage) UpdateGameCard(ctx context.Context, card models.GameCard) (_ *models.GameCard, err error) {
+	defer translateError(&err, s.isUnavailable)
+	stats, err := gameCardStats(card)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.withTx(ctx, func(tx *sql.Tx) error {
+		version, err := s.lockVersion(ctx, tx, "game_cards", card.ID, card.Version)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE game_cards SET name = ?, subtitle = ?, cost = ?,
+			type = ?, offense = ?, defense = ?, is_resource = ?, front_image_url = ?,
+			back_image_url = ?, game_id = ?, stats = ?, version = ?, updated_at = ? WHERE id = ?`,
+			card.Name, card.Subtitle, card.Cost, card.Type, card.Offense, card.Defense,
+			card.IsResource, card.FrontImageURL, card.BackImageURL, nullUUID(card.GameID), stats,
+			version+1, dbTime(now()), card.ID)
+		if err != nil {
+			return err
+		}
+		return s.writeGameCardChildren(ctx, tx, card)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetGameCard(ctx, card.ID)
+}
+
+// DeleteGameCard removes a card from storage
+func (s *sqlStorage) DeleteGameCard(ctx context.Context, id uuid.UUID, version int) (err error) {
+	defer translateError(&err, s.isUnavailable)
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := s.lockVersion(ctx, tx, "game_cards", id, version); err != nil {
+			return err
+		}
+		for _, table := range []string{"game_card_keywords", "game_card_colors", "game_card_printings"} {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE card_id = ?`, id); err != nil {
+				return err
+			}
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM game_cards WHERE id = ?`, id)
+		return err
+	})
+}
+
+// ImageCard operations
+
+const imageCardColumns = `id, name, description, front_image_url, back_image_url, version, created_at, updated_at`
+
+func scanImageCard(row rowScanner) (*models.ImageCard, error) {
+	var card models.ImageCard
+	err := row.Scan(&card.ID, &card.Name, &card.Description,
+		&card.FrontImageURL, &card.BackImageURL, &card.Version, timeColumn{&card.CreatedAt}, timeColumn{&card.UpdatedAt})
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// ListImageCards returns all image cards
+func (s *sqlStorage) ListImageCards(ctx context.Context) (_ []*models.ImageCard, err error) {
+	defer translateError(&err, s.isUnavailable)
+	rows, err := s.db.QueryContext(ctx, `SELECT `+imageCardColumns+` FROM image_cards`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	cards := []*models.ImageCard{}
+	for rows.Next() {
+		card, err := scanImageCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, rows.Err()
+}
+
+// ListImageCardsPage returns one page of image cards
+func (s *sqlStorage) ListImageCardsPage(ctx context.Context, page PageRequest) (_ *Page[*models.ImageCard], err error) {
+	defer translateError(&err, s.isUnavailable)
+	q, err := parsePageRequest(page, SortByName, SortByCreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + imageCardColumns + ` FROM image_cards`
+	conditions, args, tail := pageConditions(q)
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	rows, err := s.db.QueryContext(ctx, query+tail, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	cards := []*models.ImageCard{}
+	for rows.Next() {
+		card, err := scanImageCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &Page[*models.ImageCard]{Items: cards}
+	if len(cards) > q.limit {
+		result.Items = cards[:q.limit]
+		result.NextCursor = q.cursorAfter(imageCardPosition(result.Items[q.limit-1], q.sort))
+	}
+	return result, nil
+}
+
+// GetImageCard returns a specific image card by ID
+func (s *sqlStorage) GetImageCard(ctx context.Context, id uuid.UUID) (_ *models.ImageCard, err error) {
+	defer translateError(&err, s.isUnavailable)
+	row := s.db.QueryRowContext(ctx, `SELECT `+imageCardColumns+` FROM image_cards WHERE id = ?`, id)
+	card, err := scanImageCard(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return card, err
+}
+
+// CreateImageCard adds a new image card to storage
+func (s *sqlStorage) CreateImageCard(ctx context.Context, card models.ImageCard) (_ *models.ImageCard, err error) {
+	defer translateError(&err, s.isUnavailable)
+
+	// Generate a new ID if not provided
+	if card.ID == uuid.Nil {
+		card.ID = uuid.New()
+	}
+
+	createdAt := now()
+	_, err = s.db.ExecContext(ctx, `INSERT INTO image_cards (`+imageCardColumns+`)
+		VALUES (?, ?, ?, ?, ?, 1, ?, ?)`,
+		card.ID, card.Name, card.Description, card.FrontImageURL, card.BackImageURL,
+		dbTime(createdAt), dbTime(createdAt))
+	if err != nil {
+		if s.isDuplicateKey(err) {
+			return nil, alreadyExists("image card")
+		}
+		return nil, err
+	}
+
+	return s.GetImageCard(ctx, card.ID)
+}
+
+// UpdateImageCard updates an existing image card in storage
+func (s *sqlStorage) UpdateImageCard(ctx context.Context, card models.ImageCard) (_ *models.ImageCard, err error) {
+	defer translateError(&err, s.isUnavailable)
+	err = s.withTx(ctx, func(tx *sql.Tx) error {
+		version, err := s.lockVersion(ctx, tx, "image_cards", card.ID, card.Version)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE image_cards SET name = ?, description = ?,
+			front_image_url = ?, back_image_url = ?, version = ?, updated_at = ? WHERE id = ?`,
+			card.Name, card.Description, card.FrontImageURL, card.BackImageURL,
+			version+1, dbTime(now()), card.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetImageCard(ctx, card.ID)
+}
+
+// DeleteImageCard removes an image card from storage
+func (s *sqlStorage) DeleteImageCard(ctx context.Context, id uuid.UUID, version int) (err error) {
+	defer translateError(&err, s.isUnavailable)
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := s.lockVersion(ctx, tx, "image_cards", id, version); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM image_cards WHERE id = ?`, id)
+		return err
+	})
+}
+
+// CardSet operations
+
+const cardSetColumns = `code, name, release_date, size, created_at, updated_at`
+
+func scanCardSet(row rowScanner) (*models.CardSet, error) {
+	var set models.CardSet
+	err := row.Scan(&set.Code, &set.Name, &set.ReleaseDate, &set.Size,
+		timeColumn{&set.CreatedAt}, timeColumn{&set.UpdatedAt})
+	if err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// ListCardSets returns all card sets ordered by code
+func (s *sqlStorage) ListCardSets(ctx context.Context) (_ []*models.CardSet, err error) {
+	defer translateError(&err, s.isUnavailable)
+	rows, err := s.db.QueryContext(ctx, `SELECT `+cardSetColumns+` FROM card_sets ORDER BY code`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	sets := []*models.CardSet{}
+	for rows.Next() {
+		set, err := scanCardSet(rows)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+	return sets, rows.Err()
+}
+
+// GetCardSet returns a specific card set by code
+func (s *sqlStorage) GetCardSet(ctx context.Context, code string) (_ *models.CardSet, err error) {
+	defer translateError(&err, s.isUnavailable)
+	row := s.db.QueryRowContext(ctx, `SELECT `+cardSetColumns+` FROM card_sets WHERE code = ?`, code)
+	set, err := scanCardSet(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return set, err
+}
+
+// CreateCardSet adds a new card set to storage
+func (s *sqlStorage) CreateCardSet(ctx context.Context, set models.CardSet) (_ *models.CardSet, err error) {
+	defer translateError(&err, s.isUnavailable)
+	createdAt := now()
+	_, err = s.db.ExecContext(ctx, `INSERT INTO card_sets (`+cardSetColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		set.Code, set.Name, set.ReleaseDate, set.Size, dbTime(createdAt), dbTime(createdAt))
+	if err != nil {
+		if s.isDuplicateKey(err) {
+			return nil, alreadyExists("card set")
+		}
+		return nil, err
+	}
+
+	return s.GetCardSet(ctx, set.Code)
+}
+
+// UpdateCardSet updates an existing card set in storage
+func (s *sqlStorage) UpdateCardSet(ctx context.Context, set models.CardSet) (_ *models.CardSet, err error) {
+	defer translateError(&err, s.isUnavailable)
+	result, err := s.db.ExecContext(ctx, `UPDATE card_sets SET name = ?, release_date = ?, size = ?,
+		updated_at = ? WHERE code = ?`,
+		set.Name, set.ReleaseDate, set.Size, dbTime(now()), set.Code)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAffected(result); err != nil {
+		return nil, err
+	}
+
+	return s.GetCardSet(ctx, set.Code)
+}
+
+// DeleteCardSet removes a card set that no card is printed in. The foreign
+// key on printings refuses the delete while the set is in use, including
+// printings written by a concurrent transaction.
+func (s *sqlStorage) DeleteCardSet(ctx context.Context, code string) (err error) {
+	defer translateError(&err, s.isUnavailable)
+	result, err := s.db.ExecContext(ctx, `DELETE FROM card_sets WHERE code = ?`, code)
+	if err != nil {
+		if s.isForeignKey(err) {
+			return ErrCardSetInUse
+		}
+		return err
+	}
+	return checkAffected(result)
+}
+
+// GameDefinition operations
+
+const gameDefinitionColumns = `id, name, definition, created_at, updated_at`
+
+// scanGameDefinition reads a game definition row. The definition column
+// holds everything but the ID, name and timestamps.
+func scanGameDefinition(row rowScanner) (*models.GameDefinition, error) {
+	var id uuid.UUID
+	var name string
+	var data []byte
+	var createdAt, updatedAt time.Time
+	if err := row.Scan(&id, &name, &data, timeColumn{&createdAt}, timeColumn{&updatedAt}); err != nil {
+		return nil, err
+	}
+
+	var game models.GameDefinition
+	if err := json.Unmarshal(data, &game); err != nil {
+		return nil, fmt.Errorf("decode game definition %s: %w", id, err)
+	}
+	game.ID, game.Name, game.CreatedAt, game.UpdatedAt = id, name, createdAt, updatedAt
+	return &game, nil
+}
+
+// ListGameDefinitions returns all game definitions ordered by name
+func (s *sqlStorage) ListGameDefinitions(ctx context.Context) (_ []*models.GameDefinition, err error) {
+	defer translateError(&err, s.isUnavailable)
+	rows, err := s.db.QueryContext(ctx, `SELECT `+gameDefinitionColumns+` FROM game_definitions ORDER BY name, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	games := []*models.GameDefinition{}
+	for rows.Next() {
+		game, err := scanGameDefinition(rows)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+	return games, rows.Err()
+}
+
+// GetGameDefinition returns a specific game definition by ID
+func (s *sqlStorage) GetGameDefinition(ctx context.Context, id uuid.UUID) (_ *models.GameDefinition, err error) {
+	defer translateError(&err, s.isUnavailable)
+	row := s.db.QueryRowContext(ctx, `SELECT `+gameDefinitionColumns+` FROM game_definitions WHERE id = ?`, id)
+	game, err := scanGameDefinition(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return game, err
+}
+
+// CreateGameDefinition adds a new game definition to storage
+func (s *sqlStorage) CreateGameDefinition(ctx context.Context, game models.GameDefinition) (_ *models.GameDefinition, err error) {
+	defer translateError(&err, s.isUnavailable)
+
+	// Generate a new ID if not provided
+	if game.ID == uuid.Nil {
+		game.ID = uuid.New()
+	}
+
+	data, err := json.Marshal(game)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := now()
+	_, err = s.db.ExecContext(ctx, `INSERT INTO game_definitions (`+gameDefinitionColumns+`) VALUES (?, ?, ?, ?, ?)`,
+		game.ID, game.Name, data, dbTime(createdAt), dbTime(createdAt))
+	if err != nil {
+		if s.isDuplicateKey(err) {
+			return nil, alreadyExists("game definition")
+		}
+		return nil, err
+	}
+
+	return s.GetGameDefinition(ctx, game.ID)
+}
+
+// UpdateGameDefinition updates an existing game definition in storage
+func (s *sqlStorage) UpdateGameDefinition(ctx context.Context, game models.GameDefinition) (_ *models.GameDefinition, err error) {
+	defer translateError(&err, s.isUnavailable)
+	data, err := json.Marshal(game)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.ExecContext(ctx, `UPDATE game_definitions SET name = ?, definition = ?,
+		updated_at = ? WHERE id = ?`,
+		game.Name, data, dbTime(now()), game.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAffected(result); err != nil {
+		return nil, err
+	}
+
+	return s.GetGameDefinition(ctx, game.ID)
+}
+
+// DeleteGameDefinition removes a game definition that no card belongs to
+func (s *sqlStorage) DeleteGameDefinition(ctx context.Context, id uuid.UUID) (err error) {
+	defer translateError(&err, s.isUnavailable)
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		var exists int
+		err := tx.QueryRowContext(ctx, `SELECT 1 FROM game_definitions WHERE id = ?`+s.lockForUpdate, id).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRowContext(ctx, `SELECT 1 FROM game_cards WHERE game_id = ? LIMIT 1`, id).Scan(&exists)
+		if err == nil {
+			return ErrGameDefinitionInUse
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM game_definitions WHERE id = ?`, id)
+		return err
+	})
+}
+
+// Registry operations
+
+const registryEntryColumns = `name, aliases, reminder_text, icon_url, created_at, updated_at`
+
+// registryTables maps each registry to the table holding it
+var registryTables = map[string]string{
+	models.RegistryKeywords: "keywords",
+	models.RegistryColors:   "colors",
+}
+
+// registryTable returns the table holding registry
+func registryTable(registry string) (string, error) {
+	table, ok := registryTables[registry]
+	if !ok {
+		return "", ErrUnknownRegistry
+	}
+	return table, nil
+}
+
+func scanRegistryEntry(row rowScanner) (*models.RegistryEntry, error) {
+	var entry models.RegistryEntry
+	var aliases []byte
+	err := row.Scan(&entry.Name, &aliases, &entry.ReminderText, &entry.IconURL,
+		timeColumn{&entry.CreatedAt}, timeColumn{&entry.UpdatedAt})
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(aliases, &entry.Aliases); err != nil {
+		return nil, fmt.Errorf("decode aliases of %s: %w", entry.Name, err)
+	}
+	if entry.Aliases == nil {
+		entry.Aliases = []string{}
+	}
+	return &entry, nil
+}
+
+// registryAliases converts the aliases of an entry to a column value
+func registryAliases(entry models.RegistryEntry) ([]byte, error) {
+	if entry.Aliases == nil {
+		entry.Aliases = []string{}
+	}
+	return json.Marshal(entry.Aliases)
+}
+
+// ListRegistryEntries returns all entries of a registry ordered by name
+func (s *sqlStorage) ListRegistryEntries(ctx context.Context, registry string) (_ []*models.RegistryEntry, err error) {
+	defer translateError(&err, s.isUnavailable)
+	table, err := registryTable(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+registryEntryColumns+` FROM `+table+` ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	entries := []*models.RegistryEntry{}
+	for rows.Next() {
+		entry, err := scanRegistryEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// GetRegistryEntry returns a specific registry entry by name
+func (s *sqlStorage) GetRegistryEntry(ctx context.Context, registry, name string) (_ *models.RegistryEntry, err error) {
+	defer translateError(&err, s.isUnavailable)
+	table, err := registryTable(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	row := s.db.QueryRowContext(ctx, `SELECT `+registryEntryColumns+` FROM `+table+` WHERE name = ?`, name)
+	entry, err := scanRegistryEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return entry, err
+}
+
+// CreateRegistryEntry adds a new entry to a registry
+func (s *sqlStorage) CreateRegistryEntry(ctx context.Context, registry string, entry models.RegistryEntry) (_ *models.RegistryEntry, err error) {
+	defer translateError(&err, s.isUnavailable)
+	table, err := registryTable(registry)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := registryAliases(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := now()
+	_, err = s.db.ExecContext(ctx, `INSERT INTO `+table+` (`+registryEntryColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		entry.Name, aliases, entry.ReminderText, entry.IconURL, dbTime(createdAt), dbTime(createdAt))
+	if err != nil {
+		if s.isDuplicateKey(err) {
+			return nil, alreadyExists("registry entry")
+		}
+		return nil, err
+	}
+
+	return s.GetRegistryEntry(ctx, registry, entry.Name)
+}
+
+// UpdateRegistryEntry updates an existing registry entry
+func (s *sqlStorage) UpdateRegistryEntry(ctx context.Context, registry string, entry models.RegistryEntry) (_ *models.RegistryEntry, err error) {
+	defer translateError(&err, s.isUnavailable)
+	table, err := registryTable(registry)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := registryAliases(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.ExecContext(ctx, `UPDATE `+table+` SET aliases = ?, reminder_text = ?, icon_url = ?,
+		updated_at = ? WHERE name = ?`,
+		aliases, entry.ReminderText, entry.IconURL, dbTime(now()), entry.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAffected(result); err != nil {
+		return nil, err
+	}
+
+	return s.GetRegistryEntry(ctx, registry, entry.Name)
+}
+
+// DeleteRegistryEntry removes an entry from a registry
+func (s *sqlStorage) DeleteRegistryEntry(ctx context.Context, registry, name string) (err error) {
+	defer translateError(&err, s.isUnavailable)
+	table, err := registryTable(registry)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM `+table+` WHERE name = ?`, name)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
+// PlayingCard operations
+
+const playingCardColumns = `id, suite, value, front_image_url, back_image_url, created_at, updated_at`
+
+func scanPlayingCard(row rowScanner) (*models.PlayingCard, error) {
+	var card models.PlayingCard
+	err := row.Scan(&card.ID, &card.Suite, &card.Value,
+		&card.FrontImageURL, &card.BackImageURL, timeColumn{&card.CreatedAt}, timeColumn{&card.UpdatedAt})
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// ListPlayingCards returns all playing cards
+func (s *sqlStorage) ListPlayingCards(ctx context.Context) (_ []*models.PlayingCard, err error) {
+	defer translateError(&err, s.isUnavailable)
+	rows, err := s.db.QueryContext(ctx, `SELECT `+playingCardColumns+` FROM playing_cards`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	cards := []*models.PlayingCard{}
+	for rows.Next() {
+		card, err := scanPlayingCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, rows.Err()
+}
+
+// GetPlayingCard returns a specific playing card by ID
+func (s *sqlStorage) GetPlayingCard(ctx context.Context, id uuid.UUID) (_ *models.PlayingCard, err error) {
+	defer translateError(&err, s.isUnavailable)
+	row := s.db.QueryRowContext(ctx, `SELECT `+playingCardColumns+` FROM playing_cards WHERE id = ?`, id)
+	card, err := scanPlayingCard(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return card, err
+}
+
+// CreatePlayingCard adds a new playing card to storage
+func (s *sqlStorage) CreatePlayingCard(ctx context.Context, card models.PlayingCard) (_ *models.PlayingCard, err error) {
+	defer translateError(&err, s.isUnavailable)
+
+	// Generate a new ID if not provided
+	if card.ID == uuid.Nil {
+		card.ID = uuid.New()
+	}
+	createdAt := now()
+
+	_, err = s.db.ExecContext(ctx, `INSERT INTO playing_cards (`+playingCardColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		card.ID, card.Suite, card.Value, card.FrontImageURL, card.BackImageURL,
+		dbTime(createdAt), dbTime(createdAt))
+	if err != nil {
+		if s.isDuplicateKey(err) {
+			return nil, alreadyExists("playing card")
+		}
+		return nil, err
+	}
+
+	return s.GetPlayingCard(ctx, card.ID)
+}
+
+// UpdatePlayingCard updates an existing playing card in storage
+func (s *sqlStorage) UpdatePlayingCard(ctx context.Context, card models.PlayingCard) (_ *models.PlayingCard, err error) {
+	defer translateError(&err, s.isUnavailable)
+	result, err := s.db.ExecContext(ctx, `UPDATE playing_cards SET suite = ?, value = ?,
+		front_image_url = ?, back_image_url = ?, updated_at = ? WHERE id = ?`,
+		card.Suite, card.Value, card.FrontImageURL, card.BackImageURL, dbTime(now()), card.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAffected(result); err != nil {
+		return nil, err
+	}
+
+	return s.GetPlayingCard(ctx, card.ID)
+}
+
+// DeletePlayingCard removes a playing card from storage
+func (s *sqlStorage) DeletePlayingCard(ctx context.Context, id uuid.UUID) (err error) {
+	defer translateError(&err, s.isUnavailable)
+	result, err := s.db.ExecContext(ctx, `DELETE FROM playing_cards WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
+// Deck operations
+
+const deckColumns = `id, name, owner_id, sleeve_image_url, back_image_url, version, created_at, updated_at`
+
+func scanDeck(row rowScanner) (*models.Deck, error) {
+	var deck models.Deck
+	var ownerID uuid.NullUUID
+	var sleeveImageURL, backImageURL sql.NullString
+	err := row.Scan(&deck.ID, &deck.Name, &ownerID, &sleeveImageURL, &backImageURL,
+		&deck.Version, timeColumn{&deck.CreatedAt}, timeColumn{&deck.UpdatedAt})
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerID.Valid {
+		deck.OwnerID = &ownerID.UUID
+	}
+	if sleeveImageURL.Valid {
+		deck.SleeveImageURL = &sleeveImageURL.String
+	}
+	if backImageURL.Valid {
+		deck.BackImageURL = &backImageURL.String
+	}
+	deck.Entries = []models.DeckEntry{}
+	deck.Cards = []uuid.UUID{}
+	return &deck, nil
+}
+
+// loadDeckCards fills in the entries of the given decks. Decks written
+// before entries have a row per copy, which Normalize merges.
+func (s *sqlStorage) loadDeckCards(ctx context.Context, decks map[uuid.UUID]*models.Deck, where string, args ...any) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT deck_id, card_id, card_type, quantity, section
+		FROM deck_cards`+where+` ORDER BY deck_id, position`, args...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var deckID uuid.UUID
+		var entry models.DeckEntry
+		if err := rows.Scan(&deckID, &entry.CardID, &entry.CardType, &entry.Quantity, &entry.Section); err != nil {
+			return err
+		}
+		if deck, ok := decks[deckID]; ok {
+			deck.Entries = append(deck.Entries, entry)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, deck := range decks {
+		deck.Normalize()
+	}
+	return nil
+}
+
+// writeDeckCards replaces the entries of a deck
+func writeDeckCards(ctx context.Context, tx *sql.Tx, deck models.Deck) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM deck_cards WHERE deck_id = ?`, deck.ID); err != nil {
+		return err
+	}
+	for i, entry := range deck.Entries {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO deck_cards
+			(deck_id, position, card_id, card_type, quantity, section) VALUES (?, ?, ?, ?, ?, ?)`,
+			deck.ID, i, entry.CardID, entry.CardType, entry.Quantity, entry.Section); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nullUUID converts an optional UUID to a nullable column value
+func nullUUID(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
+
+// nullString converts an optional string to a nullable column value
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+// ListDecks returns all decks, optionally filtered by owner
+func (s *sqlStorage) ListDecks(ctx context.Context, ownerID *uuid.UUID) (_ []*models.Deck, err error) {
+	defer translateError(&err, s.isUnavailable)
+	query := `SELECT ` + deckColumns + ` FROM decks`
+	var args []any
+	if ownerID != nil {
+		query += ` WHERE owner_id = ?`
+		args = append(args, *ownerID)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	decks := []*models.Deck{}
+	byID := make(map[uuid.UUID]*models.Deck)
+	for rows.Next() {
+		deck, err := scanDeck(rows)
+		if err != nil {
+			return nil, err
+		}
+		decks = append(decks, deck)
+		byID[deck.ID] = deck
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(decks) == 0 {
+		return decks, nil
+	}
+	where := ""
+	if ownerID != nil {
+		where = ` WHERE deck_id IN (SELECT id FROM decks WHERE owner_id = ?)`
+	}
+	if err := s.loadDeckCards(ctx, byID, where, args...); err != nil {
+		return nil, err
+	}
+	return decks, nil
+}
+
+// GetDeck returns a specific deck by ID
+func (s *sqlStorage) GetDeck(ctx context.Context, id uuid.UUID) (_ *models.Deck, err error) {
+	defer translateError(&err, s.isUnavailable)
+	row := s.db.QueryRowContext(ctx, `SELECT `+deckColumns+` FROM decks WHERE id = ?`, id)
+	deck, err := scanDeck(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	byID := map[uuid.UUID]*models.Deck{deck.ID: deck}
+	if err := s.loadDeckCards(ctx, byID, ` WHERE deck_id = ?`, id); err != nil {
+		return nil, err
+	}
+	return deck, nil
+}
+
+// CreateDeck adds a new deck to storage
+func (s *sqlStorage) CreateDeck(ctx context.Context, deck models.Deck) (_ *models.Deck, err error) {
+	defer translateError(&err, s.isUnavailable)
+
+	// Generate a new ID if not provided
+	if deck.ID == uuid.Nil {
+		deck.ID = uuid.New()
+	}
+	normalizeDeck(&deck)
+	createdAt := now()
+
+	err = s.withTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO decks (`+deckColumns+`)
+			VALUES (?, ?, ?, ?, ?, 1, ?, ?)`,
+			deck.ID, deck.Name, nullUUID(deck.OwnerID), nullString(deck.SleeveImageURL),
+			nullString(deck.BackImageURL), dbTime(createdAt), dbTime(createdAt))
+		if err != nil {
+			if s.isDuplicateKey(err) {
+				return alreadyExists("deck")
+			}
+			return err
+		}
+		return writeDeckCards(ctx, tx, deck)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetDeck(ctx, deck.ID)
+}
+
+// UpdateDeck updates an existing deck in storage
+func (s *sqlStorage) UpdateDeck(ctx context.Context, deck models.Deck) (_ *models.Deck, err error) {
+	defer translateError(&err, s.isUnavailable)
+	normalizeDeck(&deck)
+	err = s.withTx(ctx, func(tx *sql.Tx) error {
+		version, err := s.lockVersion(ctx, tx, "decks", deck.ID, deck.Version)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE decks SET name = ?, owner_id = ?,
+			sleeve_image_url = ?, back_image_url = ?, version = ?, updated_at = ? WHERE id = ?`,
+			deck.Name, nullUUID(deck.OwnerID), nullString(deck.SleeveImageURL),
+			nullString(deck.BackImageURL), version+1, dbTime(now()), deck.ID)
+		if err != nil {
+			return err
+		}
+		return writeDeckCards(ctx, tx, deck)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetDeck(ctx, deck.ID)
+}
+
+// DeleteDeck removes a deck from storage
+func (s *sqlStorage) DeleteDeck(ctx context.Context, id uuid.UUID, version int) (err error) {
+	defer translateError(&err, s.isUnavailable)
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := s.lockVersion(ctx, tx, "decks", id, version); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM deck_cards WHERE deck_id = ?`, id); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM decks WHERE id = ?`, id)
+		return err
+	})
+}
+
+// DeckState operations
+//
+// Deck states are stored as a JSON document alongside indexed id and deck_id
+// columns. They are only ever read and written whole.
+
+// GetDeckState returns a specific deck state by ID
+func (s *sqlStorage) GetDeckState(ctx context.Context, id uuid.UUID) (_ *models.DeckState, err error) {
+	defer translateError(&err, s.isUnavailable)
+	return scanDeckState(s.db.QueryRowContext(ctx, `SELECT data FROM deck_states WHERE id = ?`, id), id)
+}
+
+// scanDeckState decodes the data column of a deck state row
+func scanDeckState(row rowScanner, id uuid.UUID) (*models.DeckState, error) {
+	var data []byte
+	err := row.Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state models.DeckState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("decode deck state %s: %w", id, err)
+	}
+	return &state, nil
+}
+
+// CreateDeckState adds a new deck state to storage
+func (s *sqlStorage) CreateDeckState(ctx context.Context, state models.DeckState) (_ *models.DeckState, err error) {
+	defer translateError(&err, s.isUnavailable)
+
+	// Generate a new ID if not provided
+	if state.ID == uuid.Nil {
+		state.ID = uuid.New()
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.ExecContext(ctx, `INSERT INTO deck_states (id, deck_id, data, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		state.ID, state.DeckID, data, dbTime(state.CreatedAt), dbTime(state.UpdatedAt))
+	if err != nil {
+		if s.isDuplicateKey(err) {
+			return nil, alreadyExists("deck state")
+		}
+		return nil, err
+	}
+
+	return s.GetDeckState(ctx, state.ID)
+}
+
+// UpdateDeckState updates an existing deck state in storage
+func (s *sqlStorage) UpdateDeckState(ctx context.Context, state models.DeckState) (_ *models.DeckState, err error) {
+	defer translateError(&err, s.isUnavailable)
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.ExecContext(ctx, `UPDATE deck_states SET deck_id = ?, data = ?,
+		created_at = ?, updated_at = ? WHERE id = ?`,
+		state.DeckID, data, dbTime(state.CreatedAt), dbTime(state.UpdatedAt), state.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAffected(result); err != nil {
+		return nil, err
+	}
+
+	return s.GetDeckState(ctx, state.ID)
+}
+
+// ModifyDeckState applies fn to a deck state inside a transaction that holds
+// a lock on its row
+func (s *sqlStorage) ModifyDeckState(ctx context.Context, id uuid.UUID, fn func(state *models.DeckState) error) (_ *models.DeckState, err error) {
+	defer translateError(&err, s.isUnavailable)
+	var modified *models.DeckState
+	err = s.withTx(ctx, func(tx *sql.Tx) error {
+		state, err := scanDeckState(tx.QueryRowContext(ctx,
+			`SELECT data FROM deck_states WHERE id = ?`+s.lockForUpdate, id), id)
+		if err != nil {
+			return err
+		}
+		if err := fn(state); err != nil {
+			return err
+		}
+		state.ID = id
+
+		data, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE deck_states SET deck_id = ?, data = ?,
+			created_at = ?, updated_at = ? WHERE id = ?`,
+			state.DeckID, data, dbTime(state.CreatedAt), dbTime(state.UpdatedAt), id)
+		modified = state
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return modified, nil
+}
+
+// DeleteDeckState removes a deck state from storage
+func (s *sqlStorage) DeleteDeckState(ctx context.Context, id uuid.UUID) (err error) {
+	defer translateError(&err, s.isUnavailable)
+	result, err := s.db.ExecContext(ctx, `DELETE FROM deck_states WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+)
+
+// The tests in this file run against every SQL dialect; see mysql_test.go
+// and sqlite_test.go
+
+func testSQLStorageGameCards(t *testing.T, s *sqlStorage) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	created, err := s.CreateGameCard(ctx, models.GameCard{
+		Name:     "Ancient Wyrm",
+		Cost:     7,
+		Keywords: []string{"Flying", "Trample"},
+		Colors:   []string{"Red"},
+	})
+	if err != nil {
+		t.Fatalf("CreateGameCard: %v", err)
+	}
+	if created.ID == uuid.Nil {
+		t.Fatal("Expected a generated ID")
+	}
+	if _, err := s.CreateGameCard(ctx, *created); err == nil {
+		t.Error("Expected duplicate create to fail")
+	}
+
+	got, err := s.GetGameCard(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetGameCard: %v", err)
+	}
+	if got.Name != "Ancient Wyrm" || len(got.Keywords) != 2 || got.Keywords[1] != "Trample" || got.CreatedAt.Before(now) {
+		t.Errorf("Unexpected card read back: %+v", got)
+	}
+
+	got.Keywords = []string{"Flying"}
+	got.Colors = nil
+	updated, err := s.UpdateGameCard(ctx, *got)
+	if err != nil {
+		t.Fatalf("UpdateGameCard: %v", err)
+	}
+	if len(updated.Keywords) != 1 || len(updated.Colors) != 0 {
+		t.Errorf("Expected child rows to be replaced, got %+v", updated)
+	}
+	// An update that changes nothing still finds the row
+	if _, err := s.UpdateGameCard(ctx, *updated); err != nil {
+		t.Errorf("Expected no-op update to succeed, got %v", err)
+	}
+
+	cards, err := s.ListGameCards(ctx, "gamecard")
+	if err != nil || len(cards) != 1 {
+		t.Errorf("ListGameCards: got %d cards, err %v", len(cards), err)
+	}
+
+	if err := s.DeleteGameCard(ctx, created.ID, 0); err != nil {
+		t.Fatalf("DeleteGameCard: %v", err)
+	}
+	if _, err := s.GetGameCard(ctx, created.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+	if err := s.DeleteGameCard(ctx, created.ID, 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+	}
+}
+
+func testSQLStorageImageAndPlayingCards(t *testing.T, s *sqlStorage) {
+	ctx := context.Background()
+
+	imageCard, err := s.CreateImageCard(ctx, models.ImageCard{Name: "Sunset", Description: "Orange"})
+	if err != nil {
+		t.Fatalf("CreateImageCard: %v", err)
+	}
+	imageCard.Description = "Purple"
+	if updated, err := s.UpdateImageCard(ctx, *imageCard); err != nil || updated.Description != "Purple" {
+		t.Errorf("UpdateImageCard: got %+v, err %v", updated, err)
+	}
+
+	playingCard, err := s.CreatePlayingCard(ctx, models.PlayingCard{Suite: models.SuiteSpades, Value: 1})
+	if err != nil {
+		t.Fatalf("CreatePlayingCard: %v", err)
+	}
+	if got, err := s.GetPlayingCard(ctx, playingCard.ID); err != nil || got.GetName() != "Ace of Spades" {
+		t.Errorf("GetPlayingCard: got %+v, err %v", got, err)
+	}
+
+	if _, err := s.UpdateImageCard(ctx, models.ImageCard{ID: uuid.New()}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound updating a missing card, got %v", err)
+	}
+	if err := s.DeletePlayingCard(ctx, playingCard.ID); err != nil {
+		t.Errorf("DeletePlayingCard: %v", err)
+	}
+}
+
+func testSQLStorageDecksAndStates(t *testing.T, s *sqlStorage) {
+	ctx := context.Background()
+
+	ownerID := uuid.New()
+	cardID := uuid.New()
+	deck, err := s.CreateDeck(ctx, models.Deck{
+		Name:    "Burn",
+		OwnerID: &ownerID,
+		Cards:   []uuid.UUID{cardID, cardID, uuid.New()},
+	})
+	if err != nil {
+		t.Fatalf("CreateDeck: %v", err)
+	}
+	if _, err := s.CreateDeck(ctx, models.Deck{Name: "Unowned"}); err != nil {
+		t.Fatalf("CreateDeck: %v", err)
+	}
+
+	owned, err := s.ListDecks(ctx, &ownerID)
+	if err != nil || len(owned) != 1 || len(owned[0].Cards) != 3 || owned[0].Cards[1] != cardID {
+		t.Errorf("ListDecks by owner: got %+v, err %v", owned, err)
+	}
+	all, err := s.ListDecks(ctx, nil)
+	if err != nil || len(all) != 2 {
+		t.Errorf("ListDecks: got %d decks, err %v", len(all), err)
+	}
+
+	// Decks written before entries have one row per copy
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO deck_cards (deck_id, position, card_id) VALUES (?, 3, ?)`,
+		deck.ID, cardID); err != nil {
+		t.Fatalf("insert a flat deck row: %v", err)
+	}
+	got, err := s.GetDeck(ctx, deck.ID)
+	if err != nil || got.Copies(cardID, models.SectionMain) != 3 || len(got.Entries) != 2 || len(got.Cards) != 4 {
+		t.Errorf("GetDeck with a flat row: got %+v, err %v", got, err)
+	}
+
+	state := models.NewDeckState(*deck)
+	if err := state.Shuffle("sql"); err != nil {
+		t.Fatal(err)
+	}
+	createdState, err := s.CreateDeckState(ctx, *state)
+	if err != nil {
+		t.Fatalf("CreateDeckState: %v", err)
+	}
+	createdState.Draw(1)
+	updatedState, err := s.UpdateDeckState(ctx, *createdState)
+	if err != nil {
+		t.Fatalf("UpdateDeckState: %v", err)
+	}
+	if len(updatedState.DrawPile) != 2 || len(updatedState.Drawn) != 1 || updatedState.LastShuffle.Seed != "sql" {
+		t.Errorf("Unexpected deck state read back: %+v", updatedState)
+	}
+
+	modifiedState, err := s.ModifyDeckState(ctx, createdState.ID, func(state *models.DeckState) error {
+		state.Draw(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ModifyDeckState: %v", err)
+	}
+	if got, err := s.GetDeckState(ctx, createdState.ID); err != nil || len(got.Drawn) != 2 || len(modifiedState.DrawPile) != 1 {
+		t.Errorf("ModifyDeckState: got %+v, err %v", got, err)
+	}
+
+	if err := s.DeleteDeck(ctx, deck.ID, 0); err != nil {
+		t.Fatalf("DeleteDeck: %v", err)
+	}
+	if err := s.DeleteDeckState(ctx, createdState.ID); err != nil {
+		t.Fatalf("DeleteDeckState: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+
+	"github.com/jwebster45206/tcg-api/internal/config"
+	"github.com/jwebster45206/tcg-api/internal/migrations"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// NewSQLiteStorage opens the SQLite database file described by cfg, creating
+// it if needed. Pending migrations are applied on open, so a single binary
+// needs no separate setup step.
+func NewSQLiteStorage(ctx context.Context, cfg config.SQLiteConfig) (Storage, error) {
+	db, err := sql.Open("sqlite", SQLiteDSN(cfg))
+	if err != nil {
+		return nil, err
+	}
+	s := newSQLiteStorage(db)
+
+	m, err := migrations.NewForDriver(db, config.StorageDriverSQLite)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	if _, err := m.Up(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// SQLiteDSN builds a modernc.org/sqlite data source name from cfg
+func SQLiteDSN(cfg config.SQLiteConfig) string {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Set("_time_format", "sqlite")
+	return "file:" + cfg.Path + "?" + params.Encode()
+}
+
+// newSQLiteStorage wraps an open SQLite connection pool
+func newSQLiteStorage(db *sql.DB) *sqlStorage {
+	// SQLite allows a single writer. One connection serializes transactions,
+	// which is what ModifyDeckState relies on in place of row locks, and keeps
+	// a ":memory:" database from being split across connections.
+	db.SetMaxOpenConns(1)
+
+	return &sqlStorage{
+		db: db,
+		isDuplicateKey: func(err error) bool {
+			var sqliteErr *sqlite.Error
+			return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+		},
+		isForeignKey: func(err error) bool {
+			var sqliteErr *sqlite.Error
+			return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+		},
+		// The database stayed locked by another process past busy_timeout
+		isUnavailable: func(err error) bool {
+			var sqliteErr *sqlite.Error
+			if !errors.As(err, &sqliteErr) {
+				return false
+			}
+			primary := sqliteErr.Code() & 0xff
+			return primary == sqlite3.SQLITE_BUSY || primary == sqlite3.SQLITE_LOCKED
+		},
+	}
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/jwebster45206/tcg-api/internal/config"
+	"github.com/jwebster45206/tcg-api/internal/migrations"
+	"github.com/jwebster45206/tcg-api/internal/models"
+)
+
+// newTestSQLiteStorage opens a migrated SQLite database in a temporary file
+func newTestSQLiteStorage(t *testing.T) *sqlStorage {
+	t.Helper()
+	return openTestSQLiteStorage(t, filepath.Join(t.TempDir(), "tcg.db"))
+}
+
+// openTestSQLiteStorage opens the SQLite database at path, applying any
+// pending migrations
+func openTestSQLiteStorage(t *testing.T, path string) *sqlStorage {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", SQLiteDSN(config.SQLiteConfig{Path: path}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	m, err := migrations.NewForDriver(db, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+
+	return newSQLiteStorage(db)
+}
+
+func TestSQLiteStorage_GameCards(t *testing.T) {
+	testSQLStorageGameCards(t, newTestSQLiteStorage(t))
+}
+
+func TestSQLiteStorage_ImageAndPlayingCards(t *testing.T) {
+	testSQLStorageImageAndPlayingCards(t, newTestSQLiteStorage(t))
+}
+
+func TestSQLiteStorage_DecksAndStates(t *testing.T) {
+	testSQLStorageDecksAndStates(t, newTestSQLiteStorage(t))
+}
+
+func TestSQLiteStorage_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tcg.db")
+	ctx := context.Background()
+
+	s := openTestSQLiteStorage(t, path)
+	card, err := s.CreateGameCard(ctx, models.GameCard{Name: "Phoenix", Keywords: []string{"Flying"}})
+	if err != nil {
+		t.Fatalf("CreateGameCard: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reopened := openTestSQLiteStorage(t, path)
+	got, err := reopened.GetGameCard(ctx, card.ID)
+	if err != nil {
+		t.Fatalf("GetGameCard after reopening: %v", err)
+	}
+	if got.Name != "Phoenix" || len(got.Keywords) != 1 {
+		t.Errorf("Unexpected card after reopening: %+v", got)
+	}
+}
+
+func TestNewSQLiteStorage(t *testing.T) {
+	ctx := context.Background()
+
+	// The schema is created on open
+	sto, err := NewSQLiteStorage(ctx, config.SQLiteConfig{Path: ":memory:"})
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage: %v", err)
+	}
+	defer func() { _ = sto.(*sqlStorage).Close() }()
+
+	if _, err := sto.CreateDeck(ctx, models.Deck{Name: "In Memory"}); err != nil {
+		t.Errorf("CreateDeck: %v", err)
+	}
+}
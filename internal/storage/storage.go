@@ -2,33 +2,202 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"io"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/query"
 )
 
+// Storage persists the API's records.
+//
+// Game cards, image cards and decks are versioned. Storage owns their
+// timestamps and version: Create sets CreatedAt and UpdatedAt to the current
+// time and Version to 1, whatever the caller passed, and Update keeps
+// CreatedAt, sets UpdatedAt and adds 1 to Version. The Version passed to
+// Update, and the version passed to Delete, is the version the caller last
+// read; the write fails with ErrVersionMismatch unless it is still current.
+// Version 0 skips the check.
+//
+// Storage also owns the timestamps of playing cards, card sets, game
+// definitions and registry entries, which are not versioned:
+// Create sets CreatedAt and UpdatedAt to the current time, and Update keeps
+// CreatedAt and sets UpdatedAt.
+//
+// Decks are stored as entries, merged as by models.Deck.Normalize. A deck
+// written with nil Entries is in the flat format of older clients, and its
+// Cards become the main section.
 type Storage interface {
 	// Deck operations
 	ListDecks(ctx context.Context, ownerID *uuid.UUID) ([]*models.Deck, error)
 	GetDeck(ctx context.Context, id uuid.UUID) (*models.Deck, error)
 	CreateDeck(ctx context.Context, deck models.Deck) (*models.Deck, error)
 	UpdateDeck(ctx context.Context, deck models.Deck) (*models.Deck, error)
-	DeleteDeck(ctx context.Context, id uuid.UUID) error
+	DeleteDeck(ctx context.Context, id uuid.UUID, version int) error
 
 	// ImageCard operations
 	ListImageCards(ctx context.Context) ([]*models.ImageCard, error)
+	// ListImageCardsPage returns one page of image cards sorted by name or
+	// creation time
+	ListImageCardsPage(ctx context.Context, page PageRequest) (*Page[*models.ImageCard], error)
 	GetImageCard(ctx context.Context, id uuid.UUID) (*models.ImageCard, error)
 	CreateImageCard(ctx context.Context, imageCard models.ImageCard) (*models.ImageCard, error)
 	UpdateImageCard(ctx context.Context, imageCard models.ImageCard) (*models.ImageCard, error)
-	DeleteImageCard(ctx context.Context, id uuid.UUID) error
+	DeleteImageCard(ctx context.Context, id uuid.UUID, version int) error
 
 	// GameCard operations
 	ListGameCards(ctx context.Context, cardType string) ([]*models.GameCard, error)
+	// ListGameCardsPage returns one page of the game cards matching filter
+	ListGameCardsPage(ctx context.Context, filter GameCardFilter, page PageRequest) (*Page[*models.GameCard], error)
+	// SearchGameCards returns one page of the game cards matching a parsed
+	// search query
+	SearchGameCards(ctx context.Context, expr query.Expr, page PageRequest) (*Page[*models.GameCard], error)
 	GetGameCard(ctx context.Context, id uuid.UUID) (*models.GameCard, error)
+	// CreateGameCard and UpdateGameCard fail with ErrUnknownCardSet if a
+	// printing is in a set that does not exist
 	CreateGameCard(ctx context.Context, card models.GameCard) (*models.GameCard, error)
 	UpdateGameCard(ctx context.Context, card models.GameCard) (*models.GameCard, error)
-	DeleteGameCard(ctx context.Context, id uuid.UUID) error
+	DeleteGameCard(ctx context.Context, id uuid.UUID, version int) error
 
-	// TODO: DeckState operations for future gameplay mechanics
-	// CreateDeckState, GetDeckState, UpdateDeckState, DeleteDeckState
+	// CardSet operations
+	ListCardSets(ctx context.Context) ([]*models.CardSet, error)
+	GetCardSet(ctx context.Context, code string) (*models.CardSet, error)
+	CreateCardSet(ctx context.Context, set models.CardSet) (*models.CardSet, error)
+	UpdateCardSet(ctx context.Context, set models.CardSet) (*models.CardSet, error)
+	// DeleteCardSet removes a set. It fails with ErrConflict while any game
+	// card has a printing in the set.
+	DeleteCardSet(ctx context.Context, code string) error
+
+	// GameDefinition operations
+	ListGameDefinitions(ctx context.Context) ([]*models.GameDefinition, error)
+	GetGameDefinition(ctx context.Context, id uuid.UUID) (*models.GameDefinition, error)
+	CreateGameDefinition(ctx context.Context, game models.GameDefinition) (*models.GameDefinition, error)
+	UpdateGameDefinition(ctx context.Context, game models.GameDefinition) (*models.GameDefinition, error)
+	// DeleteGameDefinition removes a game. It fails with ErrConflict while
+	// any game card belongs to the game.
+	DeleteGameDefinition(ctx context.Context, id uuid.UUID) error
+
+	// Registry operations. registry is one of models.Registries, and entries
+	// are keyed by name within it.
+	ListRegistryEntries(ctx context.Context, registry string) ([]*models.RegistryEntry, error)
+	GetRegistryEntry(ctx context.Context, registry, name string) (*models.RegistryEntry, error)
+	CreateRegistryEntry(ctx context.Context, registry string, entry models.RegistryEntry) (*models.RegistryEntry, error)
+	UpdateRegistryEntry(ctx context.Context, registry string, entry models.RegistryEntry) (*models.RegistryEntry, error)
+	DeleteRegistryEntry(ctx context.Context, registry, name string) error
+
+	// PlayingCard operations
+	ListPlayingCards(ctx context.Context) ([]*models.PlayingCard, error)
+	GetPlayingCard(ctx context.Context, id uuid.UUID) (*models.PlayingCard, error)
+	CreatePlayingCard(ctx context.Context, card models.PlayingCard) (*models.PlayingCard, error)
+	UpdatePlayingCard(ctx context.Context, card models.PlayingCard) (*models.PlayingCard, error)
+	DeletePlayingCard(ctx context.Context, id uuid.UUID) error
+
+	// DeckState operations
+	DeckStateStore
+}
+
+// ErrVersionMismatch is returned by writes to versioned records when the
+// stored version is not the one the caller read. It is an ErrConflict error.
+var ErrVersionMismatch error = &Error{Kind: ErrConflict, Message: "record was changed by another request"}
+
+// checkVersion returns ErrVersionMismatch unless the stored version of a
+// record is the expected one. An expected version of 0 matches any.
+func checkVersion(stored, expected int) error {
+	if expected != 0 && stored != expected {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// normalizeDeck brings a deck being written into the form storage keeps
+func normalizeDeck(deck *models.Deck) {
+	if deck.Entries == nil {
+		deck.UseCards()
+	}
+	deck.Normalize()
+}
+
+// now returns the time storage records for a write, in UTC and at the
+// microsecond precision every backend keeps
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// ErrCardSetInUse is returned by DeleteCardSet while cards are printed in
+// the set. It is an ErrConflict error.
+var ErrCardSetInUse error = &Error{Kind: ErrConflict, Message: "card set still has printings"}
+
+// ErrUnknownCardSet is returned by CreateGameCard and UpdateGameCard for a
+// printing in a set that does not exist. It is an ErrValidation error.
+var ErrUnknownCardSet error = &Error{Kind: ErrValidation, Message: "unknown card set"}
+
+// ErrGameDefinitionInUse is returned by DeleteGameDefinition while cards
+// belong to the game. It is an ErrConflict error.
+var ErrGameDefinitionInUse error = &Error{Kind: ErrConflict, Message: "game still has cards"}
+
+// ErrUnknownRegistry is returned by the registry operations for a registry
+// not in models.Registries. It is an ErrValidation error.
+var ErrUnknownRegistry error = &Error{Kind: ErrValidation, Message: "unknown registry"}
+
+// DeckStateStore holds live deck states. Every Storage is one, and
+// WithDeckStateStore moves deck states to a separate store such as Redis.
+type DeckStateStore interface {
+	GetDeckState(ctx context.Context, id uuid.UUID) (*models.DeckState, error)
+	CreateDeckState(ctx context.Context, state models.DeckState) (*models.DeckState, error)
+	UpdateDeckState(ctx context.Context, state models.DeckState) (*models.DeckState, error)
+	DeleteDeckState(ctx context.Context, id uuid.UUID) error
+
+	// ModifyDeckState atomically loads a deck state, applies fn and saves the
+	// result, so concurrent modifications never see the same starting state.
+	// If fn returns an error nothing is saved and the error is returned. Stores
+	// that retry on conflicting writes may call fn more than once.
+	ModifyDeckState(ctx context.Context, id uuid.UUID, fn func(state *models.DeckState) error) (*models.DeckState, error)
+}
+
+// WithDeckStateStore returns a Storage that keeps deck states in states and
+// everything else in base. Closing it closes both stores.
+func WithDeckStateStore(base Storage, states DeckStateStore) Storage {
+	return &splitStorage{
+		Storage: base,
+		states:  states,
+	}
+}
+
+// splitStorage overrides the deck state operations of an embedded Storage
+type splitStorage struct {
+	Storage
+	states DeckStateStore
+}
+
+func (s *splitStorage) GetDeckState(ctx context.Context, id uuid.UUID) (*models.DeckState, error) {
+	return s.states.GetDeckState(ctx, id)
+}
+
+func (s *splitStorage) CreateDeckState(ctx context.Context, state models.DeckState) (*models.DeckState, error) {
+	return s.states.CreateDeckState(ctx, state)
+}
+
+func (s *splitStorage) UpdateDeckState(ctx context.Context, state models.DeckState) (*models.DeckState, error) {
+	return s.states.UpdateDeckState(ctx, state)
+}
+
+func (s *splitStorage) DeleteDeckState(ctx context.Context, id uuid.UUID) error {
+	return s.states.DeleteDeckState(ctx, id)
+}
+
+func (s *splitStorage) ModifyDeckState(ctx context.Context, id uuid.UUID, fn func(state *models.DeckState) error) (*models.DeckState, error) {
+	return s.states.ModifyDeckState(ctx, id, fn)
+}
+
+// Close closes both underlying stores if they hold resources
+func (s *splitStorage) Close() error {
+	var errs []error
+	for _, store := range []any{s.Storage, s.states} {
+		if closer, ok := store.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,240 @@
+package storagetest
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// walkPages follows cursors from the first page to the last, returning the
+// IDs in the order they were listed
+func walkPages[T any](t *testing.T, req storage.PageRequest, id func(T) uuid.UUID,
+	list func(req storage.PageRequest) (*storage.Page[T], error)) []uuid.UUID {
+	t.Helper()
+
+	var ids []uuid.UUID
+	for pages := 0; ; pages++ {
+		if pages > 100 {
+			t.Fatal("Pagination did not terminate")
+		}
+		page, err := list(req)
+		if err != nil {
+			t.Fatalf("list page %d: %v", pages, err)
+		}
+		if len(page.Items) > req.Limit {
+			t.Fatalf("page %d: expected at most %d items, got %d", pages, req.Limit, len(page.Items))
+		}
+		for _, item := range page.Items {
+			ids = append(ids, id(item))
+		}
+		if page.NextCursor == "" {
+			return ids
+		}
+		req.Cursor = page.NextCursor
+	}
+}
+
+// expectedOrder sorts IDs by key, then ID, as every backend must
+func expectedOrder[K any](keys map[uuid.UUID]K, compare func(a, b K) int, desc bool) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(keys))
+	for id := range keys {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b uuid.UUID) int {
+		c := compare(keys[a], keys[b])
+		if c == 0 {
+			c = strings.Compare(a.String(), b.String())
+		}
+		if desc {
+			return -c
+		}
+		return c
+	})
+	return ids
+}
+
+func testGameCardPages(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	names := map[uuid.UUID]string{}
+	costs := map[uuid.UUID]int{}
+	created := map[uuid.UUID]time.Time{}
+	for i, cost := range []int{3, 1, 2, 1, 3, 0, 2} {
+		card := models.GameCard{
+			Name: "Card " + string(rune('G'-i)),
+			Cost: cost,
+		}
+		createdCard, err := s.CreateGameCard(ctx, card)
+		if err != nil {
+			t.Fatalf("CreateGameCard: %v", err)
+		}
+		names[createdCard.ID] = card.Name
+		costs[createdCard.ID] = card.Cost
+		created[createdCard.ID] = createdCard.CreatedAt
+	}
+
+	list := func(req storage.PageRequest) (*storage.Page[*models.GameCard], error) {
+		return s.ListGameCardsPage(ctx, storage.GameCardFilter{}, req)
+	}
+	id := func(card *models.GameCard) uuid.UUID { return card.ID }
+
+	for _, desc := range []bool{false, true} {
+		for _, tt := range []struct {
+			sort string
+			want []uuid.UUID
+		}{
+			{storage.SortByName, expectedOrder(names, strings.Compare, desc)},
+			{storage.SortByCost, expectedOrder(costs, cmp.Compare[int], desc)},
+			{storage.SortByCreatedAt, expectedOrder(created, time.Time.Compare, desc)},
+		} {
+			got := walkPages(t, storage.PageRequest{Limit: 3, Sort: tt.sort, Descending: desc}, id, list)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sort %s (descending %v): expected %v, got %v", tt.sort, desc, tt.want, got)
+			}
+		}
+	}
+
+	// An unset sort means by name and an unset limit means the default
+	page, err := list(storage.PageRequest{})
+	if err != nil {
+		t.Fatalf("list with defaults: %v", err)
+	}
+	if len(page.Items) != len(names) || page.NextCursor != "" {
+		t.Errorf("Expected a single page of %d cards, got %d and cursor %q", len(names), len(page.Items), page.NextCursor)
+	}
+	if want := expectedOrder(names, strings.Compare, false); page.Items[0].ID != want[0] {
+		t.Errorf("Expected the default sort to be by name")
+	}
+
+	first, err := list(storage.PageRequest{Limit: 2, Sort: storage.SortByCost})
+	if err != nil {
+		t.Fatalf("list first page: %v", err)
+	}
+	for name, req := range map[string]storage.PageRequest{
+		"unknown sort":    {Sort: "power"},
+		"negative limit":  {Limit: -1},
+		"garbage cursor":  {Cursor: "not a cursor"},
+		"cursor for sort": {Cursor: first.NextCursor, Sort: storage.SortByName},
+	} {
+		if _, err := list(req); !errors.Is(err, storage.ErrValidation) {
+			t.Errorf("%s: expected ErrValidation, got %v", name, err)
+		}
+	}
+}
+
+func testGameCardFilter(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	createSets(t, s, "CORE", "EXP1")
+
+	gameID := uuid.New()
+	cards := map[string]models.GameCard{
+		"dragon": {Name: "Dragon", Type: "Creature", Cost: 6, Offense: 6, Defense: 5, GameID: &gameID,
+			Colors: []string{"Red"}, Keywords: []string{"Flying", "Haste"},
+			Printings: []models.Printing{{SetCode: "CORE", CollectorNumber: "1", Rarity: models.RarityMythic}}},
+		"angel": {Name: "Angel", Type: "Creature", Cost: 4, Offense: 3, Defense: 4,
+			Colors: []string{"White", "Blue"}, Keywords: []string{"Flying"},
+			Printings: []models.Printing{
+				{SetCode: "CORE", CollectorNumber: "2", Rarity: models.RarityRare},
+				{SetCode: "EXP1", CollectorNumber: "7", Rarity: models.RarityUncommon},
+			}},
+		"bolt": {Name: "Bolt", Type: "Spell", Cost: 1, GameID: &gameID,
+			Colors:    []string{"Red"},
+			Printings: []models.Printing{{SetCode: "EXP1", CollectorNumber: "8", Rarity: models.RarityCommon}}},
+		"land": {Name: "Land", Type: "Land", IsResource: true},
+	}
+	ids := map[uuid.UUID]string{}
+	for key, card := range cards {
+		created, err := s.CreateGameCard(ctx, card)
+		if err != nil {
+			t.Fatalf("CreateGameCard: %v", err)
+		}
+		ids[created.ID] = key
+	}
+
+	yes := true
+	intPtr := func(n int) *int { return &n }
+	tests := []struct {
+		name   string
+		filter storage.GameCardFilter
+		want   []string
+	}{
+		{"none", storage.GameCardFilter{}, []string{"angel", "bolt", "dragon", "land"}},
+		{"type", storage.GameCardFilter{Type: "Creature"}, []string{"angel", "dragon"}},
+		{"cost range", storage.GameCardFilter{MinCost: intPtr(1), MaxCost: intPtr(4)}, []string{"angel", "bolt"}},
+		{"min offense", storage.GameCardFilter{MinOffense: intPtr(4)}, []string{"dragon"}},
+		{"max defense", storage.GameCardFilter{MaxDefense: intPtr(4)}, []string{"angel", "bolt", "land"}},
+		{"color", storage.GameCardFilter{Colors: []string{"Red"}}, []string{"bolt", "dragon"}},
+		{"every color", storage.GameCardFilter{Colors: []string{"White", "Blue"}}, []string{"angel"}},
+		{"keywords", storage.GameCardFilter{Keywords: []string{"Flying", "Haste"}}, []string{"dragon"}},
+		{"resource", storage.GameCardFilter{IsResource: &yes}, []string{"land"}},
+		{"combined", storage.GameCardFilter{Colors: []string{"Red"}, MinCost: intPtr(2)}, []string{"dragon"}},
+		{"set", storage.GameCardFilter{Set: "CORE"}, []string{"angel", "dragon"}},
+		{"rarity", storage.GameCardFilter{Rarity: models.RarityUncommon}, []string{"angel"}},
+		{"set and rarity", storage.GameCardFilter{Set: "EXP1", Rarity: models.RarityUncommon}, []string{"angel"}},
+		// Set and rarity must match the same printing
+		{"set and rarity apart", storage.GameCardFilter{Set: "CORE", Rarity: models.RarityUncommon}, nil},
+		{"game", storage.GameCardFilter{GameID: &gameID}, []string{"bolt", "dragon"}},
+		{"no match", storage.GameCardFilter{Type: "Artifact"}, nil},
+	}
+
+	for _, tt := range tests {
+		page, err := s.ListGameCardsPage(ctx, tt.filter, storage.PageRequest{})
+		if err != nil {
+			t.Fatalf("%s: ListGameCardsPage: %v", tt.name, err)
+		}
+		var got []string
+		for _, card := range page.Items {
+			got = append(got, ids[card.ID])
+			if card.Colors == nil || card.Keywords == nil || len(card.Printings) != len(cards[ids[card.ID]].Printings) {
+				t.Errorf("%s: expected colors, keywords and printings to be loaded, got %+v", tt.name, card)
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func testImageCardPages(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	names := map[uuid.UUID]string{}
+	created := map[uuid.UUID]time.Time{}
+	for i := range 5 {
+		card := models.ImageCard{Name: "Image " + string(rune('E'-i)), Description: "Art"}
+		createdCard, err := s.CreateImageCard(ctx, card)
+		if err != nil {
+			t.Fatalf("CreateImageCard: %v", err)
+		}
+		names[createdCard.ID] = card.Name
+		created[createdCard.ID] = createdCard.CreatedAt
+	}
+
+	list := func(req storage.PageRequest) (*storage.Page[*models.ImageCard], error) {
+		return s.ListImageCardsPage(ctx, req)
+	}
+	id := func(card *models.ImageCard) uuid.UUID { return card.ID }
+
+	for _, desc := range []bool{false, true} {
+		got := walkPages(t, storage.PageRequest{Limit: 2, Descending: desc}, id, list)
+		if want := expectedOrder(names, strings.Compare, desc); !slices.Equal(got, want) {
+			t.Errorf("by name (descending %v): expected %v, got %v", desc, want, got)
+		}
+		got = walkPages(t, storage.PageRequest{Limit: 2, Sort: storage.SortByCreatedAt, Descending: desc}, id, list)
+		if want := expectedOrder(created, time.Time.Compare, desc); !slices.Equal(got, want) {
+			t.Errorf("by created_at (descending %v): expected %v, got %v", desc, want, got)
+		}
+	}
+
+	if _, err := list(storage.PageRequest{Sort: storage.SortByCost}); !errors.Is(err, storage.ErrValidation) {
+		t.Errorf("Expected ErrValidation sorting image cards by cost, got %v", err)
+	}
+}
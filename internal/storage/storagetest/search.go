@@ -0,0 +1,123 @@
+package storagetest
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/query"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// testGameCardSearch checks that backends agree with query.Expr.Match, which
+// defines what a search means
+func testGameCardSearch(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	var cards []*models.GameCard
+	for _, card := range []models.GameCard{
+		{Name: "Goblin King", Subtitle: "Lord of the Hill", Type: "Creature", Cost: 3, Offense: 2, Defense: 2,
+			Keywords: []string{"Haste"}, Colors: []string{"Red"}},
+		{Name: "Sky Angel", Type: "Creature", Cost: 5, Offense: 4, Defense: 4,
+			Keywords: []string{"Flying", "Vigilance"}, Colors: []string{"White"}},
+		{Name: "Fire Bolt", Type: "Spell", Cost: 1, Colors: []string{"Red"}},
+		{Name: "Mountain", Type: "Land", Colors: []string{"Red"}, IsResource: true},
+		{Name: "100% Goblin_", Type: "Creature", Cost: 2, Offense: 1, Defense: 1},
+		{Name: "Élan Vital", Type: "Spell", Cost: 2, Keywords: []string{"Überlauf"}, Colors: []string{"Blue"}},
+	} {
+		created, err := s.CreateGameCard(ctx, card)
+		if err != nil {
+			t.Fatalf("CreateGameCard: %v", err)
+		}
+		cards = append(cards, created)
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].Name < cards[j].Name })
+
+	for _, input := range []string{
+		"c:red cost<=3 kw:haste o>=2 type:creature",
+		"goblin",
+		"GOBLIN KING",
+		`name="goblin king"`,
+		`"0%"`,
+		"_",
+		"sub:hill",
+		"is:resource",
+		"-is:resource c:red",
+		"c:white or t:land",
+		"t:creature (kw:flying or kw:haste)",
+		"-(c:red or c:white)",
+		"cost>1 d<4",
+		"kw!=flying",
+		"n:dragon",
+		"name:élan",
+		`n="ÉLAN VITAL"`,
+		"kw:überlauf",
+		"élan c:blue cost=2",
+		"-élan t:spell",
+		"élan or t:land",
+	} {
+		expr, err := query.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		var want []string
+		for _, card := range cards {
+			if expr.Match(card) {
+				want = append(want, card.Name)
+			}
+		}
+
+		page, err := s.SearchGameCards(ctx, expr, storage.PageRequest{})
+		if err != nil {
+			t.Errorf("SearchGameCards(%q): %v", input, err)
+			continue
+		}
+		var got []string
+		for _, card := range page.Items {
+			got = append(got, card.Name)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("SearchGameCards(%q): expected %v, got %v", input, want, got)
+		}
+	}
+
+	// Results are paged like any other list
+	expr, _ := query.Parse("c:red")
+	first, err := s.SearchGameCards(ctx, expr, storage.PageRequest{Limit: 2, Sort: storage.SortByCost})
+	if err != nil {
+		t.Fatalf("SearchGameCards: %v", err)
+	}
+	if len(first.Items) != 2 || first.Items[0].Name != "Mountain" || first.NextCursor == "" {
+		t.Fatalf("Expected the two cheapest red cards and a cursor, got %+v", first)
+	}
+	rest, err := s.SearchGameCards(ctx, expr, storage.PageRequest{Limit: 2, Sort: storage.SortByCost, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("SearchGameCards: %v", err)
+	}
+	if len(rest.Items) != 1 || rest.Items[0].Name != "Goblin King" || rest.NextCursor != "" {
+		t.Errorf("Expected the last red card on the second page, got %+v", rest)
+	}
+
+	// So are results of terms matched outside the backend's query language
+	expr, _ = query.Parse("-élan")
+	var names []string
+	request := storage.PageRequest{Limit: 2}
+	for {
+		page, err := s.SearchGameCards(ctx, expr, request)
+		if err != nil {
+			t.Fatalf("SearchGameCards: %v", err)
+		}
+		for _, card := range page.Items {
+			names = append(names, card.Name)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		request.Cursor = page.NextCursor
+	}
+	if want := []string{"100% Goblin_", "Fire Bolt", "Goblin King", "Mountain", "Sky Angel"}; !slices.Equal(names, want) {
+		t.Errorf("Expected %v across pages, got %v", want, names)
+	}
+}
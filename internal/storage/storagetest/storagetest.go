@@ -0,0 +1,1144 @@
+// Package storagetest is a conformance suite for storage.Storage
+// implementations. Every backend runs it, so the semantics handlers rely on
+// cannot drift between MockStorage and the real stores:
+//
+//   - Get, Update and Delete of a missing ID return storage.ErrNotFound
+//   - Create generates a UUID when the ID is uuid.Nil and keeps it otherwise
+//   - Create with an existing ID fails with storage.ErrConflict
+//   - storage sets the version and timestamps of game cards, image cards
+//     and decks, and Update and Delete fail with storage.ErrVersionMismatch
+//     when given a version that is no longer current
+//   - storage sets the timestamps of playing cards, card sets, game
+//     definitions and registry entries
+//   - values passed in and returned are copies, never shared with the store
+//   - paged lists are sorted stably, ties broken by ID, and following
+//     cursors visits every matching record exactly once
+//   - SearchGameCards returns exactly the cards query.Expr.Match accepts
+//   - card sets are keyed by code, game card printings must be in a stored
+//     set, and DeleteCardSet fails with storage.ErrConflict while any card
+//     is printed in the set
+//   - DeleteGameDefinition fails with storage.ErrConflict while any card
+//     belongs to the game
+//   - registry entries are keyed by name within each registry, and unknown
+//     registries fail with storage.ErrValidation
+//   - concurrent use is safe, and ModifyDeckState is atomic
+package storagetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jwebster45206/tcg-api/internal/models"
+	"github.com/jwebster45206/tcg-api/internal/storage"
+)
+
+// Factory returns an empty Storage for a single test. It should register any
+// cleanup with t.Cleanup, and may call t.Skip if the backend is unavailable.
+type Factory func(t *testing.T) storage.Storage
+
+// Run runs the conformance suite, calling newStorage once per subtest
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s storage.Storage)
+	}{
+		{"GameCards", func(t *testing.T, s storage.Storage) {
+			createSets(t, s, sampleSetCodes...)
+			testCRUD(t, s, gameCards)
+		}},
+		{"ImageCards", func(t *testing.T, s storage.Storage) { testCRUD(t, s, imageCards) }},
+		{"PlayingCards", func(t *testing.T, s storage.Storage) { testCRUD(t, s, playingCards) }},
+		{"Decks", func(t *testing.T, s storage.Storage) { testCRUD(t, s, decks) }},
+		{"DeckStates", func(t *testing.T, s storage.Storage) { testCRUD(t, s, deckStates) }},
+		{"GameDefinitions", func(t *testing.T, s storage.Storage) { testCRUD(t, s, gameDefinitions) }},
+		{"GameDefinitionInUse", testGameDefinitionInUse},
+		{"Registries", testRegistries},
+		{"GameCardListType", testGameCardListType},
+		{"CardSets", testCardSets},
+		{"GameCardPages", testGameCardPages},
+		{"GameCardFilter", testGameCardFilter},
+		{"ImageCardPages", testImageCardPages},
+		{"GameCardSearch", testGameCardSearch},
+		{"DeckOwnerFilter", testDeckOwnerFilter},
+		{"DeckFlatCards", testDeckFlatCards},
+		{"ModifyDeckState", testModifyDeckState},
+		{"ConcurrentAccess", func(t *testing.T, s storage.Storage) {
+			createSets(t, s, sampleSetCodes...)
+			testConcurrentAccess(t, s)
+		}},
+		{"ConcurrentDraws", testConcurrentDraws},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStorage(t))
+		})
+	}
+}
+
+// timestamp returns the current time at the precision every backend keeps
+func timestamp() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// callerTime is a timestamp passed to storage that it must replace
+var callerTime = time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+
+// checkCreated fails the test unless storage set both timestamps of a record
+// it just created to the same time, no earlier than before
+func checkCreated(t *testing.T, op string, createdAt, updatedAt, before time.Time) {
+	t.Helper()
+	if !createdAt.Equal(updatedAt) || !between(createdAt, before, time.Now()) {
+		t.Errorf("%s: expected a record created now, got created %v updated %v", op, createdAt, updatedAt)
+	}
+}
+
+// checkUpdated fails the test unless storage kept the CreatedAt of a record
+// it just updated and set UpdatedAt to a time no earlier than before
+func checkUpdated(t *testing.T, op string, createdAt, updatedAt, wantCreatedAt, before time.Time) {
+	t.Helper()
+	if !createdAt.Equal(wantCreatedAt) || !between(updatedAt, before, time.Now()) {
+		t.Errorf("%s: expected created %v and updated now, got created %v updated %v",
+			op, wantCreatedAt, createdAt, updatedAt)
+	}
+}
+
+// resource adapts one kind of record to the generic CRUD checks
+type resource[T any] struct {
+	// sample returns a fully populated record with a nil ID
+	sample func() T
+	// mutate changes the record, including in place inside any slices
+	mutate func(v *T)
+	id     func(v *T) *uuid.UUID
+
+	create func(ctx context.Context, s storage.Storage, v T) (*T, error)
+	get    func(ctx context.Context, s storage.Storage, id uuid.UUID) (*T, error)
+	update func(ctx context.Context, s storage.Storage, v T) (*T, error)
+	// delete passes version on to records that are versioned
+	delete func(ctx context.Context, s storage.Storage, id uuid.UUID, version int) error
+	// versioned is nil for records storage does not version, and otherwise
+	// returns the fields storage sets
+	versioned func(v *T) (version *int, createdAt, updatedAt *time.Time)
+	// stamped is set for records storage timestamps but does not version,
+	// and returns the timestamps
+	stamped func(v *T) (createdAt, updatedAt *time.Time)
+	// list is nil for records that cannot be listed
+	list func(ctx context.Context, s storage.Storage) ([]*T, error)
+}
+
+func testCRUD[T any](t *testing.T, s storage.Storage, r resource[T]) {
+	ctx := context.Background()
+
+	// Create generates an ID and stores every field
+	sample := r.sample()
+	if r.versioned != nil {
+		// Storage ignores the caller's version
+		version, _, _ := r.versioned(&sample)
+		*version = 7
+	}
+	if createdAt, updatedAt := r.timestamps(&sample); createdAt != nil {
+		// Storage ignores the caller's timestamps
+		*createdAt, *updatedAt = callerTime, callerTime
+	}
+	before := timestamp()
+	created, err := r.create(ctx, s, sample)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	id := *r.id(created)
+	if id == uuid.Nil {
+		t.Fatal("create: expected a generated ID")
+	}
+	*r.id(&sample) = id
+	if r.versioned != nil {
+		if version, _, _ := r.versioned(created); *version != 1 {
+			t.Errorf("create: expected version 1, got %d", *version)
+		}
+	}
+	if createdAt, updatedAt := r.timestamps(created); createdAt != nil {
+		checkCreated(t, "create", *createdAt, *updatedAt, before)
+	}
+	r.copyStorageFields(&sample, created)
+	assertSame(t, "create", sample, *created)
+
+	got, err := r.get(ctx, s, id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	assertSame(t, "get", sample, *got)
+
+	// Create keeps a provided ID and rejects a duplicate one
+	explicit := r.sample()
+	explicitID := uuid.New()
+	*r.id(&explicit) = explicitID
+	if created, err := r.create(ctx, s, explicit); err != nil || *r.id(created) != explicitID {
+		t.Fatalf("create with ID: got %v, err %v", created, err)
+	}
+	if _, err := r.create(ctx, s, explicit); !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("create duplicate: expected ErrConflict, got %v", err)
+	}
+
+	// Values passed in and returned are copies
+	want := clone(t, sample)
+	r.mutate(&sample)
+	r.mutate(created)
+	r.mutate(got)
+	if reread, err := r.get(ctx, s, id); err != nil {
+		t.Fatalf("get: %v", err)
+	} else {
+		assertSame(t, "get after changing returned copies", want, *reread)
+	}
+
+	// Update replaces the stored record
+	changed, err := r.get(ctx, s, id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	r.mutate(changed)
+	if createdAt, _ := r.timestamps(changed); createdAt != nil {
+		// A caller leaving out CreatedAt does not clear it
+		*createdAt = time.Time{}
+	}
+	before = timestamp()
+	updated, err := r.update(ctx, s, *changed)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if r.versioned != nil {
+		// Update moves the version on
+		version, _, _ := r.versioned(updated)
+		if oldVersion, _, _ := r.versioned(created); *version != *oldVersion+1 {
+			t.Errorf("update: expected version %d, got %d", *oldVersion+1, *version)
+		}
+	}
+	if createdAt, updatedAt := r.timestamps(updated); createdAt != nil {
+		// Update keeps CreatedAt and moves UpdatedAt on
+		oldCreatedAt, _ := r.timestamps(created)
+		checkUpdated(t, "update", *createdAt, *updatedAt, *oldCreatedAt, before)
+	}
+	r.copyStorageFields(changed, updated)
+	assertSame(t, "update", *changed, *updated)
+	if got, err := r.get(ctx, s, id); err != nil {
+		t.Fatalf("get: %v", err)
+	} else {
+		assertSame(t, "get after update", *changed, *got)
+	}
+
+	// An update that changes nothing still succeeds
+	current, err := r.update(ctx, s, *changed)
+	if err != nil {
+		t.Fatalf("no-op update: %v", err)
+	}
+
+	// Writes based on an old version fail, and change nothing
+	currentVersion := 0
+	if r.versioned != nil {
+		version, _, _ := r.versioned(current)
+		currentVersion = *version
+		stale := clone(t, *changed)
+		r.mutate(&stale)
+		if _, err := r.update(ctx, s, stale); !errors.Is(err, storage.ErrVersionMismatch) || !errors.Is(err, storage.ErrConflict) {
+			t.Errorf("update stale version: expected ErrVersionMismatch, got %v", err)
+		}
+		if err := r.delete(ctx, s, id, currentVersion-1); !errors.Is(err, storage.ErrVersionMismatch) {
+			t.Errorf("delete stale version: expected ErrVersionMismatch, got %v", err)
+		}
+		if got, err := r.get(ctx, s, id); err != nil {
+			t.Fatalf("get: %v", err)
+		} else {
+			assertSame(t, "get after stale writes", *current, *got)
+		}
+	}
+
+	if r.list != nil {
+		all, err := r.list(ctx, s)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		ids := map[uuid.UUID]bool{}
+		for _, v := range all {
+			ids[*r.id(v)] = true
+		}
+		if len(all) != 2 || !ids[id] || !ids[explicitID] {
+			t.Errorf("list: expected %s and %s, got %d records", id, explicitID, len(all))
+		}
+	}
+
+	// Missing IDs report ErrNotFound
+	missing := r.sample()
+	*r.id(&missing) = uuid.New()
+	if _, err := r.get(ctx, s, *r.id(&missing)); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("get missing: expected ErrNotFound, got %v", err)
+	}
+	if _, err := r.update(ctx, s, missing); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("update missing: expected ErrNotFound, got %v", err)
+	}
+
+	if err := r.delete(ctx, s, id, currentVersion); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := r.get(ctx, s, id); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("get deleted: expected ErrNotFound, got %v", err)
+	}
+	if err := r.delete(ctx, s, id, currentVersion); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("delete twice: expected ErrNotFound, got %v", err)
+	}
+}
+
+// timestamps returns the timestamps storage sets on v, or nil for records
+// whose timestamps the caller sets
+func (r resource[T]) timestamps(v *T) (createdAt, updatedAt *time.Time) {
+	switch {
+	case r.versioned != nil:
+		_, createdAt, updatedAt = r.versioned(v)
+	case r.stamped != nil:
+		createdAt, updatedAt = r.stamped(v)
+	}
+	return createdAt, updatedAt
+}
+
+// copyStorageFields copies the fields storage sets from src to dst
+func (r resource[T]) copyStorageFields(dst, src *T) {
+	if r.versioned != nil {
+		dstVersion, _, _ := r.versioned(dst)
+		srcVersion, _, _ := r.versioned(src)
+		*dstVersion = *srcVersion
+	}
+	if dstCreatedAt, dstUpdatedAt := r.timestamps(dst); dstCreatedAt != nil {
+		srcCreatedAt, srcUpdatedAt := r.timestamps(src)
+		*dstCreatedAt, *dstUpdatedAt = *srcCreatedAt, *srcUpdatedAt
+	}
+}
+
+// between reports whether t is within [from, to]
+func between(t, from, to time.Time) bool {
+	return !t.Before(from) && !t.After(to)
+}
+
+// clone returns a deep copy of v made through its JSON form
+func clone[T any](t *testing.T, v T) T {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c T
+	if err := json.Unmarshal(data, &c); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// assertSame fails the test if want and got differ in their JSON form, which
+// compares times by instant and treats nil and empty slices alike where the
+// model's JSON does
+func assertSame[T any](t *testing.T, op string, want, got T) {
+	t.Helper()
+
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(wantJSON) != string(gotJSON) {
+		t.Errorf("%s: records differ\nwant %s\ngot  %s", op, wantJSON, gotJSON)
+	}
+}
+
+// sampleSetCodes are the sets sample game cards are printed in
+var sampleSetCodes = []string{"CORE", "PROMO", "EXP1"}
+
+// createSets stores a card set for each code, named after the code
+func createSets(t *testing.T, s storage.Storage, codes ...string) {
+	t.Helper()
+	for _, code := range codes {
+		if _, err := s.CreateCardSet(context.Background(), models.CardSet{Code: code, Name: code}); err != nil {
+			t.Fatalf("CreateCardSet: %v", err)
+		}
+	}
+}
+
+// sampleGameID is the game every sample game card belongs to
+var sampleGameID = uuid.MustParse("0d9b3a52-8f0e-4a8e-b7a4-3f1c6d2e9a10")
+
+var gameCards = resource[models.GameCard]{
+	sample: func() models.GameCard {
+		now := timestamp()
+		gameID := sampleGameID
+		return models.GameCard{
+			Name:          "Ancient Wyrm",
+			Subtitle:      "Elder of the Peaks",
+			Cost:          7,
+			Type:          "Creature",
+			Offense:       6,
+			Defense:       5,
+			Keywords:      []string{"Flying", "Trample"},
+			Colors:        []string{"Red", "Green"},
+			IsResource:    false,
+			FrontImageURL: "https://example.com/wyrm-front.png",
+			BackImageURL:  "https://example.com/back.png",
+			Printings: []models.Printing{
+				{SetCode: "CORE", CollectorNumber: "101", Rarity: models.RarityRare},
+				{SetCode: "PROMO", CollectorNumber: "P7", Rarity: models.RaritySpecial,
+					FrontImageURL: "https://example.com/wyrm-promo.png"},
+			},
+			GameID:    &gameID,
+			Stats:     map[string]any{"loyalty": 4, "flavor": "It remembers", "legendary": true},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	},
+	mutate: func(c *models.GameCard) {
+		c.Name += " (Revised)"
+		c.Cost++
+		c.IsResource = !c.IsResource
+		c.Keywords[0] = "Haste"
+		c.Colors = c.Colors[:1]
+		c.Printings[0].Rarity = models.RarityMythic
+		c.Printings = append(c.Printings, models.Printing{SetCode: "EXP1", CollectorNumber: "12", Rarity: models.RarityUncommon})
+		*c.GameID = uuid.New()
+		c.Stats["loyalty"] = 5
+		delete(c.Stats, "flavor")
+		c.UpdatedAt = c.UpdatedAt.Add(time.Minute)
+	},
+	id: func(c *models.GameCard) *uuid.UUID { return &c.ID },
+	versioned: func(c *models.GameCard) (*int, *time.Time, *time.Time) {
+		return &c.Version, &c.CreatedAt, &c.UpdatedAt
+	},
+	create: func(ctx context.Context, s storage.Storage, c models.GameCard) (*models.GameCard, error) {
+		return s.CreateGameCard(ctx, c)
+	},
+	get: func(ctx context.Context, s storage.Storage, id uuid.UUID) (*models.GameCard, error) {
+		return s.GetGameCard(ctx, id)
+	},
+	update: func(ctx context.Context, s storage.Storage, c models.GameCard) (*models.GameCard, error) {
+		return s.UpdateGameCard(ctx, c)
+	},
+	delete: func(ctx context.Context, s storage.Storage, id uuid.UUID, version int) error {
+		return s.DeleteGameCard(ctx, id, version)
+	},
+	list: func(ctx context.Context, s storage.Storage) ([]*models.GameCard, error) {
+		return s.ListGameCards(ctx, "gamecard")
+	},
+}
+
+var imageCards = resource[models.ImageCard]{
+	sample: func() models.ImageCard {
+		now := timestamp()
+		return models.ImageCard{
+			Name:          "Sunset",
+			Description:   "An orange sky over the sea",
+			FrontImageURL: "https://example.com/sunset.png",
+			BackImageURL:  "https://example.com/back.png",
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+	},
+	mutate: func(c *models.ImageCard) {
+		c.Description = "A purple sky over the sea"
+		c.UpdatedAt = c.UpdatedAt.Add(time.Minute)
+	},
+	id: func(c *models.ImageCard) *uuid.UUID { return &c.ID },
+	versioned: func(c *models.ImageCard) (*int, *time.Time, *time.Time) {
+		return &c.Version, &c.CreatedAt, &c.UpdatedAt
+	},
+	create: func(ctx context.Context, s storage.Storage, c models.ImageCard) (*models.ImageCard, error) {
+		return s.CreateImageCard(ctx, c)
+	},
+	get: func(ctx context.Context, s storage.Storage, id uuid.UUID) (*models.ImageCard, error) {
+		return s.GetImageCard(ctx, id)
+	},
+	update: func(ctx context.Context, s storage.Storage, c models.ImageCard) (*models.ImageCard, error) {
+		return s.UpdateImageCard(ctx, c)
+	},
+	delete: func(ctx context.Context, s storage.Storage, id uuid.UUID, version int) error {
+		return s.DeleteImageCard(ctx, id, version)
+	},
+	list: func(ctx context.Context, s storage.Storage) ([]*models.ImageCard, error) {
+		return s.ListImageCards(ctx)
+	},
+}
+
+var playingCards = resource[models.PlayingCard]{
+	sample: func() models.PlayingCard {
+		now := timestamp()
+		return models.PlayingCard{
+			Suite:         models.SuiteSpades,
+			Value:         1,
+			FrontImageURL: "https://example.com/ace-of-spades.png",
+			BackImageURL:  "https://example.com/back.png",
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+	},
+	mutate: func(c *models.PlayingCard) {
+		c.Suite = models.SuiteHearts
+		c.Value = 12
+	},
+	id: func(c *models.PlayingCard) *uuid.UUID { return &c.ID },
+	stamped: func(c *models.PlayingCard) (*time.Time, *time.Time) {
+		return &c.CreatedAt, &c.UpdatedAt
+	},
+	create: func(ctx context.Context, s storage.Storage, c models.PlayingCard) (*models.PlayingCard, error) {
+		return s.CreatePlayingCard(ctx, c)
+	},
+	get: func(ctx context.Context, s storage.Storage, id uuid.UUID) (*models.PlayingCard, error) {
+		return s.GetPlayingCard(ctx, id)
+	},
+	update: func(ctx context.Context, s storage.Storage, c models.PlayingCard) (*models.PlayingCard, error) {
+		return s.UpdatePlayingCard(ctx, c)
+	},
+	delete: func(ctx context.Context, s storage.Storage, id uuid.UUID, _ int) error {
+		return s.DeletePlayingCard(ctx, id)
+	},
+	list: func(ctx context.Context, s storage.Storage) ([]*models.PlayingCard, error) {
+		return s.ListPlayingCards(ctx)
+	},
+}
+
+var gameDefinitions = resource[models.GameDefinition]{
+	sample: func() models.GameDefinition {
+		now := timestamp()
+		lo, hi := 0, 10
+		return models.GameDefinition{
+			Name:      "Skirmish",
+			CardTypes: []string{"Creature", "Spell", "Land"},
+			Stats: []models.StatDefinition{
+				{Name: "cost", Type: models.StatTypeInteger, Min: &lo, Max: &hi},
+				{Name: "loyalty", Type: models.StatTypeInteger, Min: &lo},
+				{Name: "flavor", Type: models.StatTypeString},
+			},
+			Colors:    []string{"Red", "Green", "Blue"},
+			Keywords:  []string{"Flying", "Haste"},
+			DeckRules: models.DeckRules{MinCards: 40, MaxCards: 60, MaxCopies: 3},
+			Formats: []models.Format{
+				{Name: "Singleton", MaxCopies: 1, Banned: []uuid.UUID{sampleDeckCards[0]}},
+				{Name: "Core", Sets: []string{"CORE"}, Colors: []string{"Red"}, MinResourceRatio: 0.3},
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	},
+	mutate: func(g *models.GameDefinition) {
+		g.Name = "Skirmish Second Edition"
+		g.CardTypes[0] = "Unit"
+		*g.Stats[0].Max = 12
+		g.Stats = append(g.Stats, models.StatDefinition{Name: "legendary", Type: models.StatTypeBoolean, Required: true})
+		g.Colors = g.Colors[:2]
+		g.Keywords[1] = "Trample"
+		g.DeckRules.MaxCopies = 4
+		g.Formats[0].Banned[0] = uuid.New()
+		g.Formats[1].Sets = append(g.Formats[1].Sets, "EXP1")
+		g.UpdatedAt = g.UpdatedAt.Add(time.Minute)
+	},
+	id: func(g *models.GameDefinition) *uuid.UUID { return &g.ID },
+	stamped: func(g *models.GameDefinition) (*time.Time, *time.Time) {
+		return &g.CreatedAt, &g.UpdatedAt
+	},
+	create: func(ctx context.Context, s storage.Storage, g models.GameDefinition) (*models.GameDefinition, error) {
+		return s.CreateGameDefinition(ctx, g)
+	},
+	get: func(ctx context.Context, s storage.Storage, id uuid.UUID) (*models.GameDefinition, error) {
+		return s.GetGameDefinition(ctx, id)
+	},
+	update: func(ctx context.Context, s storage.Storage, g models.GameDefinition) (*models.GameDefinition, error) {
+		return s.UpdateGameDefinition(ctx, g)
+	},
+	delete: func(ctx context.Context, s storage.Storage, id uuid.UUID, _ int) error {
+		return s.DeleteGameDefinition(ctx, id)
+	},
+	list: func(ctx context.Context, s storage.Storage) ([]*models.GameDefinition, error) {
+		return s.ListGameDefinitions(ctx)
+	},
+}
+
+// sampleDeckCards is shared by every sample deck, so samples compare equal
+var sampleDeckCards = []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+var decks = resource[models.Deck]{
+	sample: func() models.Deck {
+		now := timestamp()
+		ownerID := uuid.MustParse("6f1c2a1e-5d55-4c4e-9a43-2d0d0b1f7c11")
+		sleeve := "https://example.com/sleeve.png"
+		back := "https://example.com/back.png"
+		return models.Deck{
+			Name:           "Burn",
+			OwnerID:        &ownerID,
+			SleeveImageURL: &sleeve,
+			BackImageURL:   &back,
+			Entries: []models.DeckEntry{
+				{CardID: sampleDeckCards[0], CardType: models.CardTypeGameCard, Quantity: 2, Section: models.SectionMain},
+				{CardID: sampleDeckCards[1], CardType: models.CardTypeGameCard, Quantity: 1, Section: models.SectionMain},
+				{CardID: sampleDeckCards[0], CardType: models.CardTypeGameCard, Quantity: 1, Section: models.SectionSideboard},
+				{CardID: sampleDeckCards[2], CardType: models.CardTypeImageCard, Quantity: 1, Section: models.SectionCommander},
+			},
+			Cards:     []uuid.UUID{sampleDeckCards[0], sampleDeckCards[0], sampleDeckCards[1]},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	},
+	mutate: func(d *models.Deck) {
+		d.Name = "Big Burn"
+		*d.OwnerID = uuid.New()
+		*d.SleeveImageURL = "https://example.com/other-sleeve.png"
+		d.BackImageURL = nil
+		d.Entries[0].Quantity = 4
+		d.Entries[2].Section = models.SectionMaybeboard
+		d.Entries = append(d.Entries[:3], models.DeckEntry{CardID: uuid.New(), CardType: models.CardTypePlayingCard,
+			Quantity: 1, Section: models.SectionMain})
+		d.Normalize()
+	},
+	id: func(d *models.Deck) *uuid.UUID { return &d.ID },
+	versioned: func(d *models.Deck) (*int, *time.Time, *time.Time) {
+		return &d.Version, &d.CreatedAt, &d.UpdatedAt
+	},
+	create: func(ctx context.Context, s storage.Storage, d models.Deck) (*models.Deck, error) {
+		return s.CreateDeck(ctx, d)
+	},
+	get: func(ctx context.Context, s storage.Storage, id uuid.UUID) (*models.Deck, error) {
+		return s.GetDeck(ctx, id)
+	},
+	update: func(ctx context.Context, s storage.Storage, d models.Deck) (*models.Deck, error) {
+		return s.UpdateDeck(ctx, d)
+	},
+	delete: func(ctx context.Context, s storage.Storage, id uuid.UUID, version int) error {
+		return s.DeleteDeck(ctx, id, version)
+	},
+	list: func(ctx context.Context, s storage.Storage) ([]*models.Deck, error) {
+		return s.ListDecks(ctx, nil)
+	},
+}
+
+// sampleStateDeck is the deck every sample deck state is created from
+var sampleStateDeck = models.Deck{
+	ID:    uuid.New(),
+	Cards: []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()},
+}
+
+var deckStates = resource[models.DeckState]{
+	sample: func() models.DeckState {
+		now := timestamp()
+		state := models.NewDeckState(sampleStateDeck)
+		if err := state.Shuffle("storagetest"); err != nil {
+			panic(err)
+		}
+		state.LastShuffle.ShuffledAt = now
+		state.Draw(2)
+		state.DiscardCard(state.Drawn[0])
+		state.CreatedAt = now
+		state.UpdatedAt = now
+		return *state
+	},
+	mutate: func(s *models.DeckState) {
+		s.DrawPile[0], s.DrawPile[1] = s.DrawPile[1], s.DrawPile[0]
+		s.LastShuffle.Input[0] = uuid.New()
+		if err := s.Commit("storagetest-server-seed"); err != nil {
+			panic(err)
+		}
+		s.UpdatedAt = s.UpdatedAt.Add(time.Minute)
+	},
+	id: func(s *models.DeckState) *uuid.UUID { return &s.ID },
+	create: func(ctx context.Context, s storage.Storage, state models.DeckState) (*models.DeckState, error) {
+		return s.CreateDeckState(ctx, state)
+	},
+	get: func(ctx context.Context, s storage.Storage, id uuid.UUID) (*models.DeckState, error) {
+		return s.GetDeckState(ctx, id)
+	},
+	update: func(ctx context.Context, s storage.Storage, state models.DeckState) (*models.DeckState, error) {
+		return s.UpdateDeckState(ctx, state)
+	},
+	delete: func(ctx context.Context, s storage.Storage, id uuid.UUID, _ int) error {
+		return s.DeleteDeckState(ctx, id)
+	},
+}
+
+func testGameCardListType(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	cards, err := s.ListGameCards(ctx, "gamecard")
+	if err != nil {
+		t.Fatalf("ListGameCards: %v", err)
+	}
+	if cards == nil || len(cards) != 0 {
+		t.Errorf("Expected an empty, non-nil list from empty storage, got %#v", cards)
+	}
+	if _, err := s.ListGameCards(ctx, "unsupported"); !errors.Is(err, storage.ErrValidation) {
+		t.Errorf("Expected ErrValidation listing an unsupported card type, got %v", err)
+	}
+}
+
+// testCardSets covers sets, which are keyed by code rather than a UUID
+func testCardSets(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	// Storage ignores the caller's timestamps
+	sets := []models.CardSet{
+		{Code: "EXP1", Name: "First Expansion", ReleaseDate: "2025-03-01", Size: 150, CreatedAt: callerTime, UpdatedAt: callerTime},
+		{Code: "CORE", Name: "Core Set", ReleaseDate: "2024-09-15", Size: 250, CreatedAt: callerTime, UpdatedAt: callerTime},
+	}
+	for i := range sets {
+		before := timestamp()
+		created, err := s.CreateCardSet(ctx, sets[i])
+		if err != nil {
+			t.Fatalf("CreateCardSet: %v", err)
+		}
+		checkCreated(t, "create", created.CreatedAt, created.UpdatedAt, before)
+		sets[i].CreatedAt, sets[i].UpdatedAt = created.CreatedAt, created.UpdatedAt
+		assertSame(t, "create", sets[i], *created)
+	}
+	if _, err := s.CreateCardSet(ctx, sets[0]); !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("create duplicate: expected ErrConflict, got %v", err)
+	}
+
+	all, err := s.ListCardSets(ctx)
+	if err != nil {
+		t.Fatalf("ListCardSets: %v", err)
+	}
+	if len(all) != 2 || all[0].Code != "CORE" || all[1].Code != "EXP1" {
+		t.Errorf("Expected sets ordered by code, got %+v", all)
+	}
+
+	// Update keeps CreatedAt, even when the caller leaves it out
+	changed := sets[0]
+	changed.Name = "Expansion One"
+	changed.Size++
+	changed.CreatedAt, changed.UpdatedAt = time.Time{}, callerTime
+	before := timestamp()
+	if updated, err := s.UpdateCardSet(ctx, changed); err != nil {
+		t.Fatalf("UpdateCardSet: %v", err)
+	} else {
+		checkUpdated(t, "update", updated.CreatedAt, updated.UpdatedAt, sets[0].CreatedAt, before)
+		changed.CreatedAt, changed.UpdatedAt = updated.CreatedAt, updated.UpdatedAt
+		assertSame(t, "update", changed, *updated)
+	}
+	if got, err := s.GetCardSet(ctx, changed.Code); err != nil {
+		t.Fatalf("GetCardSet: %v", err)
+	} else {
+		assertSame(t, "get after update", changed, *got)
+	}
+
+	// Missing codes report ErrNotFound
+	if _, err := s.GetCardSet(ctx, "NOPE"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("get missing: expected ErrNotFound, got %v", err)
+	}
+	if _, err := s.UpdateCardSet(ctx, models.CardSet{Code: "NOPE"}); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("update missing: expected ErrNotFound, got %v", err)
+	}
+	if err := s.DeleteCardSet(ctx, "NOPE"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("delete missing: expected ErrNotFound, got %v", err)
+	}
+
+	// Printings must be in a stored set
+	unknown := models.Printing{SetCode: "NOPE", CollectorNumber: "1", Rarity: models.RarityRare}
+	if _, err := s.CreateGameCard(ctx, models.GameCard{Name: "Wyrm",
+		Printings: []models.Printing{unknown}}); !errors.Is(err, storage.ErrValidation) {
+		t.Errorf("create printed in missing set: expected ErrValidation, got %v", err)
+	}
+
+	// A set cannot be deleted while cards are printed in it
+	card, err := s.CreateGameCard(ctx, models.GameCard{Name: "Wyrm",
+		Printings: []models.Printing{{SetCode: "CORE", CollectorNumber: "1", Rarity: models.RarityRare}}})
+	if err != nil {
+		t.Fatalf("CreateGameCard: %v", err)
+	}
+	withUnknown := *card
+	withUnknown.Printings = append(withUnknown.Printings, unknown)
+	if _, err := s.UpdateGameCard(ctx, withUnknown); !errors.Is(err, storage.ErrValidation) {
+		t.Errorf("update printed in missing set: expected ErrValidation, got %v", err)
+	}
+	if got, err := s.GetGameCard(ctx, card.ID); err != nil || len(got.Printings) != 1 {
+		t.Errorf("Expected a failed update to keep the printings, got %+v, err %v", got, err)
+	}
+	if err := s.DeleteCardSet(ctx, "CORE"); !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("delete in use: expected ErrConflict, got %v", err)
+	}
+	if err := s.DeleteGameCard(ctx, card.ID, 0); err != nil {
+		t.Fatalf("DeleteGameCard: %v", err)
+	}
+	if err := s.DeleteCardSet(ctx, "CORE"); err != nil {
+		t.Errorf("delete: %v", err)
+	}
+	if _, err := s.GetCardSet(ctx, "CORE"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("get deleted: expected ErrNotFound, got %v", err)
+	}
+}
+
+// testGameDefinitionInUse checks that a game cannot be deleted while cards
+// belong to it
+func testGameDefinitionInUse(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	game, err := s.CreateGameDefinition(ctx, gameDefinitions.sample())
+	if err != nil {
+		t.Fatalf("CreateGameDefinition: %v", err)
+	}
+	card, err := s.CreateGameCard(ctx, models.GameCard{Name: "Wyrm", GameID: &game.ID})
+	if err != nil {
+		t.Fatalf("CreateGameCard: %v", err)
+	}
+	if err := s.DeleteGameDefinition(ctx, game.ID); !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("delete in use: expected ErrConflict, got %v", err)
+	}
+
+	// Moving the card to no game frees the definition
+	card.GameID = nil
+	if _, err := s.UpdateGameCard(ctx, *card); err != nil {
+		t.Fatalf("UpdateGameCard: %v", err)
+	}
+	if err := s.DeleteGameDefinition(ctx, game.ID); err != nil {
+		t.Errorf("delete: %v", err)
+	}
+}
+
+// testRegistries covers the keyword and color registries, which are keyed
+// by name rather than a UUID
+func testRegistries(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	// Storage ignores the caller's timestamps
+	flying := models.RegistryEntry{Name: "Flying", Aliases: []string{"flyer", "flier"},
+		ReminderText: "Can only be blocked by creatures with flying.",
+		IconURL:      "https://example.com/flying.svg", CreatedAt: callerTime, UpdatedAt: callerTime}
+	haste := models.RegistryEntry{Name: "Haste", Aliases: []string{}, CreatedAt: callerTime, UpdatedAt: callerTime}
+	for _, entry := range []*models.RegistryEntry{&haste, &flying} {
+		before := timestamp()
+		created, err := s.CreateRegistryEntry(ctx, models.RegistryKeywords, *entry)
+		if err != nil {
+			t.Fatalf("CreateRegistryEntry: %v", err)
+		}
+		checkCreated(t, "create", created.CreatedAt, created.UpdatedAt, before)
+		entry.CreatedAt, entry.UpdatedAt = created.CreatedAt, created.UpdatedAt
+		assertSame(t, "create", *entry, *created)
+	}
+	if _, err := s.CreateRegistryEntry(ctx, models.RegistryKeywords, haste); !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("create duplicate: expected ErrConflict, got %v", err)
+	}
+
+	// Registries are separate, so a color may share a keyword's name
+	red := models.RegistryEntry{Name: "Haste", Aliases: []string{"R"}}
+	if _, err := s.CreateRegistryEntry(ctx, models.RegistryColors, red); err != nil {
+		t.Fatalf("CreateRegistryEntry in another registry: %v", err)
+	}
+
+	all, err := s.ListRegistryEntries(ctx, models.RegistryKeywords)
+	if err != nil {
+		t.Fatalf("ListRegistryEntries: %v", err)
+	}
+	if len(all) != 2 || all[0].Name != "Flying" || all[1].Name != "Haste" {
+		t.Errorf("Expected keywords ordered by name, got %+v", all)
+	}
+
+	// Values passed in and returned are copies
+	want := clone(t, flying)
+	flying.Aliases[0] = "changed"
+	all[0].Aliases[1] = "changed"
+	if got, err := s.GetRegistryEntry(ctx, models.RegistryKeywords, "Flying"); err != nil {
+		t.Fatalf("GetRegistryEntry: %v", err)
+	} else {
+		assertSame(t, "get after changing copies", want, *got)
+	}
+
+	// Update keeps CreatedAt, even when the caller leaves it out
+	changed := clone(t, want)
+	changed.ReminderText = "Evasion."
+	changed.Aliases = changed.Aliases[:1]
+	changed.CreatedAt, changed.UpdatedAt = time.Time{}, callerTime
+	before := timestamp()
+	if updated, err := s.UpdateRegistryEntry(ctx, models.RegistryKeywords, changed); err != nil {
+		t.Fatalf("UpdateRegistryEntry: %v", err)
+	} else {
+		checkUpdated(t, "update", updated.CreatedAt, updated.UpdatedAt, want.CreatedAt, before)
+		changed.CreatedAt, changed.UpdatedAt = updated.CreatedAt, updated.UpdatedAt
+		assertSame(t, "update", changed, *updated)
+	}
+	if got, err := s.GetRegistryEntry(ctx, models.RegistryKeywords, "Flying"); err != nil {
+		t.Fatalf("GetRegistryEntry: %v", err)
+	} else {
+		assertSame(t, "get after update", changed, *got)
+	}
+
+	// Missing names report ErrNotFound
+	if _, err := s.GetRegistryEntry(ctx, models.RegistryKeywords, "Trample"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("get missing: expected ErrNotFound, got %v", err)
+	}
+	if _, err := s.UpdateRegistryEntry(ctx, models.RegistryKeywords, models.RegistryEntry{Name: "Trample"}); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("update missing: expected ErrNotFound, got %v", err)
+	}
+
+	if err := s.DeleteRegistryEntry(ctx, models.RegistryKeywords, "Haste"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := s.DeleteRegistryEntry(ctx, models.RegistryKeywords, "Haste"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("delete twice: expected ErrNotFound, got %v", err)
+	}
+	if _, err := s.GetRegistryEntry(ctx, models.RegistryColors, "Haste"); err != nil {
+		t.Errorf("Expected deleting a keyword to leave the color of the same name, got %v", err)
+	}
+
+	if _, err := s.ListRegistryEntries(ctx, "rarities"); !errors.Is(err, storage.ErrValidation) {
+		t.Errorf("list unknown registry: expected ErrValidation, got %v", err)
+	}
+	if _, err := s.CreateRegistryEntry(ctx, "rarities", haste); !errors.Is(err, storage.ErrValidation) {
+		t.Errorf("create in unknown registry: expected ErrValidation, got %v", err)
+	}
+}
+
+func testDeckOwnerFilter(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	owner := uuid.New()
+	other := uuid.New()
+	for _, ownerID := range []*uuid.UUID{&owner, &owner, &other, nil} {
+		if _, err := s.CreateDeck(ctx, models.Deck{Name: "Deck", OwnerID: ownerID}); err != nil {
+			t.Fatalf("CreateDeck: %v", err)
+		}
+	}
+
+	owned, err := s.ListDecks(ctx, &owner)
+	if err != nil {
+		t.Fatalf("ListDecks: %v", err)
+	}
+	if len(owned) != 2 {
+		t.Errorf("Expected 2 decks for the owner, got %d", len(owned))
+	}
+	for _, deck := range owned {
+		if deck.OwnerID == nil || *deck.OwnerID != owner {
+			t.Errorf("Expected only the owner's decks, got owner %v", deck.OwnerID)
+		}
+	}
+
+	unowned := uuid.New()
+	if none, err := s.ListDecks(ctx, &unowned); err != nil || len(none) != 0 {
+		t.Errorf("Expected no decks for an unknown owner, got %d, err %v", len(none), err)
+	}
+	if all, err := s.ListDecks(ctx, nil); err != nil || len(all) != 4 {
+		t.Errorf("Expected 4 decks without a filter, got %d, err %v", len(all), err)
+	}
+}
+
+// testDeckFlatCards checks decks written as a flat list of card IDs, as
+// clients did before entries
+func testDeckFlatCards(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	cards := []uuid.UUID{sampleDeckCards[1], sampleDeckCards[0], sampleDeckCards[1]}
+	created, err := s.CreateDeck(ctx, models.Deck{Name: "Flat", Cards: cards})
+	if err != nil {
+		t.Fatalf("CreateDeck: %v", err)
+	}
+	want := []models.DeckEntry{
+		{CardID: sampleDeckCards[1], Quantity: 2, Section: models.SectionMain},
+		{CardID: sampleDeckCards[0], Quantity: 1, Section: models.SectionMain},
+	}
+	got, err := s.GetDeck(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetDeck: %v", err)
+	}
+	assertSame(t, "entries from cards", want, got.Entries)
+	assertSame(t, "cards", []uuid.UUID{sampleDeckCards[1], sampleDeckCards[1], sampleDeckCards[0]}, got.Cards)
+
+	// A flat update replaces the main section and keeps the others
+	got.AddCard(sampleDeckCards[2], models.CardTypeGameCard, models.SectionSideboard, 2)
+	if got, err = s.UpdateDeck(ctx, *got); err != nil {
+		t.Fatalf("UpdateDeck: %v", err)
+	}
+	got.Entries = nil
+	got.Cards = []uuid.UUID{sampleDeckCards[0]}
+	updated, err := s.UpdateDeck(ctx, *got)
+	if err != nil {
+		t.Fatalf("UpdateDeck: %v", err)
+	}
+	want = []models.DeckEntry{
+		{CardID: sampleDeckCards[0], Quantity: 1, Section: models.SectionMain},
+	}
+	assertSame(t, "entries after a flat update", want, updated.Entries)
+}
+
+func testModifyDeckState(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	created, err := s.CreateDeckState(ctx, deckStates.sample())
+	if err != nil {
+		t.Fatalf("CreateDeckState: %v", err)
+	}
+
+	// A successful change is saved and returned
+	modified, err := s.ModifyDeckState(ctx, created.ID, func(state *models.DeckState) error {
+		state.Draw(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ModifyDeckState: %v", err)
+	}
+	stored, err := s.GetDeckState(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetDeckState: %v", err)
+	}
+	if len(stored.DrawPile) != len(created.DrawPile)-1 {
+		t.Errorf("Expected the draw to be saved, got %d cards left", len(stored.DrawPile))
+	}
+	assertSame(t, "ModifyDeckState", *stored, *modified)
+
+	// The returned state is a copy
+	modified.DrawPile[0] = uuid.New()
+	if reread, err := s.GetDeckState(ctx, created.ID); err != nil {
+		t.Fatalf("GetDeckState: %v", err)
+	} else {
+		assertSame(t, "get after changing the returned copy", *stored, *reread)
+	}
+
+	// A failed change is not saved and its error is returned
+	errRejected := errors.New("rejected")
+	_, err = s.ModifyDeckState(ctx, created.ID, func(state *models.DeckState) error {
+		state.Draw(len(state.DrawPile))
+		return errRejected
+	})
+	if !errors.Is(err, errRejected) {
+		t.Errorf("Expected the callback's error, got %v", err)
+	}
+	if reread, err := s.GetDeckState(ctx, created.ID); err != nil {
+		t.Fatalf("GetDeckState: %v", err)
+	} else {
+		assertSame(t, "get after a rejected change", *stored, *reread)
+	}
+
+	_, err = s.ModifyDeckState(ctx, uuid.New(), func(*models.DeckState) error {
+		t.Error("Callback called for a missing deck state")
+		return nil
+	})
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing deck state, got %v", err)
+	}
+}
+
+// testConcurrentAccess mixes reads and writes of every kind from several
+// goroutines. Run it with -race.
+func testConcurrentAccess(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	const workers = 8
+	const rounds = 5
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				if err := concurrentRound(ctx, s, fmt.Sprintf("worker %d round %d", w, i)); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	cards, err := s.ListGameCards(ctx, "gamecard")
+	if err != nil || len(cards) != workers*rounds {
+		t.Errorf("Expected %d game cards, got %d, err %v", workers*rounds, len(cards), err)
+	}
+	allDecks, err := s.ListDecks(ctx, nil)
+	if err != nil || len(allDecks) != workers*rounds {
+		t.Errorf("Expected %d decks, got %d, err %v", workers*rounds, len(allDecks), err)
+	}
+}
+
+// concurrentRound creates, reads, updates and lists records
+func concurrentRound(ctx context.Context, s storage.Storage, name string) error {
+	card := gameCards.sample()
+	card.Name = name
+	created, err := s.CreateGameCard(ctx, card)
+	if err != nil {
+		return fmt.Errorf("%s: CreateGameCard: %w", name, err)
+	}
+	created.Keywords = append(created.Keywords, "Vigilance")
+	if _, err := s.UpdateGameCard(ctx, *created); err != nil {
+		return fmt.Errorf("%s: UpdateGameCard: %w", name, err)
+	}
+	got, err := s.GetGameCard(ctx, created.ID)
+	if err != nil {
+		return fmt.Errorf("%s: GetGameCard: %w", name, err)
+	}
+	if got.Name != name || len(got.Keywords) != 3 {
+		return fmt.Errorf("%s: read back %+v", name, got)
+	}
+	if _, err := s.ListGameCards(ctx, "gamecard"); err != nil {
+		return fmt.Errorf("%s: ListGameCards: %w", name, err)
+	}
+
+	deck, err := s.CreateDeck(ctx, models.Deck{Name: name, Cards: []uuid.UUID{created.ID}})
+	if err != nil {
+		return fmt.Errorf("%s: CreateDeck: %w", name, err)
+	}
+	if _, err := s.ListDecks(ctx, nil); err != nil {
+		return fmt.Errorf("%s: ListDecks: %w", name, err)
+	}
+
+	state, err := s.CreateDeckState(ctx, *models.NewDeckState(*deck))
+	if err != nil {
+		return fmt.Errorf("%s: CreateDeckState: %w", name, err)
+	}
+	if err := s.DeleteDeckState(ctx, state.ID); err != nil {
+		return fmt.Errorf("%s: DeleteDeckState: %w", name, err)
+	}
+	return nil
+}
+
+// testConcurrentDraws draws from one deck state in parallel; every card must
+// be dealt exactly once
+func testConcurrentDraws(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	deck := models.Deck{ID: uuid.New()}
+	for i := 0; i < 24; i++ {
+		deck.Cards = append(deck.Cards, uuid.New())
+	}
+	state, err := s.CreateDeckState(ctx, *models.NewDeckState(deck))
+	if err != nil {
+		t.Fatalf("CreateDeckState: %v", err)
+	}
+
+	var mu sync.Mutex
+	dealt := make(map[uuid.UUID]int)
+	var wg sync.WaitGroup
+	for i := 0; i < len(deck.Cards); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var drawn []uuid.UUID
+			_, err := s.ModifyDeckState(ctx, state.ID, func(state *models.DeckState) error {
+				drawn = state.Draw(1)
+				return nil
+			})
+			if err != nil {
+				t.Errorf("ModifyDeckState: %v", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range drawn {
+				dealt[id]++
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(dealt) != len(deck.Cards) {
+		t.Errorf("Expected %d distinct cards dealt, got %d", len(deck.Cards), len(dealt))
+	}
+	for id, count := range dealt {
+		if count != 1 {
+			t.Errorf("Card %s dealt %d times", id, count)
+		}
+	}
+}